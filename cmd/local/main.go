@@ -2,16 +2,34 @@ package main
 
 import (
 	"context"
+	"encoding/json"
+	"errors"
 	"flag"
 	"fmt"
+	"gasinsight/internal/analysis"
+	"gasinsight/internal/api"
+	"gasinsight/internal/chart"
+	"gasinsight/internal/config"
 	"gasinsight/internal/database"
 	"gasinsight/internal/detect"
-	services "gasinsight/internal/detect"
+	"gasinsight/internal/eval"
 	fetcher "gasinsight/internal/fetch"
+	"gasinsight/internal/forecast"
 	model "gasinsight/internal/model"
+	"gasinsight/internal/notify"
+	"gasinsight/internal/pricing"
+	"gasinsight/internal/prompt"
+	"gasinsight/internal/report"
+	"gasinsight/internal/timeseries"
+	"gasinsight/internal/transfer"
 	"log"
+	"math"
 	"os"
+	"path/filepath"
+	"sort"
+	"strings"
 	"time"
+	"unicode/utf8"
 
 	"github.com/joho/godotenv"
 )
@@ -22,48 +40,285 @@ func main() {
 		log.Println("⚠️  .envファイルが見つかりません。環境変数を直接使用します。")
 	}
 
+	// 設定ファイルで変更できるフラグのデフォルトは設定のデフォルト値と同じ
+	defaults := config.Default()
+
 	mode := flag.String("mode", "fetch", "モード")
-	dbPath := flag.String("db", "./data/gasinsight.db", "DBパス")
-	useScraping := flag.Bool("scrape", false, "スクレイピングを使用")
-	useMock := flag.Bool("mock", true, "モック使用")
+	configPath := flag.String("config", "", "設定ファイル（省略時は環境変数GASINSIGHT_CONFIG、なければ "+config.DefaultPath+" があれば使用）")
+	dbPath := flag.String("db", defaults.Database.Path, "DBパスまたはDSN（postgres://... ならPostgreSQL）")
+	useScraping := flag.Bool("scrape", defaults.Sources.Scrape, "スクレイピングを使用")
+	useMock := flag.Bool("mock", defaults.Sources.Mock, "モック使用")
 	useMockAnalysis := flag.Bool("mock-analysis", true, "モック分析を使用（Gemini APIの代わり）")
 	detectChange := flag.Bool("detect", true, "変動検知を有効化")
 	mockDate := flag.String("mock-date", "", "モックデータの日付 (例: 2025-11-06)")
+	date := flag.String("date", "", "対象日付 (例: 2025-11-06、省略時は最新/今日)")
+	amount := flag.Float64("amount", 0, "補助金額（円/L）")
+	crudeUSD := flag.Float64("crude-usd", 0, "原油価格（ドル/バレル）")
+	port := flag.String("port", defaults.Server.Port, "APIサーバーのポート（省略時は環境変数PORTまたは8080）")
+	fuel := flag.String("fuel", "regular", "燃料種別（regular/premium/diesel）")
+	region := flag.String("region", "全国平均", "地域")
+	horizon := flag.Int("horizon", 4, "予測する週数（1〜4）")
+	from := flag.String("from", "", "期間の開始日 (例: 2025-10-01)")
+	to := flag.String("to", "", "期間の終了日 (例: 2025-10-31)")
+	format := flag.String("format", "text", "出力形式（text/json）")
+	maxLag := flag.Int("max-lag", 8, "相関分析の最大ラグ（週）")
+	window := flag.Int("window", 12, "移動相関の窓幅（週）")
+	newsDays := flag.Int("news-days", 7, "価格変動分析で参照するニュースの日数")
+	useRSS := flag.Bool("rss", false, "RSS/Atomフィードからニュースを取得")
+	newsSources := flag.String("news-sources", "", "ニュース取得元（カンマ区切り: mock,newsapi,rss。省略時は-mock/-rssに従う）")
+	feeds := flag.String("feeds", "", "RSS/Atomフィード（カンマ区切り、\"名前=URL\"形式も可。省略時はデフォルト）")
+	extract := flag.Bool("extract", defaults.News.Extract, "記事URLから本文を取得して分析に使用")
+	maxArticleChars := flag.Int("max-article-chars", defaults.News.MaxArticleChars, "抽出する本文の最大文字数")
+	newsQueriesFile := flag.String("news-queries", defaults.News.QueriesFile, "NewsAPIの名前付き検索条件ファイル")
+	newsQueryName := flag.String("news-query-name", defaults.News.Query, "使用する名前付き検索条件")
+	newsQuery := flag.String("news-query", "", "NewsAPIの検索クエリ（名前付き検索条件のクエリを上書き）")
+	newsMax := flag.Int("news-max", 0, "NewsAPIから取得する最大件数（0なら検索条件の設定に従う）")
+	analyzerName := flag.String("analyzer", defaults.Analyzer.Backend, "ニュース分析・要因分析のバックエンド（mock/gemini/openai。省略時は-mock-analysisに従う）")
+	analyzerModel := flag.String("model", defaults.Analyzer.Model, "分析に使用するモデル（省略時はバックエンドのデフォルト）")
+	cacheTTL := flag.Duration("cache-ttl", defaults.Analyzer.CacheTTL, "分析結果キャッシュの有効期間")
+	noCache := flag.Bool("no-cache", false, "分析結果キャッシュを使用しない")
+	purgeAll := flag.Bool("all", false, "cache-purgeで期限内のエントリも含めて全て削除")
+	promptsDir := flag.String("prompts", defaults.Analyzer.PromptsDir, "プロンプトテンプレートのディレクトリ")
+	promptVersion := flag.String("prompt-version", defaults.Analyzer.PromptVersion, "使用するプロンプトのバージョン（例: v1。省略時は最新）")
+	golden := flag.String("golden", "./eval/golden_news.jsonl", "評価用のラベル付き記事データ（JSONL）")
+	evalOut := flag.String("eval-out", "", "評価結果の保存先（省略時は ./data/eval/ 以下に自動命名）")
+	baseline := flag.String("baseline", "", "比較する前回の評価結果（JSON）")
+	dailyBudget := flag.Float64("daily-budget", defaults.Analyzer.DailyBudget, "LLMの1日（日本時間）の利用上限（ドル、0なら無制限）")
+	reportDays := flag.Int("report-days", 7, "レポートの日数（-from省略時、-toから遡る。1ならデイリー）")
+	topNews := flag.Int("top-news", report.DefaultTopNews, "レポートに載せる注目ニュースの件数")
+	out := flag.String("out", "", "出力先ファイル（省略時は標準出力）")
+	fuels := flag.String("fuels", "", "グラフ・集計の燃料種別（カンマ区切り、省略時は全て）")
+	currency := flag.String("currency", "USD", "グラフに重ねる為替（USD/EUR/GBP/CNY/none）。statsではカンマ区切りで集計する通貨（省略時は全て）")
+	period := flag.String("period", "month", "集計単位（week/month）")
+	width := flag.Int("width", chart.DefaultWidth, "グラフの幅（px）")
+	height := flag.Int("height", chart.DefaultHeight, "グラフの高さ（px）")
+	limit := flag.Int("limit", 0, "一覧の最大件数（0なら全件）")
+	offset := flag.Int("offset", 0, "一覧で読み飛ばす件数")
+	cursor := flag.String("cursor", "", "一覧の続きを取得するカーソル（前回の出力に表示）")
+	sortBy := flag.String("sort", "", "一覧の並び順（例: date, -date, -regular, -impact。先頭の-は降順、省略時は-date）")
+	source := flag.String("source", "", "一覧をデータソース/ニュースの取得元で絞り込む")
+	searchQuery := flag.String("q", "", "ニュース検索の検索語（空白区切りで全てを含む記事）")
+	sentiment := flag.String("sentiment", "", "ニュース検索を感情で絞り込む（positive/neutral/negative）")
+	asOf := flag.String("as-of", "", "list/list-exchange/revisionsでこの日時までに記録した値を表示（RFC3339、\"YYYY-MM-DD HH:MM\"（日本時間）、YYYY-MM-DDならその日の終わり）")
+	retention := flag.String("retention", "", "保持期間（\"テーブル=期間\"のカンマ区切り、例: news_articles=1y,llm_usage=180d,news_summaries=off）")
+	dryRun := flag.Bool("dry-run", false, "maintainで削除対象の件数を表示するだけで削除しない")
+	analyzeInterval := flag.Duration("analyze-interval", 24*time.Hour, "maintainでANALYZEを実行する間隔")
+	vacuumInterval := flag.Duration("vacuum-interval", 7*24*time.Hour, "maintainでVACUUMを実行する間隔（0なら実行しない）")
+	force := flag.Bool("force", false, "maintainで間隔に関わらずANALYZE・VACUUMを実行")
+	backupDir := flag.String("backup-dir", "./data/backups", "バックアップの保存先ディレクトリ")
+	keep := flag.Int("keep", 7, "残すバックアップの数（0なら削除しない）")
+	dataset := flag.String("dataset", "gas-prices", "書き出し・取り込みするデータ（gas-prices/exchange-rates/news/price-changes。revisionsではgas-prices/exchange-rates）")
+	in := flag.String("in", "", "取り込むファイル（省略時は標準入力）")
+	notifyTarget := flag.String("notify", defaults.Report.Notify, "通知先（設定ファイルのnotifiersの名前、またはslack/webhook。slack/webhookのURLは環境変数 SLACK_WEBHOOK_URL / NOTIFY_WEBHOOK_URL）")
 
 	flag.Parse()
 
 	log.Println("🚀 GasInsight ローカル実行版")
 
-	db, err := database.NewSQLiteClient(*dbPath)
+	cfg, err := config.Load(*configPath)
+	if *mode == "config-check" {
+		os.Exit(checkConfig(cfg, err))
+	}
+	if err == nil {
+		err = cfg.Validate()
+	}
+	if err != nil {
+		log.Fatalf("❌ 設定エラー（-mode=config-check で確認できます）:\n  %s", strings.ReplaceAll(err.Error(), "\n", "\n  "))
+	}
+	if cfg.Path != "" {
+		log.Printf("⚙️  設定ファイル: %s", cfg.Path)
+	}
+
+	// 設定ファイル・環境変数の値は、コマンドラインで指定しなかったフラグにだけ反映する
+	fromConfig(dbPath, "db", cfg.Database.Path)
+	fromConfig(port, "port", cfg.Server.Port)
+	fromConfig(useMock, "mock", cfg.Sources.Mock)
+	fromConfig(useScraping, "scrape", cfg.Sources.Scrape)
+	fromConfig(newsSources, "news-sources", strings.Join(cfg.Sources.News, ","))
+	fromConfig(feeds, "feeds", strings.Join(cfg.Sources.Feeds, ","))
+	fromConfig(newsQueriesFile, "news-queries", cfg.News.QueriesFile)
+	fromConfig(newsQueryName, "news-query-name", cfg.News.Query)
+	fromConfig(extract, "extract", cfg.News.Extract)
+	fromConfig(maxArticleChars, "max-article-chars", cfg.News.MaxArticleChars)
+	fromConfig(analyzerName, "analyzer", cfg.Analyzer.Backend)
+	if cfg.Analyzer.Backend != "" {
+		fromConfig(useMockAnalysis, "mock-analysis", cfg.Analyzer.Backend == "mock")
+	}
+	fromConfig(analyzerModel, "model", cfg.Analyzer.Model)
+	fromConfig(promptsDir, "prompts", cfg.Analyzer.PromptsDir)
+	fromConfig(promptVersion, "prompt-version", cfg.Analyzer.PromptVersion)
+	fromConfig(cacheTTL, "cache-ttl", cfg.Analyzer.CacheTTL)
+	fromConfig(dailyBudget, "daily-budget", cfg.Analyzer.DailyBudget)
+	fromConfig(notifyTarget, "notify", cfg.Report.Notify)
+	// -news-queriesで指定したファイルにも、設定ファイルの検索条件を重ねる
+	newsConfig := cfg.News
+	newsConfig.QueriesFile = *newsQueriesFile
+
+	store, err := database.Open(*dbPath)
 	if err != nil {
 		log.Fatalf("❌ エラー: %v", err)
 	}
-	defer db.Close()
+	defer store.Close()
+
+	// メンテナンスとバックアップはSQLiteのファイルに対して行う
+	db, isSQLite := store.(*database.SQLiteClient)
+	if !isSQLite && sqliteModes[*mode] {
+		log.Fatalf("❌ %sモードはSQLiteのDBでのみ実行できます", *mode)
+	}
+
+	listQuery := database.ListQuery{
+		From: *from, To: *to, Source: *source, Sort: *sortBy,
+		Limit: *limit, Offset: *offset, Cursor: *cursor,
+	}
+	revisionQuery := database.RevisionQuery{From: *from, To: *to, Source: *source}
+	if *date != "" {
+		revisionQuery.From, revisionQuery.To = *date, *date
+	}
+	if flagPassed("region") {
+		revisionQuery.Region = *region
+	}
+	if *asOf != "" {
+		if revisionQuery.AsOf, err = database.ParseAsOf(*asOf); err != nil {
+			log.Fatalf("❌ %v", err)
+		}
+	}
 
 	switch *mode {
 	case "fetch":
-		fetchGasPrice(db, *useScraping, *useMock, *detectChange, *mockDate)
+		fetchGasPrice(store, *useScraping, *useMock, *detectChange, cfg.Thresholds.PriceChangePct, *mockDate)
 	case "fetch-exchange":
-		fetchExchangeRate(db, *useMock, *detectChange)
+		fetchExchangeRate(store, *useMock, *detectChange, cfg.Thresholds.PriceChangePct)
 	case "fetch-all":
-		fetchGasPrice(db, *useScraping, *useMock, *detectChange, *mockDate)
-		fetchExchangeRate(db, *useMock, *detectChange)
+		fetchGasPrice(store, *useScraping, *useMock, *detectChange, cfg.Thresholds.PriceChangePct, *mockDate)
+		fetchExchangeRate(store, *useMock, *detectChange, cfg.Thresholds.PriceChangePct)
 	case "list":
-		listGasPrices(db)
+		// -regionのデフォルト（全国平均）では絞り込まず、明示した場合のみ地域で絞り込む
+		q := listQuery
+		if flagPassed("region") {
+			q.Region = *region
+		}
+		if *asOf != "" {
+			listGasPricesAsOf(store, revisionQuery)
+			break
+		}
+		listGasPrices(store, q)
 	case "list-exchange":
-		listExchangeRates(db)
+		if *asOf != "" {
+			q := revisionQuery
+			q.Region = ""
+			listExchangeRatesAsOf(store, q)
+			break
+		}
+		listExchangeRates(store, listQuery)
+	case "revisions":
+		if *dataset == "exchange-rates" {
+			q := revisionQuery
+			q.Region = ""
+			if flagPassed("currency") {
+				q.Currency = *currency
+			}
+			showExchangeRateRevisions(store, q)
+			break
+		}
+		showGasPriceRevisions(store, revisionQuery)
 	case "latest":
-		latestGasPrice(db)
+		latestGasPrice(store)
 	case "latest-exchange":
-		latestExchangeRate(db)
+		latestExchangeRate(store)
 	case "fetch-news":
-		fetchNews(db, *useMock, *useMockAnalysis)
+		var extractor *fetcher.ArticleExtractor
+		if *extract {
+			extractor = fetcher.NewArticleExtractor(*maxArticleChars)
+		}
+		query, err := resolveNewsQuery(newsConfig, *newsQueryName, *newsQuery, *from, *to, *newsMax)
+		if err != nil {
+			log.Fatalf("❌ %v", err)
+		}
+		prompts := prompt.NewStore(*promptsDir)
+		analyzer, err := newsAnalyzer(store, *analyzerName, *analyzerModel, *useMockAnalysis, prompts, *promptVersion, *dailyBudget, *cacheTTL, *noCache)
+		if err != nil {
+			log.Fatalf("❌ %v", err)
+		}
+		fetchNews(store, newsSourceNames(*newsSources, *useMock, *useRSS), analyzer, *feeds, extractor, query)
+	case "eval":
+		// 評価ではキャッシュを使わず、毎回分析器を呼び出す
+		analyzer, err := newsAnalyzer(store, *analyzerName, *analyzerModel, *useMockAnalysis, prompt.NewStore(*promptsDir), *promptVersion, *dailyBudget, 0, true)
+		if err != nil {
+			log.Fatalf("❌ %v", err)
+		}
+		runEval(analyzer, *golden, *evalOut, *baseline, *format)
+	case "usage":
+		showUsage(store, *from, *to, *format)
+	case "chart":
+		renderChart(store, chart.Options{
+			Region: *region, Fuels: chart.SplitFuels(*fuels), Currency: *currency,
+			From: *from, To: *to, Width: *width, Height: *height,
+		}, *format, *out)
+	case "report":
+		var notifier notify.Notifier
+		if *notifyTarget != "" {
+			if notifier, err = cfg.Notifier(*notifyTarget); err != nil {
+				log.Fatalf("❌ %v", err)
+			}
+		}
+		buildReport(store, *from, *to, *reportDays, *topNews, *format, *out, notifier)
+	case "cache-stats":
+		showCacheStats(store, *cacheTTL, *format)
+	case "cache-purge":
+		purgeCache(store, *cacheTTL, *purgeAll)
 	case "list-news":
-		listNews(db)
+		listNews(store, listQuery)
 	case "latest-news":
-		latestNews(db)
+		latestNews(store)
+	case "search-news":
+		searchNews(store, database.NewsSearchQuery{
+			Query: *searchQuery, Sentiment: *sentiment, From: *from, To: *to, Limit: *limit,
+		}, *format)
 	case "analyze-fluctuation":
-		analyzeFluctuation(db)
+		analyzeFluctuation(store, *analyzerName, *analyzerModel, *useMockAnalysis, *newsDays, prompt.NewStore(*promptsDir), *promptVersion, detect.NewUsageMeter(store, *dailyBudget))
+	case "save-subsidy":
+		saveSubsidy(store, *date, *amount)
+	case "save-crude":
+		saveCrudePrice(store, *date, *crudeUSD)
+	case "decompose":
+		decomposeGasPrice(store, *region, *date)
+	case "serve":
+		serveAPI(store, *port)
+	case "forecast":
+		forecastPrice(store, *fuel, *region, *horizon)
+	case "backtest":
+		backtestForecast(store, *fuel, *region, *horizon)
+	case "detect-anomalies":
+		detectAnomalies(store, *from, cfg.Thresholds.AnomalyConfig())
+	case "correlate":
+		correlate(store, *fuel, *region, *maxLag, *window, *format)
+	case "stats":
+		q := database.AggregateQuery{
+			Period: timeseries.Period(*period), From: *from, To: *to, Source: *source,
+			Fuels: splitList(*fuels),
+		}
+		if flagPassed("region") {
+			q.Region = *region
+		}
+		if flagPassed("currency") {
+			q.Currencies = splitList(*currency)
+		}
+		showStats(store, q, *format)
+	case "maintain":
+		policies, err := database.ParseRetention(*retention, database.DefaultRetention)
+		if err != nil {
+			log.Fatalf("❌ %v", err)
+		}
+		maintain(db, database.MaintenanceOptions{
+			Retention: policies, AnalyzeInterval: *analyzeInterval, VacuumInterval: *vacuumInterval,
+			Force: *force, DryRun: *dryRun,
+		})
+	case "backup":
+		backupDB(db, *dbPath, *backupDir, *keep)
+	case "export":
+		exportData(store, *dataset, *format, *from, *to, *out)
+	case "import":
+		importData(store, *dataset, *format, *in)
 	default:
 		log.Fatalf("❌ 不正なモード: %s", *mode)
 	}
@@ -71,7 +326,12 @@ func main() {
 	log.Println("✅ 処理完了")
 }
 
-func fetchGasPrice(db *database.SQLiteClient, useScraping bool, useMock bool, detectChange bool, mockDate string) {
+// sqliteModes SQLiteのDBでのみ実行できるモード（それ以外はdatabase.Storeのみを使用する）
+var sqliteModes = map[string]bool{
+	"maintain": true, "backup": true,
+}
+
+func fetchGasPrice(db database.Store, useScraping bool, useMock bool, detectChange bool, thresholdPct float64, mockDate string) {
 	log.Println("⛽ ガソリン価格を取得中...")
 
 	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
@@ -99,50 +359,81 @@ func fetchGasPrice(db *database.SQLiteClient, useScraping bool, useMock bool, de
 	price := model.NewGasPrice(data.Date, data.Region,
 		data.RegularPrice, data.PremiumPrice, data.DieselPrice)
 
-	if err := db.SaveGasPrice(price); err != nil {
-		log.Fatalf("❌ 保存エラー: %v", err)
+	// 保存と変動検知を同じトランザクションで行い、検知に失敗した場合は保存も取り消す
+	err = db.WithTx(ctx, func(tx database.Store) error {
+		if _, err := tx.SaveGasPrice(price); err != nil {
+			return err
+		}
+		if detectChange {
+			return detectPriceChanges(tx, thresholdPct)
+		}
+		return nil
+	})
+	if err != nil {
+		log.Fatalf("❌ %v", err)
 	}
 
 	printGasPrice(price)
+}
 
-	if detectChange {
-		if err := services.DetectPriceChanges("./data/gasinsight.db", 2.0); err != nil {
-			log.Printf("⚠️  ガソリン価格変動検知エラー: %v", err)
+// detectPriceChanges 直近2日付のガソリン価格を比較して変動を記録（thresholdPctは±%）
+func detectPriceChanges(store database.Store, thresholdPct float64) error {
+	changes, err := detect.NewPriceChangeDetector(store, thresholdPct).Detect()
+	if err != nil {
+		return fmt.Errorf("ガソリン価格変動検知エラー: %w", err)
+	}
+	for _, c := range changes {
+		if c.Flagged {
+			log.Printf("🚨 価格変動を検知: %s %s→%s %+.2f%%", c.Region, c.DateOld, c.DateNew, c.PctChange)
 		}
 	}
+	return nil
 }
 
-func fetchNews(db *database.SQLiteClient, useMockNews bool, useMockAnalysis bool) {
+// extractorがnilでなければ記事URLから本文を取得し、概要の代わりに分析に使う
+func fetchNews(db database.Store, sourceNames []string, analyzer detect.NewsAnalyzer, feeds string, extractor *fetcher.ArticleExtractor, query fetcher.NewsQuery) {
 	log.Println("📰 ニュース取得中...")
 
-	var articles []fetcher.NewsArticle
-	var err error
+	var sources []fetcher.NewsSource
+	useNewsAPI := false
+	for _, name := range sourceNames {
+		switch name {
+		case "mock":
+			// モックニュースを使用
+			sources = append(sources, fetcher.NewMockNewsFetcher())
+		case "rss":
+			// 日本語のRSS/Atomフィードを使用
+			sources = append(sources, fetcher.NewRSSFetcher(fetcher.ParseFeedList(feeds)))
+		case "newsapi":
+			// 実際のNewsAPIを使用
+			apiKey := os.Getenv("NEWSAPI_KEY")
+			if apiKey == "" {
+				log.Println("⚠️  NEWSAPI_KEYが設定されていません。環境変数を確認してください。")
+				log.Println("💡 ヒント: .envファイルに NEWSAPI_KEY=your_key を追加してください")
+				log.Println("💡 取得先: https://newsapi.org/register")
+				continue
+			}
 
-	if useMockNews {
-		// モックニュースを使用
-		mockFetcher := fetcher.NewMockNewsFetcher()
-		articles, err = mockFetcher.FetchTopNews("")
-	} else {
-		// 実際のNewsAPIを使用
-		apiKey := os.Getenv("NEWSAPI_KEY")
-		if apiKey == "" {
-			log.Println("⚠️  NEWSAPI_KEYが設定されていません。環境変数を確認してください。")
-			log.Println("💡 ヒント: .envファイルに NEWSAPI_KEY=your_key を追加してください")
-			log.Println("💡 取得先: https://newsapi.org/register")
-			return
+			log.Printf("🔑 APIキー: %s...%s (長さ: %d)", apiKey[:4], apiKey[len(apiKey)-4:], len(apiKey))
+			sources = append(sources, fetcher.NewNewsFetcher(apiKey).WithQuery(query))
+			useNewsAPI = true
+		default:
+			log.Printf("⚠️  不明なニュース取得元: %s", name)
 		}
+	}
 
-		log.Printf("🔑 APIキー: %s...%s (長さ: %d)", apiKey[:4], apiKey[len(apiKey)-4:], len(apiKey))
-
-		newsFetcher := fetcher.NewNewsFetcher(apiKey)
-
-		// 英語のクエリを使用（NewsAPIは英語の方が安定）
-		articles, err = newsFetcher.FetchTopNews("oil OR gasoline OR economy")
+	if len(sources) == 0 {
+		log.Println("❌ 有効なニュース取得元がありません")
+		return
 	}
 
+	aggregator := fetcher.NewNewsAggregator(sources...)
+	log.Printf("📡 取得元: %s", aggregator.Name())
+
+	articles, err := aggregator.FetchTopNews("")
 	if err != nil {
 		log.Printf("❌ ニュース取得エラー: %v", err)
-		if !useMockNews {
+		if useNewsAPI {
 			log.Println("💡 ヒント:")
 			log.Println("  1. NewsAPIキーが正しいか確認")
 			log.Println("  2. https://newsapi.org/account でAPIキーの状態を確認")
@@ -158,28 +449,31 @@ func fetchNews(db *database.SQLiteClient, useMockNews bool, useMockAnalysis bool
 	}
 
 	log.Printf("📊 %d件のニュースを取得しました", len(articles))
+
+	if extractor != nil {
+		log.Println("📄 記事本文を取得中...")
+		ctx, cancel := context.WithTimeout(context.Background(), 2*time.Minute)
+		articles = extractor.EnrichArticles(ctx, articles)
+		cancel()
+	}
+
+	// 分析前の記事（本文）を保存
+	for _, a := range articles {
+		if err := db.SaveNewsArticle(a); err != nil {
+			log.Printf("⚠️  %v", err)
+		}
+	}
+
 	successCount := 0
 
 	for i, a := range articles {
 		log.Printf("[%d/%d] 分析中: %s", i+1, len(articles), a.Title)
 
-		var analyzed *detect.AnalyzedNews
-		var err error
-
-		if useMockAnalysis {
-			// モック分析を使用（API不要）
-			analyzed, err = detect.MockAnalyzeNews(a)
-		} else {
-			// Gemini APIで分析
-			// レート制限回避のため、前のリクエストから時間を空ける（無料枠対策）
-			if i > 0 {
-				log.Printf("⏳ APIレート制限回避のため 10秒待機中...")
-				time.Sleep(10 * time.Second)
-			}
-
-			analyzed, err = detect.AnalyzeNewsWithGemini(a)
+		analyzed, err := analyzer.Analyze(a)
+		if errors.Is(err, detect.ErrBudgetExceeded) {
+			log.Printf("🛑 %v。残りの記事の分析を中止します", err)
+			break
 		}
-
 		if err != nil {
 			log.Printf("⚠️  分析エラー: %v", err)
 			// 429エラーの場合は長めに待機してリトライを促すなどの処理が可能だが、
@@ -192,14 +486,103 @@ func fetchNews(db *database.SQLiteClient, useMockNews bool, useMockAnalysis bool
 			continue
 		}
 
-		log.Printf("✅ 保存: %s (%s)", analyzed.Title, analyzed.Sentiment)
+		log.Printf("✅ 保存: %s (%s %+.2f, 影響 %.2f, %s) [%s]", analyzed.Title, analyzed.Sentiment.Label(),
+			analyzed.SentimentScore, analyzed.ImpactScore, analyzed.Direction, analyzed.Source)
 		successCount++
 	}
 
 	log.Printf("🎉 完了: %d/%d 件のニュースを保存しました", successCount, len(articles))
 }
 
-func fetchExchangeRate(db *database.SQLiteClient, useMock bool, detectChange bool) {
+// newsAnalyzer 分析バックエンドを作成し、キャッシュを有効にする
+func newsAnalyzer(db database.Store, backend, model string, useMockAnalysis bool, prompts *prompt.Store, promptVersion string, dailyBudget float64, ttl time.Duration, noCache bool) (detect.NewsAnalyzer, error) {
+	backend = analyzerBackend(backend, useMockAnalysis)
+
+	var tmpl *prompt.Template
+	if backend != "mock" {
+		var err error
+		if tmpl, err = prompts.Load(detect.NewsPromptName, promptVersion); err != nil {
+			return nil, err
+		}
+	}
+
+	analyzer, err := detect.NewNewsAnalyzer(backend, model, tmpl, detect.NewUsageMeter(db, dailyBudget))
+	if err != nil {
+		return nil, err
+	}
+	log.Printf("🤖 分析バックエンド: %s (%s, prompt %s)", analyzer.Name(), analyzer.Model(), analyzer.PromptVersion())
+
+	if noCache {
+		return analyzer, nil
+	}
+	return detect.NewCachedAnalyzer(analyzer, db, ttl), nil
+}
+
+// analyzerBackend 分析バックエンド名（-analyzer未指定なら-mock-analysisに従う）
+func analyzerBackend(backend string, useMockAnalysis bool) string {
+	if backend != "" {
+		return backend
+	}
+	if useMockAnalysis {
+		return "mock"
+	}
+	return "gemini"
+}
+
+// resolveNewsQuery 名前付き検索条件を読み込み、コマンドライン指定で上書き
+func resolveNewsQuery(news config.NewsConfig, name, query, from, to string, max int) (fetcher.NewsQuery, error) {
+	queries, err := news.LoadQueries()
+	if err != nil {
+		return fetcher.NewsQuery{}, err
+	}
+	q, ok := queries[name]
+	if !ok {
+		var names []string
+		for n := range queries {
+			names = append(names, n)
+		}
+		sort.Strings(names)
+		return fetcher.NewsQuery{}, fmt.Errorf("検索条件 %q が見つかりません（利用可能: %s）", name, strings.Join(names, ", "))
+	}
+
+	if query != "" {
+		q.Query = query
+	}
+	if from != "" {
+		q.From = from
+	}
+	if to != "" {
+		q.To = to
+	}
+	if max > 0 {
+		q.MaxResults = max
+	}
+	return q, nil
+}
+
+// newsSourceNames ニュース取得元の一覧を決定（-news-sources指定がなければ-mock/-rssから判断）
+func newsSourceNames(list string, useMock bool, useRSS bool) []string {
+	if list != "" {
+		var names []string
+		for _, name := range strings.Split(list, ",") {
+			if name = strings.TrimSpace(name); name != "" {
+				names = append(names, name)
+			}
+		}
+		return names
+	}
+
+	switch {
+	case useRSS:
+		return []string{"rss"}
+	case useMock:
+		return []string{"mock"}
+	default:
+		return []string{"newsapi"}
+	}
+}
+
+func fetchExchangeRate(db database.Store, useMock bool, detectChange bool, thresholdPct float64) {
 	log.Println("💱 為替レートを取得中...")
 
 	ctx, cancel := context.WithTimeout(context.Background(), 15*time.Second)
@@ -222,23 +605,50 @@ func fetchExchangeRate(db *database.SQLiteClient, useMock bool, detectChange boo
 
 	rate := model.NewExchangeRate(data.Date, data.USDJPY, data.EURJPY, data.GBPJPY, data.CNYJPY)
 
-	if err := db.SaveExchangeRate(rate); err != nil {
-		log.Fatalf("❌ 保存エラー: %v", err)
+	// --- 保存と変動検知（同じトランザクション） ---
+	err = db.WithTx(ctx, func(tx database.Store) error {
+		if _, err := tx.SaveExchangeRate(rate); err != nil {
+			return err
+		}
+		if detectChange {
+			return detectPriceChanges(tx, thresholdPct)
+		}
+		return nil
+	})
+	if err != nil {
+		log.Fatalf("❌ %v", err)
 	}
 
 	printExchangeRate(rate)
+}
 
-	// --- 変動検知 ---
-	if detectChange {
-		if err := services.DetectPriceChanges("./data/gasinsight.db", 2.0); err != nil {
-			log.Printf("⚠️  ガソリン価格変動検知エラー: %v", err)
-		}
+// fromConfig コマンドラインでフラグを指定しなかった場合に、設定の値を使う
+func fromConfig[T any](p *T, name string, v T) {
+	if !flagPassed(name) {
+		*p = v
 	}
+}
+
+// flagPassed コマンドラインでフラグが明示的に指定されたか
+func flagPassed(name string) bool {
+	passed := false
+	flag.Visit(func(f *flag.Flag) {
+		if f.Name == name {
+			passed = true
+		}
+	})
+	return passed
+}
 
+// printNextCursor 続きがある場合に次ページの取得方法を表示
+func printNextCursor(next string) {
+	if next != "" {
+		fmt.Printf("\n➡️  続きは -cursor %s で取得できます\n", next)
+	}
 }
 
-func listGasPrices(db *database.SQLiteClient) {
-	prices, err := db.GetAllGasPrices()
+func listGasPrices(db database.GasPriceStore, q database.ListQuery) {
+	prices, next, err := db.ListGasPrices(q)
 	if err != nil {
 		log.Fatalf("❌ 取得エラー: %v", err)
 	}
@@ -249,14 +659,33 @@ func listGasPrices(db *database.SQLiteClient) {
 	}
 
 	fmt.Printf("\n📊 ガソリン価格データ一覧（%d件）\n\n", len(prices))
+	for i, p := range prices {
+		fmt.Printf("[%d] %s - レギュラー:%.2f円 ハイオク:%.2f円 軽油:%.2f円 (%s)\n",
+			q.Offset+i+1, p.Date, p.RegularPrice, p.PremiumPrice, p.DieselPrice, p.Region)
+	}
+	printNextCursor(next)
+}
+
+func listGasPricesAsOf(db database.GasPriceStore, q database.RevisionQuery) {
+	prices, err := db.GasPricesAsOf(q)
+	if err != nil {
+		log.Fatalf("❌ 取得エラー: %v", err)
+	}
+
+	if len(prices) == 0 {
+		fmt.Println("📭 データがありません")
+		return
+	}
+
+	fmt.Printf("\n📊 ガソリン価格データ一覧（%s時点、%d件）\n\n", q.AsOf.In(timeseries.JST).Format("2006-01-02 15:04"), len(prices))
 	for i, p := range prices {
 		fmt.Printf("[%d] %s - レギュラー:%.2f円 ハイオク:%.2f円 軽油:%.2f円 (%s)\n",
 			i+1, p.Date, p.RegularPrice, p.PremiumPrice, p.DieselPrice, p.Region)
 	}
 }
 
-func listExchangeRates(db *database.SQLiteClient) {
-	rates, err := db.GetAllExchangeRates()
+func listExchangeRatesAsOf(db database.ExchangeRateStore, q database.RevisionQuery) {
+	rates, err := db.ExchangeRatesAsOf(q)
 	if err != nil {
 		log.Fatalf("❌ 取得エラー: %v", err)
 	}
@@ -266,14 +695,78 @@ func listExchangeRates(db *database.SQLiteClient) {
 		return
 	}
 
-	fmt.Printf("\n💱 為替レートデータ一覧(%d件) \n\n", len(rates))
+	fmt.Printf("\n💱 為替レートデータ一覧（%s時点、%d件）\n\n", q.AsOf.In(timeseries.JST).Format("2006-01-02 15:04"), len(rates))
 	for i, r := range rates {
 		fmt.Printf("[%d] %s - USD:%.2f EUR:%.2f GBP:%.2f CNY:%.2f\n",
 			i+1, r.Date, r.USDJPY, r.EURJPY, r.GBPJPY, r.CNYJPY)
 	}
 }
 
-func latestGasPrice(db *database.SQLiteClient) {
+// showGasPriceRevisions 日付・地域・データソースごとの改定履歴（2件以上あれば改定あり）
+func showGasPriceRevisions(db database.GasPriceStore, q database.RevisionQuery) {
+	revisions, err := db.GasPriceRevisions(q)
+	if err != nil {
+		log.Fatalf("❌ 取得エラー: %v", err)
+	}
+	if len(revisions) == 0 {
+		fmt.Println("📭 改定履歴がありません")
+		return
+	}
+
+	fmt.Printf("\n🔁 ガソリン価格の改定履歴（%d件）\n", len(revisions))
+	var key string
+	for _, r := range revisions {
+		if k := r.Date + "|" + r.Region + "|" + r.Source; k != key {
+			key = k
+			fmt.Printf("\n%s %s（%s）\n", r.Date, r.Region, r.Source)
+		}
+		fmt.Printf("  %s  レギュラー:%.2f円 ハイオク:%.2f円 軽油:%.2f円\n",
+			time.Unix(r.RecordedAt, 0).In(timeseries.JST).Format("2006-01-02 15:04:05"), r.RegularPrice, r.PremiumPrice, r.DieselPrice)
+	}
+}
+
+// showExchangeRateRevisions 日付・データソース・通貨ごとの改定履歴
+func showExchangeRateRevisions(db database.ExchangeRateStore, q database.RevisionQuery) {
+	revisions, err := db.ExchangeRateRevisions(q)
+	if err != nil {
+		log.Fatalf("❌ 取得エラー: %v", err)
+	}
+	if len(revisions) == 0 {
+		fmt.Println("📭 改定履歴がありません")
+		return
+	}
+
+	fmt.Printf("\n🔁 為替レートの改定履歴（%d件）\n", len(revisions))
+	var key string
+	for _, r := range revisions {
+		if k := r.Date + "|" + r.Source; k != key {
+			key = k
+			fmt.Printf("\n%s（%s）\n", r.Date, r.Source)
+		}
+		fmt.Printf("  %s  %s/JPY: %.4f\n", time.Unix(r.RecordedAt, 0).In(timeseries.JST).Format("2006-01-02 15:04:05"), r.Currency, r.Rate)
+	}
+}
+
+func listExchangeRates(db database.ExchangeRateStore, q database.ListQuery) {
+	rates, next, err := db.ListExchangeRates(q)
+	if err != nil {
+		log.Fatalf("❌ 取得エラー: %v", err)
+	}
+
+	if len(rates) == 0 {
+		fmt.Println("📭 データがありません")
+		return
+	}
+
+	fmt.Printf("\n💱 為替レートデータ一覧(%d件) \n\n", len(rates))
+	for i, r := range rates {
+		fmt.Printf("[%d] %s - USD:%.2f EUR:%.2f GBP:%.2f CNY:%.2f\n",
+			q.Offset+i+1, r.Date, r.USDJPY, r.EURJPY, r.GBPJPY, r.CNYJPY)
+	}
+	printNextCursor(next)
+}
+
+func latestGasPrice(db database.GasPriceStore) {
 	p, err := db.GetLatestGasPrice()
 	if err != nil {
 		log.Fatalf("❌ 取得エラー: %v", err)
@@ -281,7 +774,7 @@ func latestGasPrice(db *database.SQLiteClient) {
 	printGasPrice(p)
 }
 
-func latestExchangeRate(db *database.SQLiteClient) {
+func latestExchangeRate(db database.ExchangeRateStore) {
 	r, err := db.GetLatestExchangeRate()
 	if err != nil {
 		log.Fatalf("❌ 取得エラー: %v", err)
@@ -313,8 +806,8 @@ func printExchangeRate(r *model.ExchangeRate) {
 	fmt.Println("━━━━━━━━━━━━━━━━━━━━━━")
 }
 
-func listNews(db *database.SQLiteClient) {
-	newsList, err := db.GetAllNews()
+func listNews(db database.NewsStore, q database.ListQuery) {
+	newsList, next, err := db.ListNews(q)
 	if err != nil {
 		log.Fatalf("❌ 取得エラー: %v", err)
 	}
@@ -326,14 +819,15 @@ func listNews(db *database.SQLiteClient) {
 
 	fmt.Printf("\n📰 ニュース一覧（%d件）\n\n", len(newsList))
 	for i, n := range newsList {
-		fmt.Printf("[%d] %s\n", i+1, n.Title)
-		fmt.Printf("    日付: %s | 感情: %s\n", n.Date, n.Sentiment)
+		fmt.Printf("[%d] %s\n", q.Offset+i+1, n.Title)
+		fmt.Printf("    日付: %s | 感情: %s (%+.2f) | 影響: %.2f | 方向: %s\n", n.Date, n.Sentiment.Label(), n.SentimentScore, n.ImpactScore, n.Direction)
 		fmt.Printf("    要約: %s\n", truncateString(n.Summary, 100))
 		fmt.Printf("    URL:  %s\n\n", n.URL)
 	}
+	printNextCursor(next)
 }
 
-func latestNews(db *database.SQLiteClient) {
+func latestNews(db database.NewsStore) {
 	newsList, err := db.GetLatestNews(5)
 	if err != nil {
 		log.Fatalf("❌ 取得エラー: %v", err)
@@ -350,13 +844,48 @@ func latestNews(db *database.SQLiteClient) {
 		fmt.Printf("[%d] %s\n", i+1, n.Title)
 		fmt.Println("━━━━━━━━━━━━━━━━━━━━━━")
 		fmt.Printf("日付:   %s\n", n.Date)
-		fmt.Printf("感情:   %s\n", n.Sentiment)
+		fmt.Printf("感情:   %s (スコア %+.2f, 確信度 %.2f)\n", n.Sentiment.Label(), n.SentimentScore, n.Confidence)
+		fmt.Printf("影響:   %.2f（価格の方向: %s）\n", n.ImpactScore, n.Direction)
 		fmt.Printf("要約:\n%s\n", n.Summary)
 		fmt.Printf("URL:    %s\n", n.URL)
 	}
 	fmt.Println("\n━━━━━━━━━━━━━━━━━━━━━━")
 }
 
+func searchNews(db database.Store, q database.NewsSearchQuery, format string) {
+	if format != "json" {
+		q.HighlightStart, q.HighlightEnd, q.PlainText = "**", "**", true
+	}
+	if _, isSQLite := db.(*database.SQLiteClient); isSQLite && !db.FullTextSearchEnabled() {
+		log.Println("⚠️  FTS5が無効なビルドのため、LIKEで検索します（-tags sqlite_fts5 でビルドすると全文検索を使用）")
+	}
+	hits, err := db.SearchNews(q)
+	if err != nil {
+		log.Fatalf("❌ %v", err)
+	}
+
+	if format == "json" {
+		if hits == nil {
+			hits = []*database.NewsSearchHit{}
+		}
+		out, _ := json.MarshalIndent(hits, "", "  ")
+		fmt.Println(string(out))
+		return
+	}
+
+	if len(hits) == 0 {
+		fmt.Printf("📭 「%s」に一致するニュースがありません\n", q.Query)
+		return
+	}
+	fmt.Printf("\n🔎 「%s」の検索結果（%d件）\n\n", q.Query, len(hits))
+	for i, h := range hits {
+		fmt.Printf("[%d] %s\n", i+1, h.TitleHighlight)
+		fmt.Printf("    日付: %s | 感情: %s (%+.2f) | 影響: %.2f | 関連度: %.2f\n", h.Date, h.Sentiment.Label(), h.SentimentScore, h.ImpactScore, h.Score)
+		fmt.Printf("    抜粋: %s\n", strings.ReplaceAll(h.Snippet, "\n", " "))
+		fmt.Printf("    URL:  %s\n\n", h.URL)
+	}
+}
+
 func truncateString(s string, n int) string {
 	r := []rune(s)
 	if len(r) <= n {
@@ -365,49 +894,859 @@ func truncateString(s string, n int) string {
 	return string(r[:n]) + "..."
 }
 
-func analyzeFluctuation(db *database.SQLiteClient) {
+func analyzeFluctuation(db database.Store, backend, modelName string, useMockAnalysis bool, newsDays int, prompts *prompt.Store, promptVersion string, meter *detect.UsageMeter) {
 	log.Println("📉 価格変動分析を実行中...")
 
-	// 1. ニュースを取得（実際はDBからその日のニュースを取得するが、今回はAPIから取得）
-	apiKey := os.Getenv("NEWSAPI_KEY")
-	if apiKey == "" {
-		log.Println("⚠️ NEWSAPI_KEYが設定されていません")
+	// 1. 最新のアラート対象の価格変動を取得
+	change, err := db.GetLatestFlaggedPriceChange()
+	if err != nil {
+		log.Printf("❌ 取得エラー: %v", err)
+		log.Println("💡 ヒント: make fetch で価格を取得すると変動検知が実行されます")
 		return
 	}
 
-	newsFetcher := fetcher.NewNewsFetcher(apiKey)
-	// テスト用に3件取得
-	articles, err := newsFetcher.FetchTopNews("oil OR gasoline OR economy")
+	// 2. 比較期間とその前の期間に保存された分析済みニュースを取得
+	newsFrom := detect.NewsFrom(change.DateOld, change.DateNew, newsDays)
+	newsList, err := db.GetNewsBetween(newsFrom, change.DateNew)
 	if err != nil {
 		log.Printf("❌ ニュース取得エラー: %v", err)
 		return
 	}
 
-	if len(articles) > 3 {
-		articles = articles[:3]
+	// 3. 同期間の為替変動を取得
+	in := detect.PriceChangeContext{
+		Region:    change.Region,
+		DateOld:   change.DateOld,
+		DateNew:   change.DateNew,
+		OldPrice:  change.PriceOld,
+		NewPrice:  change.PriceNew,
+		PctChange: change.PctChange,
+		News:      newsList,
+	}
+	if r, err := db.GetExchangeRateOnOrBefore(change.DateOld); err == nil && r != nil {
+		in.USDJPYOld = r.USDJPY
+	}
+	if r, err := db.GetExchangeRateOnOrBefore(change.DateNew); err == nil && r != nil {
+		in.USDJPYNew = r.USDJPY
 	}
 
-	// 2. ダミーの価格変動データ
-	oldPrice := 160
-	newPrice := 165
-	priceDiff := newPrice - oldPrice
-
-	log.Printf("📊 検知された価格変動: %d円 -> %d円 (%+d円)", oldPrice, newPrice, priceDiff)
-	log.Printf("📰 関連ニュース数: %d件", len(articles))
-	log.Println("🤖 Geminiによる分析を開始します...")
+	log.Printf("📊 検知された価格変動: %.2f円 -> %.2f円 (%+.2f%%) [%s]", change.PriceOld, change.PriceNew, change.PctChange, change.Region)
+	log.Printf("📰 関連ニュース数: %d件（%s〜%s）", len(newsList), newsFrom, change.DateNew)
 
-	// 3. 分析実行
-	ctx := context.Background()
-	analysis, err := detect.AnalyzePriceChange(ctx, priceDiff, oldPrice, newPrice, articles)
+	// 4. 分析実行（ニュース分析と同じバックエンド・モデルを使う）
+	backend = analyzerBackend(backend, useMockAnalysis)
+	var tmpl *prompt.Template
+	if backend != "mock" {
+		if tmpl, err = prompts.Load(detect.PriceChangePromptName, promptVersion); err != nil {
+			log.Printf("❌ %v", err)
+			return
+		}
+	}
+	analyzer, err := detect.NewPriceChangeAnalyzer(backend, modelName, tmpl, meter)
+	if err != nil {
+		log.Printf("❌ %v", err)
+		return
+	}
+	log.Printf("🤖 要因分析を開始します: %s (%s, prompt %s)", analyzer.Name(), analyzer.Model(), analyzer.PromptVersion())
+	analysis, err := analyzer.AnalyzePriceChange(context.Background(), in)
 	if err != nil {
 		log.Printf("❌ 分析エラー: %v", err)
 		return
 	}
 
-	// 4. 結果表示
+	// 5. 変動記録に紐づけて保存
+	attribution := model.NewPriceChangeAttribution(change.ID, analyzer.Name(), analyzer.Model(), analyzer.PromptVersion(), len(newsList), in.USDJPYOld, in.USDJPYNew, analysis)
+	if err := db.SavePriceChangeAttribution(attribution); err != nil {
+		log.Printf("⚠️  保存エラー: %v", err)
+	}
+
+	// 6. 結果表示
 	fmt.Println("\n━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━")
-	fmt.Println("🤖 Geminiの分析レポート")
+	fmt.Printf("🤖 価格変動の要因分析レポート（%s / %s）\n", analyzer.Name(), analyzer.Model())
 	fmt.Println("━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━")
 	fmt.Println(analysis)
 	fmt.Println("━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━")
 }
+
+func saveSubsidy(db database.Store, date string, amount float64) {
+	if amount < 0 || math.IsNaN(amount) || math.IsInf(amount, 0) {
+		log.Fatalf("❌ -amount には0以上の補助金額（円/L）を指定してください: %v", amount)
+	}
+	if date == "" {
+		date = time.Now().Format("2006-01-02")
+	}
+
+	weekStart, err := pricing.WeekStart(date)
+	if err != nil {
+		log.Fatalf("❌ %v", err)
+	}
+
+	if err := db.SaveFuelSubsidy(model.NewFuelSubsidy(weekStart, amount)); err != nil {
+		log.Fatalf("❌ 保存エラー: %v", err)
+	}
+}
+
+func saveCrudePrice(db database.Store, date string, usdPerBarrel float64) {
+	if usdPerBarrel <= 0 {
+		log.Fatalf("❌ -crude-usd に原油価格（ドル/バレル）を指定してください")
+	}
+	if date == "" {
+		date = time.Now().Format("2006-01-02")
+	}
+
+	if err := db.SaveCrudePrice(model.NewCrudePrice(date, usdPerBarrel)); err != nil {
+		log.Fatalf("❌ 保存エラー: %v", err)
+	}
+}
+
+func decomposeGasPrice(db database.Store, region, date string) {
+	p, err := database.FindGasPrice(db, region, date)
+	if err != nil {
+		log.Fatalf("❌ 取得エラー: %v", err)
+	}
+
+	decompositions, err := pricing.DecomposeGasPrice(db, p)
+	if err != nil {
+		log.Fatalf("❌ 価格分解エラー: %v", err)
+	}
+
+	labels := map[string]string{
+		pricing.FuelRegular: "レギュラー",
+		pricing.FuelPremium: "ハイオク",
+		pricing.FuelDiesel:  "軽油",
+	}
+
+	for _, d := range decompositions {
+		fmt.Println("\n━━━━━━━━━━━━━━━━━━━━━━")
+		fmt.Printf("🧾 %s 価格内訳 (%s / %s)\n", labels[d.FuelType], d.Date, d.Region)
+		fmt.Println("━━━━━━━━━━━━━━━━━━━━━━")
+		fmt.Printf("小売価格:     %.2f円\n", d.RetailPrice)
+		if d.HasCrude {
+			fmt.Printf("原油コスト:   %.2f円\n", d.CrudeCost)
+			fmt.Printf("マージン等:   %.2f円\n", d.Margin)
+		} else {
+			fmt.Printf("原油+マージン: %.2f円（原油データなし）\n", d.Margin)
+		}
+		if d.FuelType == pricing.FuelDiesel {
+			fmt.Printf("軽油引取税:   %.2f円\n", d.FuelTax)
+		} else {
+			fmt.Printf("揮発油税:     %.2f円\n", d.FuelTax)
+		}
+		fmt.Printf("暫定税率:     %.2f円\n", d.ProvisionalTax)
+		fmt.Printf("石油石炭税:   %.2f円\n", d.PetroleumCoalTax)
+		fmt.Printf("消費税:       %.2f円\n", d.ConsumptionTax)
+		fmt.Printf("補助金:      -%.2f円\n", d.Subsidy)
+		fmt.Printf("税金合計:     %.2f円 (%.1f%%)\n", d.TaxTotal, d.TaxTotal/d.RetailPrice*100)
+	}
+	fmt.Println("━━━━━━━━━━━━━━━━━━━━━━")
+}
+
+func serveAPI(db database.Store, port string) {
+	if port == "" {
+		port = os.Getenv("PORT")
+	}
+	if port == "" {
+		port = "8080"
+	}
+
+	server := api.NewServer(db)
+	if err := server.ListenAndServe(":" + port); err != nil {
+		log.Fatalf("❌ サーバーエラー: %v", err)
+	}
+}
+
+func forecastPrice(db database.Store, fuel, region string, horizon int) {
+	log.Printf("🔮 %s（%s）の価格を予測中...", fuel, region)
+
+	in, err := forecast.LoadInputs(db, fuel, region)
+	if err != nil {
+		log.Fatalf("❌ %v", err)
+	}
+
+	cfg := forecast.DefaultConfig()
+	cfg.Horizons = horizon
+
+	f, err := forecast.Predict(in, cfg)
+	if err != nil {
+		log.Fatalf("❌ 予測エラー: %v", err)
+	}
+
+	fmt.Println("\n━━━━━━━━━━━━━━━━━━━━━━")
+	fmt.Printf("🔮 価格予測 (%s / %s)\n", fuel, region)
+	fmt.Println("━━━━━━━━━━━━━━━━━━━━━━")
+	fmt.Printf("手法:     %s\n", f.Method)
+	fmt.Printf("直近週:   %s %.2f円\n", f.LastWeek, f.LastValue)
+	for _, p := range f.Predictions {
+		fmt.Printf("%d週後 (%s週): %.2f円  [%.0f%%区間 %.2f〜%.2f円]\n",
+			p.Horizon, p.WeekStart, p.Value, f.Confidence*100, p.Lower, p.Upper)
+	}
+	fmt.Println("━━━━━━━━━━━━━━━━━━━━━━")
+}
+
+func backtestForecast(db database.Store, fuel, region string, horizon int) {
+	log.Printf("🧪 %s（%s）の予測をバックテスト中...", fuel, region)
+
+	in, err := forecast.LoadInputs(db, fuel, region)
+	if err != nil {
+		log.Fatalf("❌ %v", err)
+	}
+
+	cfg := forecast.DefaultConfig()
+	cfg.Horizons = horizon
+
+	report, err := forecast.Backtest(in, cfg, 8)
+	if err != nil {
+		log.Fatalf("❌ バックテストエラー: %v", err)
+	}
+
+	fmt.Println("\n━━━━━━━━━━━━━━━━━━━━━━")
+	fmt.Printf("🧪 バックテスト結果 (%s / %s, 起点%d件)\n", fuel, region, report.Origins)
+	fmt.Println("━━━━━━━━━━━━━━━━━━━━━━")
+	for method, n := range report.Methods {
+		fmt.Printf("手法: %s (%d回)\n", method, n)
+	}
+	for _, m := range report.Metrics {
+		fmt.Printf("%d週後: n=%d MAE=%.2f円 RMSE=%.2f円 MAPE=%.2f%% ナイーブMAE=%.2f円 区間的中率=%.0f%%\n",
+			m.Horizon, m.N, m.MAE, m.RMSE, m.MAPE, m.NaiveMAE, m.Coverage*100)
+	}
+	fmt.Println("━━━━━━━━━━━━━━━━━━━━━━")
+}
+
+func detectAnomalies(db database.Store, from string, anomalyConfig detect.AnomalyConfig) {
+	log.Println("🚨 統計的異常検知を実行中...")
+
+	series, err := detect.LoadAnomalySeries(db)
+	if err != nil {
+		log.Fatalf("❌ %v", err)
+	}
+
+	var events []detect.DetectionEvent
+	for _, e := range detect.DetectAnomalies(series, anomalyConfig) {
+		if from == "" || e.Date >= from {
+			events = append(events, e)
+		}
+	}
+
+	if len(events) == 0 {
+		fmt.Println("✅ 異常は検知されませんでした")
+		return
+	}
+
+	fmt.Printf("\n🚨 検知イベント（%d件）\n\n", len(events))
+	for i, e := range events {
+		fmt.Printf("[%d] %s %s (%s)\n", i+1, e.Date, e.Series, e.Method)
+		switch e.Method {
+		case detect.MethodZScore:
+			fmt.Printf("    値: %.2f  z=%.2f (しきい値 ±%.1f)\n", e.Value, e.Score, e.Threshold)
+			fmt.Printf("    基準: %s〜%s の%d点 平均%.2f 標準偏差%.2f\n\n",
+				e.Baseline.From, e.Baseline.To, e.Baseline.Window, e.Baseline.Mean, e.Baseline.StdDev)
+		case detect.MethodEWMA:
+			fmt.Printf("    値: %.2f  EWMA=%.2f (管理限界 %.2f〜%.2f)\n", e.Value, e.Score, e.Baseline.Lower, e.Baseline.Upper)
+			fmt.Printf("    基準: %s〜%s の%d点 平均%.2f 標準偏差%.2f\n\n",
+				e.Baseline.From, e.Baseline.To, e.Baseline.Window, e.Baseline.Mean, e.Baseline.StdDev)
+		case detect.MethodStreak:
+			fmt.Printf("    値: %.2f  %.0f回連続%s (起点 %s %.2f)\n\n",
+				e.Value, e.Score, map[string]string{"up": "上昇", "down": "下落"}[e.Direction], e.Baseline.From, e.Baseline.Mean)
+		}
+	}
+}
+
+func correlate(db database.Store, fuel, region string, maxLag, window int, format string) {
+	cfg := analysis.Config{MaxLag: maxLag, Window: window}
+	if err := cfg.Validate(); err != nil {
+		log.Fatalf("❌ -max-lag/-window が不正です: %v", err)
+	}
+	log.Printf("📈 %s（%s）と為替・原油の相関を分析中...", fuel, region)
+
+	report, err := analysis.Analyze(db, fuel, region, cfg)
+	if err != nil {
+		log.Fatalf("❌ 分析エラー: %v", err)
+	}
+
+	if format == "json" {
+		enc := json.NewEncoder(os.Stdout)
+		enc.SetIndent("", "  ")
+		if err := enc.Encode(report); err != nil {
+			log.Fatalf("❌ JSON出力エラー: %v", err)
+		}
+		return
+	}
+
+	for _, p := range report.Pairs {
+		fmt.Println("\n━━━━━━━━━━━━━━━━━━━━━━")
+		fmt.Printf("📈 %s → %s（共通%d週）\n", p.Driver, p.Target, p.Weeks)
+		fmt.Println("━━━━━━━━━━━━━━━━━━━━━━")
+		fmt.Println("ラグ別相関（週次変化率）:")
+		for _, c := range p.CrossCorr {
+			marker := ""
+			if c.Lag == p.BestLag {
+				marker = " ◀"
+			}
+			fmt.Printf("  %2d週: %+.3f (n=%d)%s\n", c.Lag, float64(c.Corr), c.N, marker)
+		}
+		fmt.Printf("最大相関ラグ: %d週 (%+.3f)\n", p.BestLag, float64(p.BestCorr))
+		fmt.Printf("弾性値: 短期%.3f 累積%.3f 水準%.3f (R²=%.2f)\n",
+			float64(p.Elasticity.ShortRun), float64(p.Elasticity.Cumulative),
+			float64(p.Elasticity.LevelRatio), float64(p.Elasticity.R2))
+		if n := len(p.Rolling); n > 0 {
+			fmt.Printf("移動相関（%d週窓, 直近）:\n", report.Window)
+			for _, r := range p.Rolling[max(0, n-5):] {
+				fmt.Printf("  %s: %+.3f\n", r.WeekStart, float64(r.Corr))
+			}
+		}
+	}
+	fmt.Println("━━━━━━━━━━━━━━━━━━━━━━")
+}
+
+func showCacheStats(db database.Store, ttl time.Duration, format string) {
+	stats, err := db.GetAnalysisCacheStats(ttl)
+	if err != nil {
+		log.Fatalf("❌ %v", err)
+	}
+
+	if format == "json" {
+		out, _ := json.MarshalIndent(stats, "", "  ")
+		fmt.Println(string(out))
+		return
+	}
+
+	fmt.Println("\n💾 分析結果キャッシュ:")
+	fmt.Printf("  エントリ数: %d（期限切れ: %d、TTL: %s）\n", stats.Entries, stats.Expired, ttl)
+	fmt.Printf("  ヒット数:   %d\n", stats.Hits)
+	if stats.Oldest != "" {
+		fmt.Printf("  期間:       %s 〜 %s\n", stats.Oldest, stats.Newest)
+	}
+	for _, m := range stats.ByModel {
+		fmt.Printf("  - %-32s prompt %-8s %5d件  ヒット %5d回\n", m.Model, m.PromptVersion, m.Entries, m.Hits)
+	}
+}
+
+func purgeCache(db database.Store, ttl time.Duration, all bool) {
+	if all {
+		ttl = 0
+	}
+	n, err := db.PurgeAnalysisCache(ttl)
+	if err != nil {
+		log.Fatalf("❌ %v", err)
+	}
+	if all {
+		log.Printf("🗑️  分析結果キャッシュを全て削除しました（%d件）", n)
+	} else {
+		log.Printf("🗑️  %sより古いキャッシュを削除しました（%d件）", ttl, n)
+	}
+}
+
+func runEval(analyzer detect.NewsAnalyzer, golden, out, baseline, format string) {
+	examples, err := eval.LoadGoldenSet(golden)
+	if err != nil {
+		log.Fatalf("❌ %v", err)
+	}
+	log.Printf("🧪 評価を実行中: %d件（%s）", len(examples), golden)
+
+	run := eval.Evaluate(analyzer, golden, examples)
+
+	if out == "" {
+		if err := os.MkdirAll("./data/eval", 0755); err != nil {
+			log.Fatalf("❌ ディレクトリ作成エラー: %v", err)
+		}
+		name := fmt.Sprintf("%s-%s.json", time.Now().Format("20060102-150405"), strings.NewReplacer("/", "_", ":", "_", "@", "_").Replace(analyzer.Name()+"-"+analyzer.Model()))
+		out = filepath.Join("./data/eval", name)
+	}
+	if err := eval.SaveRun(out, run); err != nil {
+		log.Fatalf("❌ %v", err)
+	}
+	log.Printf("💾 評価結果を保存: %s", out)
+
+	var diff *eval.RunDiff
+	if baseline != "" {
+		prev, err := eval.LoadRun(baseline)
+		if err != nil {
+			log.Fatalf("❌ %v", err)
+		}
+		diff = eval.Diff(prev, run)
+	}
+
+	if format == "json" {
+		report, _ := json.MarshalIndent(struct {
+			Run  *eval.Run     `json:"run"`
+			Diff *eval.RunDiff `json:"diff,omitempty"`
+		}{run, diff}, "", "  ")
+		fmt.Println(string(report))
+		return
+	}
+
+	fmt.Printf("\n🧪 評価結果: %s\n", run.Label())
+	fmt.Printf("  データ: %s（%d件、失敗 %d件、所要 %s）\n", run.Dataset, len(run.Predictions), run.Errors, run.Duration)
+	printMetrics("感情", run.Sentiment)
+	printMetrics("影響度", run.Impact)
+
+	for _, p := range run.Predictions {
+		if p.Error != "" {
+			fmt.Printf("  ❌ %s %s: %s\n", p.ID, p.Title, p.Error)
+		}
+	}
+
+	if diff == nil {
+		return
+	}
+	fmt.Printf("\n🔍 前回との比較: %s\n", diff.Prev)
+	fmt.Printf("  感情の正解率:   %+.1fpt\n", diff.SentimentDelta*100)
+	fmt.Printf("  影響度の正解率: %+.1fpt\n", diff.ImpactDelta*100)
+	if len(diff.Changes) == 0 {
+		fmt.Println("  予測が変わった記事はありません")
+	}
+	for _, c := range diff.Changes {
+		mark := map[string]string{"fixed": "✅", "broken": "❌", "changed": "🔄"}[c.Status()]
+		fmt.Printf("  %s %s %s: 感情 %s→%s（正解 %s）、影響 %s→%s（正解 %s）\n", mark, c.ID, c.Title,
+			c.Prev.GotSentiment, c.Cur.GotSentiment, c.Cur.WantSentiment,
+			c.Prev.GotImpact, c.Cur.GotImpact, c.Cur.WantImpact)
+	}
+	if len(diff.OnlyPrev)+len(diff.OnlyCur) > 0 {
+		fmt.Printf("  データの差分: 前回のみ %v / 今回のみ %v\n", diff.OnlyPrev, diff.OnlyCur)
+	}
+}
+
+// printMetrics 正解率と混同行列（行: 正解、列: 予測）を表示
+func printMetrics(name string, m eval.Metrics) {
+	fmt.Printf("\n  %s: 正解率 %.1f%%（%d/%d）\n", name, m.Accuracy*100, m.Correct, m.N)
+	// 日本語のラベルは全角2桁分で数えて揃える
+	fmt.Printf("    %s", padRight("正解＼予測", 14))
+	for _, l := range m.Confusion.Labels {
+		fmt.Printf(" %s", padLeft(l, 8))
+	}
+	fmt.Println()
+	for _, want := range m.Confusion.Labels {
+		fmt.Printf("    %s", padRight(want, 14))
+		for _, got := range m.Confusion.Labels {
+			fmt.Printf(" %8d", m.Confusion.Counts[want][got])
+		}
+		fmt.Println()
+	}
+}
+
+// displayWidth 端末での表示幅（ASCII以外の文字は全角として2桁で数える）
+func displayWidth(s string) int {
+	w := 0
+	for _, r := range s {
+		if r < utf8.RuneSelf {
+			w++
+		} else {
+			w += 2
+		}
+	}
+	return w
+}
+
+// padLeft 表示幅がwidthになるよう左を空白で埋める
+func padLeft(s string, width int) string {
+	return strings.Repeat(" ", max(width-displayWidth(s), 0)) + s
+}
+
+// padRight 表示幅がwidthになるよう右を空白で埋める
+func padRight(s string, width int) string {
+	return s + strings.Repeat(" ", max(width-displayWidth(s), 0))
+}
+
+func showUsage(db database.Store, from, to, format string) {
+	today := time.Now().In(timeseries.JST)
+	if to == "" {
+		to = today.Format("2006-01-02")
+	}
+	if from == "" {
+		from = today.AddDate(0, 0, -29).Format("2006-01-02")
+	}
+
+	summary, err := db.GetLLMUsageSummary(from, to)
+	if err != nil {
+		log.Fatalf("❌ %v", err)
+	}
+
+	if format == "json" {
+		out, _ := json.MarshalIndent(summary, "", "  ")
+		fmt.Println(string(out))
+		return
+	}
+
+	fmt.Printf("\n📈 LLM利用量（%s〜%s、日本時間）:\n", from, to)
+	if len(summary) == 0 {
+		fmt.Println("  記録がありません")
+		return
+	}
+
+	var calls, errs, promptTokens, responseTokens int
+	var cost float64
+	for _, u := range summary {
+		fmt.Printf("  %s %-7s %-24s %-13s %4d回（失敗%d） 入力%7d 出力%6d 平均%6.0fms $%.4f\n",
+			u.Day, u.Backend, u.Model, u.Operation, u.Calls, u.Errors,
+			u.PromptTokens, u.ResponseTokens, u.AvgLatencyMs, u.CostUSD)
+		calls += u.Calls
+		errs += u.Errors
+		promptTokens += u.PromptTokens
+		responseTokens += u.ResponseTokens
+		cost += u.CostUSD
+	}
+	fmt.Printf("  合計: %d回（失敗%d） 入力%dトークン 出力%dトークン 推定コスト $%.4f\n",
+		calls, errs, promptTokens, responseTokens, cost)
+}
+
+// splitList カンマ区切りの値（空要素は除く）
+func splitList(s string) []string {
+	var list []string
+	for _, v := range strings.Split(s, ",") {
+		if v = strings.TrimSpace(v); v != "" {
+			list = append(list, v)
+		}
+	}
+	return list
+}
+
+func showStats(db database.Store, q database.AggregateQuery, format string) {
+	prices, err := db.AggregateGasPrices(q)
+	if err != nil {
+		log.Fatalf("❌ %v", err)
+	}
+	rates, err := db.AggregateExchangeRates(q)
+	if err != nil {
+		log.Fatalf("❌ %v", err)
+	}
+
+	if format == "json" {
+		out, _ := json.MarshalIndent(map[string]interface{}{
+			"gas_prices":     prices,
+			"exchange_rates": rates,
+		}, "", "  ")
+		fmt.Println(string(out))
+		return
+	}
+
+	unit := "月次"
+	if q.Period == timeseries.PeriodWeek {
+		unit = "週次（ISO週）"
+	}
+	fmt.Printf("\n📊 %s集計", unit)
+	if q.From != "" || q.To != "" {
+		fmt.Printf("（%s〜%s）", q.From, q.To)
+	}
+	fmt.Println()
+	if len(prices) == 0 && len(rates) == 0 {
+		fmt.Println("📭 データがありません")
+		return
+	}
+
+	for _, ss := range prices {
+		fmt.Printf("\n⛽ %s（%s）円/L\n", ss.Series, ss.Region)
+		printStats(ss.Stats)
+	}
+	for _, ss := range rates {
+		fmt.Printf("\n💱 %s/JPY\n", ss.Series)
+		printStats(ss.Stats)
+	}
+}
+
+func printStats(stats []timeseries.Stats) {
+	fmt.Printf("  %-9s %-10s %4s %8s %8s %8s %8s %7s %9s\n", "期間", "開始日", "件数", "平均", "最小", "最大", "期末", "標準偏差", "変動率(%)")
+	for _, st := range stats {
+		fmt.Printf("  %-9s %-10s %4d %8.2f %8.2f %8.2f %8.2f %7.2f %9.2f\n",
+			st.Period, st.Start, st.Count, st.Avg, st.Min, st.Max, st.Last, st.StdDev, st.Volatility)
+	}
+}
+
+func buildReport(db database.Store, from, to string, days, topNews int, format, out string, notifier notify.Notifier) {
+	if to == "" {
+		to = time.Now().In(timeseries.JST).Format("2006-01-02")
+	}
+	if from == "" {
+		end, err := timeseries.ParseDate(to)
+		if err != nil {
+			log.Fatalf("❌ 終了日の形式エラー: %v", err)
+		}
+		from = timeseries.FormatDate(end.AddDate(0, 0, -(max(days, 1) - 1)))
+	}
+
+	log.Printf("📝 レポートを作成中: %s〜%s", from, to)
+	digest, err := report.Build(db, from, to, report.Options{TopNews: topNews})
+	if err != nil {
+		log.Fatalf("❌ %v", err)
+	}
+
+	var body string
+	switch format {
+	case "html":
+		if body, err = digest.HTML(); err != nil {
+			log.Fatalf("❌ %v", err)
+		}
+	case "json":
+		data, _ := json.MarshalIndent(digest, "", "  ")
+		body = string(data) + "\n"
+	default:
+		body = digest.Markdown()
+	}
+
+	if out != "" {
+		if err := os.MkdirAll(filepath.Dir(out), 0755); err != nil {
+			log.Fatalf("❌ ディレクトリ作成エラー: %v", err)
+		}
+		if err := os.WriteFile(out, []byte(body), 0644); err != nil {
+			log.Fatalf("❌ レポート保存エラー: %v", err)
+		}
+		log.Printf("💾 レポートを保存: %s", out)
+	}
+
+	if notifier != nil {
+		ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+		defer cancel()
+		// 通知先はMarkdownを受け取る（Slackはmrkdwnに変換して送信）
+		if err := notifier.Notify(ctx, notify.Message{Title: digest.Title(), Body: digest.Markdown()}); err != nil {
+			log.Fatalf("❌ %v", err)
+		}
+		log.Printf("📨 レポートを通知しました: %s", notifier.Name())
+	}
+
+	if out == "" && notifier == nil {
+		fmt.Print(body)
+	}
+}
+
+func maintain(db *database.SQLiteClient, opts database.MaintenanceOptions) {
+	report, err := db.Maintain(context.Background(), opts)
+	if err != nil {
+		log.Fatalf("❌ %v", err)
+	}
+
+	if opts.DryRun {
+		fmt.Println("\n🧹 保持期間を過ぎた行（削除はしていません）")
+	} else {
+		fmt.Println("\n🧹 メンテナンス結果")
+	}
+	if len(report.Retention) == 0 {
+		fmt.Println("  保持期間の指定がありません")
+	}
+	for _, r := range report.Retention {
+		fmt.Printf("  %-26s %s より前: %d件\n", r.Table, r.Cutoff, r.Deleted)
+	}
+	if opts.DryRun {
+		return
+	}
+	fmt.Printf("  ANALYZE: %s\n", doneLabel(report.Analyzed))
+	fmt.Printf("  VACUUM:  %s\n", doneLabel(report.Vacuumed))
+	fmt.Printf("  DBサイズ: %.1f MB → %.1f MB\n", float64(report.SizeBefore)/(1<<20), float64(report.SizeAfter)/(1<<20))
+}
+
+func doneLabel(done bool) string {
+	if done {
+		return "実行しました"
+	}
+	return "前回から間隔が経過していないためスキップ"
+}
+
+func backupDB(db *database.SQLiteClient, dbPath, dir string, keep int) {
+	// バックアップ名はDBファイル名（拡張子なし）と日時から付ける
+	base := filepath.Base(strings.TrimPrefix(dbPath, "sqlite://"))
+	prefix := strings.TrimSuffix(base, filepath.Ext(base))
+	dest := database.BackupPath(dir, prefix, time.Now())
+
+	start := time.Now()
+	if err := db.Backup(context.Background(), dest); err != nil {
+		log.Fatalf("❌ %v", err)
+	}
+	info, err := os.Stat(dest)
+	if err != nil {
+		log.Fatalf("❌ %v", err)
+	}
+	log.Printf("💾 バックアップを作成: %s（%.1f MB、%s）", dest, float64(info.Size())/(1<<20), time.Since(start).Round(time.Millisecond))
+
+	removed, err := database.RotateBackups(dir, prefix, keep)
+	if err != nil {
+		log.Fatalf("❌ %v", err)
+	}
+	for _, path := range removed {
+		log.Printf("🗑️  古いバックアップを削除: %s", path)
+	}
+}
+
+// transferFormat 書き出し・取り込みの形式（指定がなければファイルの拡張子から判断し、デフォルトはCSV）
+func transferFormat(format, path string) string {
+	if format != "" && format != "text" {
+		return format
+	}
+	if f := transfer.FormatFromPath(path); f != "" {
+		return f
+	}
+	return transfer.FormatCSV
+}
+
+func exportData(db database.Store, dataset, format, from, to, out string) {
+	format = transferFormat(format, out)
+
+	w := os.Stdout
+	if out != "" {
+		if err := os.MkdirAll(filepath.Dir(out), 0755); err != nil {
+			log.Fatalf("❌ ディレクトリ作成エラー: %v", err)
+		}
+		f, err := os.Create(out)
+		if err != nil {
+			log.Fatalf("❌ ファイル作成エラー: %v", err)
+		}
+		defer f.Close()
+		w = f
+	}
+
+	n, err := transfer.Export(db, dataset, format, from, to, w)
+	if err != nil {
+		log.Fatalf("❌ %v", err)
+	}
+	if out != "" {
+		log.Printf("💾 %sを%d件書き出しました（%s）: %s", dataset, n, format, out)
+	} else {
+		log.Printf("💾 %sを%d件書き出しました（%s）", dataset, n, format)
+	}
+}
+
+func importData(db database.Store, dataset, format, in string) {
+	format = transferFormat(format, in)
+
+	r := os.Stdin
+	if in != "" {
+		f, err := os.Open(in)
+		if err != nil {
+			log.Fatalf("❌ ファイル読み込みエラー: %v", err)
+		}
+		defer f.Close()
+		r = f
+	}
+
+	res, err := transfer.Import(context.Background(), db, dataset, format, r)
+	if err != nil {
+		log.Fatalf("❌ %v", err)
+	}
+	log.Printf("📥 %sを取り込みました（%s）: %d行中 %d件を追加・更新、%d件は記録済みの内容と同じためスキップ",
+		dataset, format, res.Rows, res.Imported, res.Skipped)
+}
+
+func renderChart(db database.Store, opts chart.Options, format, out string) {
+	// 形式の指定がなければ出力先の拡張子から判断（デフォルトはSVG）
+	if format != "svg" && format != "png" {
+		format = "svg"
+		if strings.EqualFold(filepath.Ext(out), ".png") {
+			format = "png"
+		}
+	}
+	if out == "" {
+		out = filepath.Join("./data/charts", fmt.Sprintf("prices-%s.%s", opts.Region, format))
+	}
+
+	c, err := chart.PriceHistory(db, opts)
+	if err != nil {
+		log.Fatalf("❌ %v", err)
+	}
+
+	if err := os.MkdirAll(filepath.Dir(out), 0755); err != nil {
+		log.Fatalf("❌ ディレクトリ作成エラー: %v", err)
+	}
+	f, err := os.Create(out)
+	if err != nil {
+		log.Fatalf("❌ グラフ保存エラー: %v", err)
+	}
+	defer f.Close()
+	if err := c.Write(f, format); err != nil {
+		log.Fatalf("❌ グラフ作成エラー: %v", err)
+	}
+	log.Printf("📊 グラフを保存: %s（%d系列、注記%d件）", out, len(c.Lines), len(c.Annotations))
+}
+
+// checkConfig 設定を検証して有効な値を表示し、終了コードを返す（問題があれば1）
+func checkConfig(cfg *config.Config, loadErr error) int {
+	if loadErr != nil {
+		fmt.Printf("❌ %v\n", loadErr)
+		return 1
+	}
+
+	source := cfg.Path
+	if source == "" {
+		source = "なし（デフォルト値を使用）"
+	}
+	fmt.Printf("\n⚙️  設定ファイル: %s\n", source)
+	if len(cfg.Overrides) > 0 {
+		fmt.Printf("🌱 環境変数で上書き: %s\n", strings.Join(cfg.Overrides, ", "))
+	}
+
+	data, err := cfg.YAML()
+	if err != nil {
+		fmt.Printf("❌ %v\n", err)
+		return 1
+	}
+	fmt.Printf("\n%s", data)
+
+	if lines := cfg.Crontab(cronCommand(cfg.Path)); len(lines) > 0 {
+		fmt.Println("\n⏰ crontab:")
+		for _, line := range lines {
+			fmt.Println(line)
+		}
+	}
+
+	// 実行環境の確認（APIキーや通知先URLが未設定でも設定自体は有効なので警告にとどめる）
+	var warnings []string
+	requireEnv := func(name, usage string) {
+		if os.Getenv(name) == "" {
+			warnings = append(warnings, fmt.Sprintf("%sが設定されていません（%sに必要）", name, usage))
+		}
+	}
+	switch cfg.Analyzer.Backend {
+	case "gemini":
+		requireEnv("GEMINI_API_KEY", "analyzer.backend: gemini")
+	case "openai":
+		requireEnv("OPENAI_API_KEY", "analyzer.backend: openai")
+	}
+	for _, name := range cfg.Sources.News {
+		if name == "newsapi" {
+			requireEnv("NEWSAPI_KEY", "sources.news: newsapi")
+		}
+	}
+	envURLs := map[string]string{"slack": "SLACK_WEBHOOK_URL", "webhook": "NOTIFY_WEBHOOK_URL"}
+	for name, n := range cfg.Notifiers {
+		if n.URL == "" && envURLs[n.Kind] != "" {
+			requireEnv(envURLs[n.Kind], "notifiers."+name)
+		}
+	}
+	if env := envURLs[cfg.Report.Notify]; env != "" {
+		requireEnv(env, "report.notify")
+	}
+	sort.Strings(warnings)
+
+	err = cfg.Validate()
+	if cfg.Analyzer.Backend != "" && cfg.Analyzer.Backend != "mock" {
+		if _, perr := prompt.NewStore(cfg.Analyzer.PromptsDir).Load(detect.NewsPromptName, cfg.Analyzer.PromptVersion); perr != nil {
+			err = errors.Join(err, fmt.Errorf("analyzer: %w", perr))
+		}
+	}
+
+	fmt.Println()
+	for _, w := range warnings {
+		fmt.Printf("⚠️  %s\n", w)
+	}
+	if err != nil {
+		for _, line := range strings.Split(err.Error(), "\n") {
+			fmt.Printf("❌ %s\n", line)
+		}
+		return 1
+	}
+	fmt.Println("✅ 設定に問題はありません")
+	return 0
+}
+
+// cronCommand crontabで実行するコマンド（go runで実行中ならgo runのまま）
+func cronCommand(configPath string) string {
+	wd, _ := os.Getwd()
+	exe, err := os.Executable()
+	if err != nil || strings.Contains(exe, "go-build") {
+		exe = "go run ./cmd/local"
+	}
+	command := fmt.Sprintf("cd %s && %s", wd, exe)
+	if configPath != "" {
+		if abs, err := filepath.Abs(configPath); err == nil {
+			configPath = abs
+		}
+		command += " -config=" + configPath
+	}
+	return command
+}
@@ -0,0 +1,301 @@
+package analysis
+
+import (
+	"encoding/json"
+	"fmt"
+	"math"
+	"time"
+
+	model "gasinsight/internal/model"
+	"gasinsight/internal/timeseries"
+)
+
+const week = 7 * 24 * time.Hour
+
+// Float 計算できない値（NaN）をJSONではnullとして出力する数値
+type Float float64
+
+// MarshalJSON NaN・無限大をnullに変換
+func (f Float) MarshalJSON() ([]byte, error) {
+	v := float64(f)
+	if math.IsNaN(v) || math.IsInf(v, 0) {
+		return []byte("null"), nil
+	}
+	return json.Marshal(v)
+}
+
+// LagCorrelation 指定ラグでの相関係数
+// Lag週前の説明系列の変化と、当週の小売価格の変化の相関
+type LagCorrelation struct {
+	Lag  int   `json:"lag"`
+	Corr Float `json:"corr"`
+	N    int   `json:"n"`
+}
+
+// RollingPoint 移動窓の相関係数
+type RollingPoint struct {
+	WeekStart string `json:"week_start"` // 窓の最終週
+	Corr      Float  `json:"corr"`
+}
+
+// Elasticity 弾性値（説明系列1%変化に対する小売価格の変化率%）
+type Elasticity struct {
+	Lag        int   `json:"lag"`         // 使用したラグ（週）
+	ShortRun   Float `json:"short_run"`   // 週次変化率同士の回帰係数
+	Cumulative Float `json:"cumulative"`  // ラグ0〜Lagの係数の合計
+	LevelRatio Float `json:"level_ratio"` // 対数水準同士の回帰係数
+	R2         Float `json:"r2"`          // 短期回帰の決定係数
+}
+
+// PairResult 系列ペアの分析結果
+type PairResult struct {
+	Driver     string           `json:"driver"` // 説明系列
+	Target     string           `json:"target"` // 小売価格系列
+	Weeks      int              `json:"weeks"`  // 共通する週数
+	CrossCorr  []LagCorrelation `json:"cross_correlation"`
+	BestLag    int              `json:"best_lag"`  // 相関の絶対値が最大のラグ
+	BestCorr   Float            `json:"best_corr"` // そのときの相関係数
+	Rolling    []RollingPoint   `json:"rolling_correlation"`
+	Elasticity Elasticity       `json:"elasticity"`
+}
+
+// Report 相関・ラグ分析の結果
+type Report struct {
+	Fuel   string        `json:"fuel"`
+	Region string        `json:"region"`
+	MaxLag int           `json:"max_lag"`
+	Window int           `json:"window"`
+	Pairs  []*PairResult `json:"pairs"`
+}
+
+// Config 分析の設定
+type Config struct {
+	MaxLag int // 最大ラグ（週）
+	Window int // 移動相関の窓幅（週）
+}
+
+// minPearsonPoints ピアソンの相関係数の計算に必要な最小の点数
+const minPearsonPoints = 3
+
+// Validate 設定値を検証（移動相関には相関を計算できる3週以上の窓が必要）
+func (c Config) Validate() error {
+	if c.Window < minPearsonPoints {
+		return fmt.Errorf("移動相関の窓幅は%d週以上にしてください: %d", minPearsonPoints, c.Window)
+	}
+	if c.MaxLag < 0 {
+		return fmt.Errorf("最大ラグは0週以上にしてください: %d", c.MaxLag)
+	}
+	return nil
+}
+
+// Source 分析に使用するデータの取得元
+type Source interface {
+	GetAllGasPrices() ([]*model.GasPrice, error)
+	GetAllExchangeRates() ([]*model.ExchangeRate, error)
+	GetAllCrudePrices() ([]*model.CrudePrice, error)
+}
+
+// Analyze 小売価格とUSD/JPY・原油（ドル建て/円建て）の相関・ラグ・弾性値を分析
+func Analyze(src Source, fuel, region string, cfg Config) (*Report, error) {
+	if err := cfg.Validate(); err != nil {
+		return nil, err
+	}
+	prices, err := src.GetAllGasPrices()
+	if err != nil {
+		return nil, fmt.Errorf("ガソリン価格取得エラー: %w", err)
+	}
+	rates, err := src.GetAllExchangeRates()
+	if err != nil {
+		return nil, fmt.Errorf("為替レート取得エラー: %w", err)
+	}
+	crude, err := src.GetAllCrudePrices()
+	if err != nil {
+		return nil, fmt.Errorf("原油価格取得エラー: %w", err)
+	}
+
+	retail := timeseries.FromGasPrices(prices, fuel, region)
+	usdJpy := timeseries.FromExchangeRates(rates, "USD")
+	crudeUSD := timeseries.FromCrudePrices(crude)
+
+	drivers := []struct {
+		name   string
+		series timeseries.Series
+	}{
+		{"usdjpy", usdJpy},
+		{"crude_usd", crudeUSD},
+		{"crude_yen", timeseries.CrudeYen(crudeUSD, usdJpy)},
+	}
+
+	report := &Report{Fuel: fuel, Region: region, MaxLag: cfg.MaxLag, Window: cfg.Window}
+	target := retail.Weekly()
+	for _, d := range drivers {
+		if len(d.series) == 0 {
+			continue
+		}
+		report.Pairs = append(report.Pairs, AnalyzePair(d.name, "retail_"+fuel, d.series.Weekly(), target, cfg))
+	}
+
+	if len(report.Pairs) == 0 {
+		return nil, fmt.Errorf("分析可能な為替・原油データがありません")
+	}
+	return report, nil
+}
+
+// AnalyzePair 週次系列ペアの相関・ラグ・弾性値を計算
+// 水準同士の見せかけの相関を避けるため、対数変化率で相関を計算する
+func AnalyzePair(driverName, targetName string, driver, target timeseries.Series, cfg Config) *PairResult {
+	dx := logReturns(driver)
+	dy := logReturns(target)
+
+	result := &PairResult{
+		Driver:   driverName,
+		Target:   targetName,
+		Weeks:    len(alignSeries(indexedReturns{values: driver.Index()}, target, 0)),
+		BestCorr: Float(math.NaN()),
+	}
+
+	for lag := 0; lag <= cfg.MaxLag; lag++ {
+		pairs := alignSeries(dx, dy.series, lag)
+		c := Pearson(split(pairs))
+		result.CrossCorr = append(result.CrossCorr, LagCorrelation{Lag: lag, Corr: Float(c), N: len(pairs)})
+		best := float64(result.BestCorr)
+		if !math.IsNaN(c) && (math.IsNaN(best) || math.Abs(c) > math.Abs(best)) {
+			result.BestLag, result.BestCorr = lag, Float(c)
+		}
+	}
+
+	// 最適ラグでの移動相関（窓幅が3週未満なら相関を計算できないため計算しない）
+	pairs := alignSeries(dx, dy.series, result.BestLag)
+	for i := cfg.Window; cfg.Window >= minPearsonPoints && i <= len(pairs); i++ {
+		x, y := split(pairs[i-cfg.Window : i])
+		result.Rolling = append(result.Rolling, RollingPoint{
+			WeekStart: timeseries.FormatDate(pairs[i-1].date),
+			Corr:      Float(Pearson(x, y)),
+		})
+	}
+
+	result.Elasticity = elasticity(driver, target, dx, dy.series, result.BestLag)
+	return result
+}
+
+// indexedReturns 日付→対数変化率
+type indexedReturns struct {
+	values map[string]float64
+	series timeseries.Series
+}
+
+func logReturns(s timeseries.Series) indexedReturns {
+	var out timeseries.Series
+	for i := 1; i < len(s); i++ {
+		// 欠損週をまたぐ変化は除外
+		if s[i].Date.Sub(s[i-1].Date) != week || s[i-1].Value <= 0 || s[i].Value <= 0 {
+			continue
+		}
+		out = append(out, timeseries.Point{Date: s[i].Date, Value: math.Log(s[i].Value / s[i-1].Value)})
+	}
+	return indexedReturns{values: out.Index(), series: out}
+}
+
+type pair struct {
+	date time.Time
+	x, y float64
+}
+
+// alignSeries 目的系列の各週tについて、説明系列のt-lag週の値と組にする
+func alignSeries(driver indexedReturns, target timeseries.Series, lag int) []pair {
+	var pairs []pair
+	for _, p := range target {
+		x, ok := driver.values[timeseries.FormatDate(p.Date.Add(-time.Duration(lag)*week))]
+		if !ok {
+			continue
+		}
+		pairs = append(pairs, pair{date: p.Date, x: x, y: p.Value})
+	}
+	return pairs
+}
+
+func split(pairs []pair) ([]float64, []float64) {
+	x := make([]float64, len(pairs))
+	y := make([]float64, len(pairs))
+	for i, p := range pairs {
+		x[i], y[i] = p.x, p.y
+	}
+	return x, y
+}
+
+// elasticity 弾性値を推定
+func elasticity(driver, target timeseries.Series, dx indexedReturns, dy timeseries.Series, lag int) Elasticity {
+	e := Elasticity{Lag: lag}
+
+	slope, _, r2 := Regress(split(alignSeries(dx, dy, lag)))
+	e.ShortRun, e.R2 = Float(slope), Float(r2)
+
+	var cumulative float64
+	for k := 0; k <= lag; k++ {
+		slope, _, _ := Regress(split(alignSeries(dx, dy, k)))
+		if !math.IsNaN(slope) {
+			cumulative += slope
+		}
+	}
+	e.Cumulative = Float(cumulative)
+
+	logDriver := map[string]float64{}
+	for _, p := range driver {
+		if p.Value > 0 {
+			logDriver[timeseries.FormatDate(p.Date)] = math.Log(p.Value)
+		}
+	}
+	var logTarget timeseries.Series
+	for _, p := range target {
+		if p.Value > 0 {
+			logTarget = append(logTarget, timeseries.Point{Date: p.Date, Value: math.Log(p.Value)})
+		}
+	}
+	level, _, _ := Regress(split(alignSeries(indexedReturns{values: logDriver}, logTarget, lag)))
+	e.LevelRatio = Float(level)
+
+	return e
+}
+
+// Pearson ピアソンの積率相関係数（計算できない場合はNaN）
+func Pearson(x, y []float64) float64 {
+	if len(x) < minPearsonPoints || len(x) != len(y) {
+		return math.NaN()
+	}
+	mx, my := timeseries.Mean(x), timeseries.Mean(y)
+	var sxy, sxx, syy float64
+	for i := range x {
+		dx, dy := x[i]-mx, y[i]-my
+		sxy += dx * dy
+		sxx += dx * dx
+		syy += dy * dy
+	}
+	if sxx == 0 || syy == 0 {
+		return math.NaN()
+	}
+	return sxy / math.Sqrt(sxx*syy)
+}
+
+// Regress 単回帰 y = a + b x の傾き・切片・決定係数（計算できない場合はNaN）
+func Regress(x, y []float64) (slope, intercept, r2 float64) {
+	if len(x) < 3 || len(x) != len(y) {
+		return math.NaN(), math.NaN(), math.NaN()
+	}
+	mx, my := timeseries.Mean(x), timeseries.Mean(y)
+	var sxy, sxx, syy float64
+	for i := range x {
+		dx, dy := x[i]-mx, y[i]-my
+		sxy += dx * dy
+		sxx += dx * dx
+		syy += dy * dy
+	}
+	if sxx == 0 {
+		return math.NaN(), math.NaN(), math.NaN()
+	}
+	slope = sxy / sxx
+	intercept = my - slope*mx
+	if syy > 0 {
+		r2 = sxy * sxy / (sxx * syy)
+	}
+	return slope, intercept, r2
+}
@@ -0,0 +1,76 @@
+package analysis
+
+import (
+	"math"
+	"testing"
+	"time"
+
+	model "gasinsight/internal/model"
+	"gasinsight/internal/timeseries"
+)
+
+// weekly startから毎週の値の系列
+func weekly(values ...float64) timeseries.Series {
+	start := time.Date(2025, 1, 6, 0, 0, 0, 0, timeseries.JST)
+	s := make(timeseries.Series, len(values))
+	for i, v := range values {
+		s[i] = timeseries.Point{Date: start.Add(time.Duration(i) * week), Value: v}
+	}
+	return s
+}
+
+var (
+	driver = weekly(150, 152, 151, 155, 158, 157, 160, 159, 162, 165, 163, 166)
+	target = weekly(170, 171, 172, 171, 174, 176, 175, 177, 176, 179, 181, 180)
+)
+
+func TestConfigValidate(t *testing.T) {
+	for _, cfg := range []Config{{MaxLag: 8, Window: 12}, {MaxLag: 0, Window: 3}} {
+		if err := cfg.Validate(); err != nil {
+			t.Errorf("%+v: %v", cfg, err)
+		}
+	}
+	for _, cfg := range []Config{{MaxLag: 8, Window: 0}, {MaxLag: 8, Window: 1}, {MaxLag: 8, Window: 2}, {MaxLag: 8, Window: -3}, {MaxLag: -1, Window: 12}} {
+		if err := cfg.Validate(); err == nil {
+			t.Errorf("%+v: エラーになりません", cfg)
+		}
+	}
+}
+
+// emptySource データのない取得元
+type emptySource struct{}
+
+func (emptySource) GetAllGasPrices() ([]*model.GasPrice, error)         { return nil, nil }
+func (emptySource) GetAllExchangeRates() ([]*model.ExchangeRate, error) { return nil, nil }
+func (emptySource) GetAllCrudePrices() ([]*model.CrudePrice, error)     { return nil, nil }
+
+func TestAnalyzeRejectsInvalidConfig(t *testing.T) {
+	if _, err := Analyze(emptySource{}, "regular", "全国平均", Config{MaxLag: 4, Window: 0}); err == nil {
+		t.Error("窓幅0でエラーになりません")
+	}
+	if _, err := Analyze(emptySource{}, "regular", "全国平均", Config{MaxLag: -1, Window: 4}); err == nil {
+		t.Error("負の最大ラグでエラーになりません")
+	}
+}
+
+func TestAnalyzePairRolling(t *testing.T) {
+	r := AnalyzePair("usdjpy", "retail_regular", driver, target, Config{MaxLag: 2, Window: 4})
+	if len(r.CrossCorr) != 3 {
+		t.Errorf("ラグ別相関 = %d件, want 3", len(r.CrossCorr))
+	}
+	// 変化率は11週分で、最適ラグの週数だけ組が減る。窓幅4なら組の数-3点
+	if want := 11 - r.BestLag - 3; len(r.Rolling) != want {
+		t.Errorf("移動相関 = %d点, want %d（最適ラグ%d週）", len(r.Rolling), want, r.BestLag)
+	}
+	if math.IsNaN(float64(r.BestCorr)) {
+		t.Error("最適ラグの相関が計算されていません")
+	}
+
+	// 検証を通さずに呼び出しても、窓幅が3週未満なら移動相関を計算しない（NaNの点を返さない）
+	for _, window := range []int{0, 1, 2, -2} {
+		r := AnalyzePair("usdjpy", "retail_regular", driver, target, Config{MaxLag: 2, Window: window})
+		if len(r.Rolling) != 0 {
+			t.Errorf("window=%d: 移動相関 = %d点, want 0", window, len(r.Rolling))
+		}
+	}
+}
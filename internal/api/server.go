@@ -0,0 +1,465 @@
+package api
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"gasinsight/internal/chart"
+	"gasinsight/internal/database"
+	"gasinsight/internal/pricing"
+	"gasinsight/internal/timeseries"
+)
+
+// Server JSON REST APIサーバー
+type Server struct {
+	db  database.Store
+	mux *http.ServeMux
+}
+
+// response APIレスポンスの共通形式
+type response struct {
+	Code       int         `json:"code"`
+	Data       interface{} `json:"data,omitempty"`
+	NextCursor string      `json:"next_cursor,omitempty"` // 一覧の続きがある場合のカーソル
+	Error      string      `json:"error,omitempty"`
+}
+
+// 一覧APIの件数（limit省略時と上限）
+const (
+	defaultListLimit = 100
+	maxListLimit     = 1000
+)
+
+// NewServer APIサーバーを作成
+func NewServer(db database.Store) *Server {
+	s := &Server{
+		db:  db,
+		mux: http.NewServeMux(),
+	}
+	s.routes()
+	return s
+}
+
+func (s *Server) routes() {
+	s.mux.HandleFunc("GET /health", s.handleHealth)
+	s.mux.HandleFunc("GET /api/gas-prices", s.handleGasPrices)
+	s.mux.HandleFunc("GET /api/gas-prices/latest", s.handleLatestGasPrice)
+	s.mux.HandleFunc("GET /api/gas-prices/decomposition", s.handleDecomposition)
+	s.mux.HandleFunc("GET /api/gas-prices/revisions", s.handleGasPriceRevisions)
+	s.mux.HandleFunc("GET /api/gas-prices/as-of", s.handleGasPricesAsOf)
+	s.mux.HandleFunc("GET /api/exchange-rates", s.handleExchangeRates)
+	s.mux.HandleFunc("GET /api/exchange-rates/latest", s.handleLatestExchangeRate)
+	s.mux.HandleFunc("GET /api/exchange-rates/revisions", s.handleExchangeRateRevisions)
+	s.mux.HandleFunc("GET /api/exchange-rates/as-of", s.handleExchangeRatesAsOf)
+	s.mux.HandleFunc("GET /api/news", s.handleNews)
+	s.mux.HandleFunc("GET /api/news/search", s.handleNewsSearch)
+	s.mux.HandleFunc("GET /api/subsidies", s.handleSubsidies)
+	s.mux.HandleFunc("GET /api/charts/prices", s.handlePriceChart)
+	s.mux.HandleFunc("GET /api/stats/gas-prices", s.handleGasPriceStats)
+	s.mux.HandleFunc("GET /api/stats/exchange-rates", s.handleExchangeRateStats)
+}
+
+// Handler HTTPハンドラーを返す
+func (s *Server) Handler() http.Handler {
+	return s.mux
+}
+
+// ListenAndServe 指定アドレスでサーバーを起動
+func (s *Server) ListenAndServe(addr string) error {
+	srv := &http.Server{
+		Addr:              addr,
+		Handler:           s.mux,
+		ReadHeaderTimeout: 10 * time.Second,
+	}
+	log.Printf("🌐 APIサーバーを起動: %s", addr)
+	return srv.ListenAndServe()
+}
+
+func (s *Server) handleHealth(w http.ResponseWriter, r *http.Request) {
+	writeJSON(w, http.StatusOK, map[string]string{"status": "ok"})
+}
+
+func (s *Server) handleGasPrices(w http.ResponseWriter, r *http.Request) {
+	q, err := listQuery(r)
+	if err != nil {
+		writeError(w, http.StatusBadRequest, err)
+		return
+	}
+	prices, next, err := s.db.ListGasPrices(q)
+	if err != nil {
+		writeListError(w, err)
+		return
+	}
+	writeList(w, prices, next)
+}
+
+func (s *Server) handleLatestGasPrice(w http.ResponseWriter, r *http.Request) {
+	price, err := s.db.GetLatestGasPrice()
+	if err != nil {
+		writeError(w, http.StatusNotFound, err)
+		return
+	}
+	writeJSON(w, http.StatusOK, price)
+}
+
+// handleDecomposition ?region=（省略時は全国平均）&date=YYYY-MM-DD（省略時は最新）のガソリン価格を分解
+func (s *Server) handleDecomposition(w http.ResponseWriter, r *http.Request) {
+	region := r.URL.Query().Get("region")
+	if region == "" {
+		region = "全国平均"
+	}
+
+	price, err := database.FindGasPrice(s.db, region, r.URL.Query().Get("date"))
+	if errors.Is(err, database.ErrInvalidQuery) {
+		writeError(w, http.StatusBadRequest, err)
+		return
+	}
+	if err != nil {
+		writeError(w, http.StatusNotFound, err)
+		return
+	}
+
+	result, err := pricing.DecomposeGasPrice(s.db, price)
+	if err != nil {
+		writeError(w, http.StatusInternalServerError, err)
+		return
+	}
+	writeJSON(w, http.StatusOK, result)
+}
+
+func (s *Server) handleExchangeRates(w http.ResponseWriter, r *http.Request) {
+	q, err := listQuery(r)
+	if err != nil {
+		writeError(w, http.StatusBadRequest, err)
+		return
+	}
+	rates, next, err := s.db.ListExchangeRates(q)
+	if err != nil {
+		writeListError(w, err)
+		return
+	}
+	writeList(w, rates, next)
+}
+
+func (s *Server) handleLatestExchangeRate(w http.ResponseWriter, r *http.Request) {
+	rate, err := s.db.GetLatestExchangeRate()
+	if err != nil {
+		writeError(w, http.StatusNotFound, err)
+		return
+	}
+	writeJSON(w, http.StatusOK, rate)
+}
+
+func (s *Server) handleNews(w http.ResponseWriter, r *http.Request) {
+	q, err := listQuery(r)
+	if err != nil {
+		writeError(w, http.StatusBadRequest, err)
+		return
+	}
+	news, next, err := s.db.ListNews(q)
+	if err != nil {
+		writeListError(w, err)
+		return
+	}
+	writeList(w, news, next)
+}
+
+// handleGasPriceRevisions ガソリン価格の改定履歴
+// クエリ: date（from/toの代わり）, from, to, region, source, as_of
+func (s *Server) handleGasPriceRevisions(w http.ResponseWriter, r *http.Request) {
+	q, err := revisionQuery(r)
+	if err != nil {
+		writeListError(w, err)
+		return
+	}
+	revisions, err := s.db.GasPriceRevisions(q)
+	if err != nil {
+		writeListError(w, err)
+		return
+	}
+	writeList(w, revisions, "")
+}
+
+// handleGasPricesAsOf as_of（省略時は現在）の時点で記録されていたガソリン価格
+func (s *Server) handleGasPricesAsOf(w http.ResponseWriter, r *http.Request) {
+	q, err := revisionQuery(r)
+	if err != nil {
+		writeListError(w, err)
+		return
+	}
+	prices, err := s.db.GasPricesAsOf(q)
+	if err != nil {
+		writeListError(w, err)
+		return
+	}
+	writeList(w, prices, "")
+}
+
+// handleExchangeRateRevisions 為替レートの改定履歴
+// クエリ: date, from, to, currency, source, as_of
+func (s *Server) handleExchangeRateRevisions(w http.ResponseWriter, r *http.Request) {
+	q, err := revisionQuery(r)
+	if err != nil {
+		writeListError(w, err)
+		return
+	}
+	revisions, err := s.db.ExchangeRateRevisions(q)
+	if err != nil {
+		writeListError(w, err)
+		return
+	}
+	writeList(w, revisions, "")
+}
+
+// handleExchangeRatesAsOf as_of（省略時は現在）の時点で記録されていた為替レート
+func (s *Server) handleExchangeRatesAsOf(w http.ResponseWriter, r *http.Request) {
+	q, err := revisionQuery(r)
+	if err != nil {
+		writeListError(w, err)
+		return
+	}
+	rates, err := s.db.ExchangeRatesAsOf(q)
+	if err != nil {
+		writeListError(w, err)
+		return
+	}
+	writeList(w, rates, "")
+}
+
+// revisionQuery 改定履歴APIのクエリを読み取る
+func revisionQuery(r *http.Request) (database.RevisionQuery, error) {
+	v := r.URL.Query()
+	q := database.RevisionQuery{
+		From:     v.Get("from"),
+		To:       v.Get("to"),
+		Region:   v.Get("region"),
+		Currency: v.Get("currency"),
+		Source:   v.Get("source"),
+	}
+	if d := v.Get("date"); d != "" {
+		q.From, q.To = d, d
+	}
+	if a := v.Get("as_of"); a != "" {
+		t, err := database.ParseAsOf(a)
+		if err != nil {
+			return q, err
+		}
+		q.AsOf = t
+	}
+	return q, nil
+}
+
+// handleNewsSearch ニュースの全文検索（関連度順、タイトル・抜粋はHTMLエスケープし、一致箇所は<mark>で強調）
+// クエリ: q（必須、空白区切り）, sentiment, from, to, limit
+func (s *Server) handleNewsSearch(w http.ResponseWriter, r *http.Request) {
+	v := r.URL.Query()
+	q := database.NewsSearchQuery{
+		Query:     v.Get("q"),
+		Sentiment: v.Get("sentiment"),
+		From:      v.Get("from"),
+		To:        v.Get("to"),
+	}
+	if l := v.Get("limit"); l != "" {
+		n, err := strconv.Atoi(l)
+		if err != nil || n < 1 {
+			writeError(w, http.StatusBadRequest, fmt.Errorf("limitは1〜%dで指定してください", database.MaxNewsSearchLimit))
+			return
+		}
+		q.Limit = n
+	}
+	hits, err := s.db.SearchNews(q)
+	if err != nil {
+		writeListError(w, err)
+		return
+	}
+	writeList(w, hits, "")
+}
+
+func (s *Server) handleSubsidies(w http.ResponseWriter, r *http.Request) {
+	subsidies, err := s.db.GetAllFuelSubsidies()
+	if err != nil {
+		writeError(w, http.StatusInternalServerError, err)
+		return
+	}
+	writeJSON(w, http.StatusOK, subsidies)
+}
+
+// handleGasPriceStats ガソリン価格の週次/月次集計
+// クエリ: period（week/month、省略時はmonth）, from, to, region, source, fuels（カンマ区切り）
+func (s *Server) handleGasPriceStats(w http.ResponseWriter, r *http.Request) {
+	stats, err := s.db.AggregateGasPrices(aggregateQuery(r))
+	if err != nil {
+		writeListError(w, err)
+		return
+	}
+	writeJSON(w, http.StatusOK, stats)
+}
+
+// handleExchangeRateStats 為替レートの週次/月次集計
+// クエリ: period, from, to, source, currencies（カンマ区切り）
+func (s *Server) handleExchangeRateStats(w http.ResponseWriter, r *http.Request) {
+	stats, err := s.db.AggregateExchangeRates(aggregateQuery(r))
+	if err != nil {
+		writeListError(w, err)
+		return
+	}
+	writeJSON(w, http.StatusOK, stats)
+}
+
+// aggregateQuery 集計APIのクエリを読み取る
+func aggregateQuery(r *http.Request) database.AggregateQuery {
+	v := r.URL.Query()
+	q := database.AggregateQuery{
+		Period:     timeseries.Period(v.Get("period")),
+		From:       v.Get("from"),
+		To:         v.Get("to"),
+		Region:     v.Get("region"),
+		Source:     v.Get("source"),
+		Fuels:      splitParam(v.Get("fuels")),
+		Currencies: splitParam(v.Get("currencies")),
+	}
+	if q.Period == "" {
+		q.Period = timeseries.PeriodMonth
+	}
+	return q
+}
+
+// splitParam カンマ区切りのクエリ値
+func splitParam(s string) []string {
+	var list []string
+	for _, v := range strings.Split(s, ",") {
+		if v = strings.TrimSpace(v); v != "" {
+			list = append(list, v)
+		}
+	}
+	return list
+}
+
+// handlePriceChart 価格推移グラフ（SVG/PNG）
+// クエリ: region, fuels（カンマ区切り）, currency（noneで為替なし）, from, to, format（svg/png）, width, height
+func (s *Server) handlePriceChart(w http.ResponseWriter, r *http.Request) {
+	q := r.URL.Query()
+	opts := chart.Options{
+		Region:   q.Get("region"),
+		Fuels:    chart.SplitFuels(q.Get("fuels")),
+		Currency: q.Get("currency"),
+		From:     q.Get("from"),
+		To:       q.Get("to"),
+	}
+	if opts.Region == "" {
+		opts.Region = "全国平均"
+	}
+	if opts.Currency == "" {
+		opts.Currency = "USD"
+	}
+	for _, dim := range []struct {
+		name string
+		dst  *int
+	}{{"width", &opts.Width}, {"height", &opts.Height}} {
+		if v := q.Get(dim.name); v != "" {
+			n, err := strconv.Atoi(v)
+			if err != nil || n < 200 || n > 4000 {
+				writeError(w, http.StatusBadRequest, fmt.Errorf("%sは200〜4000で指定してください", dim.name))
+				return
+			}
+			*dim.dst = n
+		}
+	}
+	format := q.Get("format")
+	if format == "" {
+		format = "svg"
+	}
+	if format != "svg" && format != "png" {
+		writeError(w, http.StatusBadRequest, fmt.Errorf("不明な画像形式: %s（svg/png）", format))
+		return
+	}
+
+	c, err := chart.PriceHistory(s.db, opts)
+	if err != nil {
+		writeError(w, http.StatusBadRequest, err)
+		return
+	}
+	var buf bytes.Buffer
+	if err := c.Write(&buf, format); err != nil {
+		writeError(w, http.StatusInternalServerError, err)
+		return
+	}
+	w.Header().Set("Content-Type", chart.ContentType(format))
+	w.WriteHeader(http.StatusOK)
+	if _, err := w.Write(buf.Bytes()); err != nil {
+		log.Printf("⚠️  レスポンス書き込みエラー: %v", err)
+	}
+}
+
+// listQuery 一覧APIのクエリ（from, to, region, source, sort, limit, offset, cursor）を読み取る
+func listQuery(r *http.Request) (database.ListQuery, error) {
+	v := r.URL.Query()
+	q := database.ListQuery{
+		From:   v.Get("from"),
+		To:     v.Get("to"),
+		Region: v.Get("region"),
+		Source: v.Get("source"),
+		Sort:   v.Get("sort"),
+		Cursor: v.Get("cursor"),
+		Limit:  defaultListLimit,
+	}
+	if s := v.Get("limit"); s != "" {
+		n, err := strconv.Atoi(s)
+		if err != nil || n < 1 || n > maxListLimit {
+			return q, fmt.Errorf("limitは1〜%dで指定してください", maxListLimit)
+		}
+		q.Limit = n
+	}
+	if s := v.Get("offset"); s != "" {
+		n, err := strconv.Atoi(s)
+		if err != nil || n < 0 {
+			return q, fmt.Errorf("offsetは0以上で指定してください")
+		}
+		q.Offset = n
+	}
+	return q, nil
+}
+
+// writeList 一覧と次ページのカーソルを返す（データがなくても空配列を返す）
+func writeList[T any](w http.ResponseWriter, items []T, next string) {
+	if items == nil {
+		items = []T{}
+	}
+	w.Header().Set("Content-Type", "application/json; charset=utf-8")
+	w.WriteHeader(http.StatusOK)
+	if err := json.NewEncoder(w).Encode(response{Code: http.StatusOK, Data: items, NextCursor: next}); err != nil {
+		log.Printf("⚠️  レスポンス書き込みエラー: %v", err)
+	}
+}
+
+// writeListError 条件の誤りは400、それ以外は500で返す
+func writeListError(w http.ResponseWriter, err error) {
+	status := http.StatusInternalServerError
+	if errors.Is(err, database.ErrInvalidQuery) {
+		status = http.StatusBadRequest
+	}
+	writeError(w, status, err)
+}
+
+func writeJSON(w http.ResponseWriter, status int, data interface{}) {
+	w.Header().Set("Content-Type", "application/json; charset=utf-8")
+	w.WriteHeader(status)
+	if err := json.NewEncoder(w).Encode(response{Code: status, Data: data}); err != nil {
+		log.Printf("⚠️  レスポンス書き込みエラー: %v", err)
+	}
+}
+
+func writeError(w http.ResponseWriter, status int, err error) {
+	w.Header().Set("Content-Type", "application/json; charset=utf-8")
+	w.WriteHeader(status)
+	if err := json.NewEncoder(w).Encode(response{Code: status, Error: err.Error()}); err != nil {
+		log.Printf("⚠️  レスポンス書き込みエラー: %v", err)
+	}
+}
@@ -0,0 +1,288 @@
+// Package chart ガソリン価格・為替の推移をSVG/PNGの折れ線グラフとして描画する
+package chart
+
+import (
+	"fmt"
+	"io"
+	"math"
+	"strings"
+	"time"
+
+	"gasinsight/internal/timeseries"
+)
+
+// デフォルトの画像サイズ（px）
+const (
+	DefaultWidth  = 960
+	DefaultHeight = 480
+)
+
+// Axis 系列を対応させる縦軸
+type Axis int
+
+const (
+	AxisLeft  Axis = iota // 左軸（ガソリン価格）
+	AxisRight             // 右軸（為替）
+)
+
+// Line 折れ線1本
+type Line struct {
+	Name   string
+	Series timeseries.Series
+	Axis   Axis
+	Dashed bool
+}
+
+// Annotation 日付に付ける注記（しきい値を超えた変動など）
+type Annotation struct {
+	Date  time.Time
+	Value float64 // 左軸上のマーカー位置（0なら縦線のみ）
+	Label string
+}
+
+// Chart 折れ線グラフ
+type Chart struct {
+	Title       string
+	Width       int
+	Height      int
+	LeftLabel   string // 左軸の単位
+	RightLabel  string // 右軸の単位
+	Lines       []Line
+	Annotations []Annotation
+}
+
+// palette 系列の色（Lineの順に割り当てる）
+var palette = []string{"#d62728", "#1f77b4", "#2ca02c", "#ff7f0e", "#9467bd", "#8c564b"}
+
+// annotationColor 注記の色
+const annotationColor = "#7f7f7f"
+
+// rect 描画領域
+type rect struct{ x0, y0, x1, y1 float64 }
+
+// scale 値→座標の変換
+type scale struct {
+	min, max float64
+	from, to float64 // 座標（縦軸はfromが下端）
+}
+
+func (s scale) pos(v float64) float64 {
+	if s.max == s.min {
+		return (s.from + s.to) / 2
+	}
+	return s.from + (v-s.min)/(s.max-s.min)*(s.to-s.from)
+}
+
+// tick 目盛り
+type tick struct {
+	pos   float64
+	label string
+}
+
+// layout 描画に必要な座標系（SVG/PNG共通）
+type layout struct {
+	plot   rect
+	x      scale
+	left   scale
+	right  scale
+	xTicks []tick
+	lTicks []tick
+	rTicks []tick
+	hasR   bool
+}
+
+// Validate 描画できるデータがあるか確認
+func (c *Chart) Validate() error {
+	for _, l := range c.Lines {
+		if len(l.Series) > 0 {
+			return nil
+		}
+	}
+	return fmt.Errorf("グラフに描画するデータがありません")
+}
+
+// Write 形式（svg/png）を指定して出力
+func (c *Chart) Write(w io.Writer, format string) error {
+	switch format {
+	case "svg":
+		return c.WriteSVG(w)
+	case "png":
+		return c.WritePNG(w)
+	}
+	return fmt.Errorf("不明な画像形式: %s（svg/png）", format)
+}
+
+// ContentType 形式に対応するContent-Type
+func ContentType(format string) string {
+	if format == "png" {
+		return "image/png"
+	}
+	return "image/svg+xml"
+}
+
+// dateRange 全系列の最初と最後の日付
+func (c *Chart) dateRange() (first, last time.Time, ok bool) {
+	for _, l := range c.Lines {
+		if len(l.Series) == 0 {
+			continue
+		}
+		if !ok || l.Series[0].Date.Before(first) {
+			first = l.Series[0].Date
+		}
+		if end := l.Series[len(l.Series)-1].Date; !ok || end.After(last) {
+			last = end
+		}
+		ok = true
+	}
+	return first, last, ok
+}
+
+// annotationPos 注記の座標（x、マーカーのy、ラベルを左側に置くか）。描画範囲外ならok=false
+func (l layout) annotationPos(a Annotation) (x, y float64, marker, leftLabel, ok bool) {
+	x = l.x.pos(float64(a.Date.Unix()))
+	if x < l.plot.x0 || x > l.plot.x1 {
+		return 0, 0, false, false, false
+	}
+	y = l.left.pos(a.Value)
+	marker = a.Value != 0 && y >= l.plot.y0 && y <= l.plot.y1
+	leftLabel = x > l.plot.x1-60
+	return x, y, marker, leftLabel, true
+}
+
+// size 画像サイズ（未指定ならデフォルト）
+func (c *Chart) size() (int, int) {
+	w, h := c.Width, c.Height
+	if w <= 0 {
+		w = DefaultWidth
+	}
+	if h <= 0 {
+		h = DefaultHeight
+	}
+	return w, h
+}
+
+// layout 系列の範囲から軸と目盛りを計算
+func (c *Chart) layout() layout {
+	w, h := c.size()
+	var l layout
+
+	tMin, tMax := math.Inf(1), math.Inf(-1)
+	lMin, lMax := math.Inf(1), math.Inf(-1)
+	rMin, rMax := math.Inf(1), math.Inf(-1)
+	for _, line := range c.Lines {
+		for _, p := range line.Series {
+			t := float64(p.Date.Unix())
+			tMin, tMax = math.Min(tMin, t), math.Max(tMax, t)
+			if line.Axis == AxisRight {
+				rMin, rMax = math.Min(rMin, p.Value), math.Max(rMax, p.Value)
+				l.hasR = true
+			} else {
+				lMin, lMax = math.Min(lMin, p.Value), math.Max(lMax, p.Value)
+			}
+		}
+	}
+	if math.IsInf(tMin, 1) {
+		tMin, tMax = 0, 0
+	}
+	if math.IsInf(lMin, 1) {
+		lMin, lMax = 0, 1
+	}
+
+	right := 20.0
+	if l.hasR {
+		right = 70
+	}
+	l.plot = rect{x0: 70, y0: 50, x1: float64(w) - right, y1: float64(h) - 60}
+
+	l.x = scale{min: tMin, max: tMax, from: l.plot.x0, to: l.plot.x1}
+	lTicks, lLo, lHi := niceTicks(lMin, lMax, 6)
+	l.left = scale{min: lLo, max: lHi, from: l.plot.y1, to: l.plot.y0}
+	for _, v := range lTicks {
+		l.lTicks = append(l.lTicks, tick{pos: l.left.pos(v), label: formatTick(v)})
+	}
+	if l.hasR {
+		rTicks, rLo, rHi := niceTicks(rMin, rMax, 6)
+		l.right = scale{min: rLo, max: rHi, from: l.plot.y1, to: l.plot.y0}
+		for _, v := range rTicks {
+			l.rTicks = append(l.rTicks, tick{pos: l.right.pos(v), label: formatTick(v)})
+		}
+	}
+
+	// 日付の目盛り（等間隔に最大6個）
+	n := 5
+	if tMax == tMin {
+		n = 0
+	}
+	for i := 0; i <= n; i++ {
+		t := tMin
+		if n > 0 {
+			t += (tMax - tMin) * float64(i) / float64(n)
+		}
+		l.xTicks = append(l.xTicks, tick{
+			pos:   l.x.pos(t),
+			label: timeseries.FormatDate(time.Unix(int64(t), 0)),
+		})
+	}
+	return l
+}
+
+// scaleFor 系列の軸に対応するスケール
+func (l layout) scaleFor(a Axis) scale {
+	if a == AxisRight {
+		return l.right
+	}
+	return l.left
+}
+
+// points 系列を座標に変換
+func (l layout) points(line Line) [][2]float64 {
+	s := l.scaleFor(line.Axis)
+	pts := make([][2]float64, 0, len(line.Series))
+	for _, p := range line.Series {
+		pts = append(pts, [2]float64{l.x.pos(float64(p.Date.Unix())), s.pos(p.Value)})
+	}
+	return pts
+}
+
+// legendLabel 凡例の表示（右軸の系列は軸を明記）
+func legendLabel(line Line) string {
+	if line.Axis == AxisRight {
+		return line.Name + " (右軸)"
+	}
+	return line.Name
+}
+
+// niceTicks 1, 2, 5 × 10^n 刻みの目盛りと、それを含む軸の範囲
+func niceTicks(lo, hi float64, n int) ([]float64, float64, float64) {
+	if hi == lo {
+		pad := math.Max(math.Abs(lo)*0.01, 1)
+		lo, hi = lo-pad, hi+pad
+	}
+	raw := (hi - lo) / float64(n)
+	mag := math.Pow(10, math.Floor(math.Log10(raw)))
+	step := mag * 10
+	for _, m := range []float64{1, 2, 5, 10} {
+		if raw <= m*mag {
+			step = m * mag
+			break
+		}
+	}
+
+	start := math.Floor(lo/step) * step
+	end := math.Ceil(hi/step) * step
+	var ticks []float64
+	for v := start; v <= end+step/2; v += step {
+		ticks = append(ticks, math.Round(v/step)*step)
+	}
+	return ticks, start, end
+}
+
+// formatTick 目盛りの数値表示（不要な小数点以下は省く）
+func formatTick(v float64) string {
+	s := fmt.Sprintf("%.2f", v)
+	s = strings.TrimRight(strings.TrimRight(s, "0"), ".")
+	if s == "-0" {
+		return "0"
+	}
+	return s
+}
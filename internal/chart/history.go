@@ -0,0 +1,144 @@
+package chart
+
+import (
+	"fmt"
+	"strings"
+
+	model "gasinsight/internal/model"
+	"gasinsight/internal/timeseries"
+)
+
+// DefaultFuels グラフに描く燃料種別
+var DefaultFuels = []string{"regular", "premium", "diesel"}
+
+// Source グラフに使用するデータの取得元
+type Source interface {
+	GetAllGasPrices() ([]*model.GasPrice, error)
+	GetAllExchangeRates() ([]*model.ExchangeRate, error)
+	GetFlaggedPriceChangesBetween(from, to string) ([]*model.PriceChangeRecord, error)
+}
+
+// SplitFuels カンマ区切りの燃料種別（空ならDefaultFuels）
+func SplitFuels(s string) []string {
+	var fuels []string
+	for _, f := range strings.Split(s, ",") {
+		if f = strings.TrimSpace(f); f != "" {
+			fuels = append(fuels, f)
+		}
+	}
+	if len(fuels) == 0 {
+		return DefaultFuels
+	}
+	return fuels
+}
+
+// Options 価格推移グラフの設定
+type Options struct {
+	Region   string   // 地域
+	Fuels    []string // 燃料種別（空ならDefaultFuels）
+	Currency string   // 右軸に重ねる通貨（USD/EUR/GBP/CNY、空またはnoneなら重ねない）
+	From     string   // 期間の開始日（YYYY-MM-DD、空なら最初から）
+	To       string   // 期間の終了日（空なら最後まで）
+	Width    int
+	Height   int
+}
+
+// PriceHistory 燃料種別ごとの価格推移に為替を右軸で重ね、しきい値を超えた変動を注記したグラフ
+func PriceHistory(src Source, opts Options) (*Chart, error) {
+	if len(opts.Fuels) == 0 {
+		opts.Fuels = DefaultFuels
+	}
+	for _, f := range opts.Fuels {
+		if f != "regular" && f != "premium" && f != "diesel" {
+			return nil, fmt.Errorf("不明な燃料種別: %s（regular/premium/diesel）", f)
+		}
+	}
+	if opts.Currency == "none" {
+		opts.Currency = ""
+	}
+	switch opts.Currency {
+	case "", "USD", "EUR", "GBP", "CNY":
+	default:
+		return nil, fmt.Errorf("不明な通貨: %s（USD/EUR/GBP/CNY）", opts.Currency)
+	}
+
+	prices, err := src.GetAllGasPrices()
+	if err != nil {
+		return nil, fmt.Errorf("ガソリン価格取得エラー: %w", err)
+	}
+
+	c := &Chart{
+		Title:     "Gas prices (" + opts.Region + ")",
+		Width:     opts.Width,
+		Height:    opts.Height,
+		LeftLabel: "JPY/L",
+	}
+	for _, fuel := range opts.Fuels {
+		s := between(timeseries.FromGasPrices(prices, fuel, opts.Region), opts.From, opts.To)
+		c.Lines = append(c.Lines, Line{Name: fuel, Series: s, Axis: AxisLeft})
+	}
+	// 為替だけのグラフにならないよう、価格の系列があることを先に確認する
+	if err := c.Validate(); err != nil {
+		return nil, fmt.Errorf("%w（地域: %s）", err, opts.Region)
+	}
+	if first, last, ok := c.dateRange(); ok {
+		c.Title += " " + timeseries.FormatDate(first) + " - " + timeseries.FormatDate(last)
+	}
+
+	if opts.Currency != "" {
+		rates, err := src.GetAllExchangeRates()
+		if err != nil {
+			return nil, fmt.Errorf("為替レート取得エラー: %w", err)
+		}
+		c.RightLabel = opts.Currency + "/JPY"
+		c.Lines = append(c.Lines, Line{
+			Name:   opts.Currency + "/JPY",
+			Series: between(timeseries.FromExchangeRates(rates, opts.Currency), opts.From, opts.To),
+			Axis:   AxisRight,
+			Dashed: true,
+		})
+	}
+
+	from, to := opts.From, opts.To
+	if from == "" {
+		from = "0000-01-01"
+	}
+	if to == "" {
+		to = "9999-12-31"
+	}
+	changes, err := src.GetFlaggedPriceChangesBetween(from, to)
+	if err != nil {
+		return nil, err
+	}
+	for _, ch := range changes {
+		if ch.Region != opts.Region {
+			continue
+		}
+		t, err := timeseries.ParseDate(ch.DateNew)
+		if err != nil {
+			continue
+		}
+		// 変動検知はレギュラー価格で判定しているため、レギュラーを描く場合のみマーカーを付ける
+		a := Annotation{Date: t, Label: fmt.Sprintf("%+.1f%%", ch.PctChange)}
+		for _, f := range opts.Fuels {
+			if f == "regular" {
+				a.Value = ch.PriceNew
+			}
+		}
+		c.Annotations = append(c.Annotations, a)
+	}
+	return c, nil
+}
+
+// between 期間内の点のみ
+func between(s timeseries.Series, from, to string) timeseries.Series {
+	var out timeseries.Series
+	for _, p := range s {
+		d := timeseries.FormatDate(p.Date)
+		if (from != "" && d < from) || (to != "" && d > to) {
+			continue
+		}
+		out = append(out, p)
+	}
+	return out
+}
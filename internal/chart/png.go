@@ -0,0 +1,179 @@
+package chart
+
+import (
+	"image"
+	"image/color"
+	"image/draw"
+	"image/png"
+	"io"
+	"math"
+	"strconv"
+	"strings"
+
+	"golang.org/x/image/font"
+	"golang.org/x/image/font/basicfont"
+	"golang.org/x/image/math/fixed"
+)
+
+// WritePNG グラフをPNGで出力
+// 組み込みのビットマップフォントはASCIIのみのため、日本語の文字は省略して描画する（日本語はSVGを推奨）
+func (c *Chart) WritePNG(w io.Writer) error {
+	if err := c.Validate(); err != nil {
+		return err
+	}
+	width, height := c.size()
+	l := c.layout()
+
+	img := image.NewRGBA(image.Rect(0, 0, width, height))
+	draw.Draw(img, img.Bounds(), image.White, image.Point{}, draw.Src)
+	cv := &canvas{img: img}
+
+	grid := hexColor("#e0e0e0")
+	axis := hexColor("#999999")
+	text := hexColor("#222222")
+
+	if c.Title != "" {
+		cv.text(l.plot.x0, 26, c.Title, text, alignLeft)
+	}
+	for _, t := range l.lTicks {
+		cv.line(l.plot.x0, t.pos, l.plot.x1, t.pos, 1, false, grid)
+		cv.text(l.plot.x0-6, t.pos+4, t.label, text, alignRight)
+	}
+	for _, t := range l.rTicks {
+		cv.text(l.plot.x1+6, t.pos+4, t.label, text, alignLeft)
+	}
+	for _, t := range l.xTicks {
+		cv.line(t.pos, l.plot.y1, t.pos, l.plot.y1+4, 1, false, axis)
+		cv.text(t.pos, l.plot.y1+18, t.label, text, alignCenter)
+	}
+	cv.line(l.plot.x0, l.plot.y0, l.plot.x1, l.plot.y0, 1, false, axis)
+	cv.line(l.plot.x0, l.plot.y1, l.plot.x1, l.plot.y1, 1, false, axis)
+	cv.line(l.plot.x0, l.plot.y0, l.plot.x0, l.plot.y1, 1, false, axis)
+	cv.line(l.plot.x1, l.plot.y0, l.plot.x1, l.plot.y1, 1, false, axis)
+	cv.text(l.plot.x0, l.plot.y0-8, c.LeftLabel, text, alignCenter)
+	if l.hasR {
+		cv.text(l.plot.x1, l.plot.y0-8, c.RightLabel, text, alignCenter)
+	}
+
+	ann := hexColor(annotationColor)
+	for _, a := range c.Annotations {
+		x, y, marker, leftLabel, ok := l.annotationPos(a)
+		if !ok {
+			continue
+		}
+		cv.line(x, l.plot.y0, x, l.plot.y1, 1, true, ann)
+		if marker {
+			cv.circle(x, y, 5, ann)
+		}
+		if leftLabel {
+			cv.text(x-4, l.plot.y0+12, a.Label, ann, alignRight)
+		} else {
+			cv.text(x+4, l.plot.y0+12, a.Label, ann, alignLeft)
+		}
+	}
+
+	for i, line := range c.Lines {
+		col := hexColor(palette[i%len(palette)])
+		pts := l.points(line)
+		for j := 1; j < len(pts); j++ {
+			cv.line(pts[j-1][0], pts[j-1][1], pts[j][0], pts[j][1], 2, line.Dashed, col)
+		}
+	}
+
+	x := l.plot.x0
+	for i, line := range c.Lines {
+		col := hexColor(palette[i%len(palette)])
+		draw.Draw(img, image.Rect(int(x), height-20, int(x)+14, height-16), image.NewUniform(col), image.Point{}, draw.Src)
+		label := asciiOnly(line.Name)
+		if line.Axis == AxisRight {
+			label += " (R)"
+		}
+		cv.text(x+18, float64(height)-13, label, text, alignLeft)
+		x += 30 + float64(len(label))*7
+	}
+
+	return png.Encode(w, img)
+}
+
+// align 文字の揃え位置
+type align int
+
+const (
+	alignLeft align = iota
+	alignCenter
+	alignRight
+)
+
+// canvas 線・円・文字の簡易描画
+type canvas struct {
+	img *image.RGBA
+}
+
+// line 太さwidthの線分（dashedなら破線）
+func (cv *canvas) line(x0, y0, x1, y1 float64, width int, dashed bool, col color.RGBA) {
+	dx, dy := x1-x0, y1-y0
+	steps := int(math.Max(math.Abs(dx), math.Abs(dy)))
+	if steps == 0 {
+		steps = 1
+	}
+	for i := 0; i <= steps; i++ {
+		if dashed && (i/4)%2 == 1 {
+			continue
+		}
+		t := float64(i) / float64(steps)
+		cv.dot(int(math.Round(x0+dx*t)), int(math.Round(y0+dy*t)), width, col)
+	}
+}
+
+// circle 半径rの円周
+func (cv *canvas) circle(cx, cy, r float64, col color.RGBA) {
+	for a := 0.0; a < 2*math.Pi; a += 1 / r / 2 {
+		cv.dot(int(math.Round(cx+r*math.Cos(a))), int(math.Round(cy+r*math.Sin(a))), 2, col)
+	}
+}
+
+// dot 幅widthの点
+func (cv *canvas) dot(x, y, width int, col color.RGBA) {
+	for i := 0; i < width; i++ {
+		for j := 0; j < width; j++ {
+			if (image.Point{x + i, y + j}).In(cv.img.Rect) {
+				cv.img.SetRGBA(x+i, y+j, col)
+			}
+		}
+	}
+}
+
+// text ベースラインy、揃え位置alignでASCII文字を描画
+func (cv *canvas) text(x, y float64, s string, col color.RGBA, a align) {
+	s = asciiOnly(s)
+	if s == "" {
+		return
+	}
+	d := &font.Drawer{Dst: cv.img, Src: image.NewUniform(col), Face: basicfont.Face7x13}
+	width := float64(d.MeasureString(s).Round())
+	switch a {
+	case alignCenter:
+		x -= width / 2
+	case alignRight:
+		x -= width
+	}
+	d.Dot = fixed.P(int(x), int(y))
+	d.DrawString(s)
+}
+
+// asciiOnly ビットマップフォントで描画できない文字を除く
+func asciiOnly(s string) string {
+	var b strings.Builder
+	for _, r := range s {
+		if r >= 0x20 && r < 0x7f {
+			b.WriteRune(r)
+		}
+	}
+	return strings.Join(strings.Fields(strings.ReplaceAll(b.String(), "()", "")), " ")
+}
+
+// hexColor #rrggbb形式の色
+func hexColor(s string) color.RGBA {
+	v, _ := strconv.ParseUint(strings.TrimPrefix(s, "#"), 16, 32)
+	return color.RGBA{R: uint8(v >> 16), G: uint8(v >> 8), B: uint8(v), A: 0xff}
+}
@@ -0,0 +1,102 @@
+package chart
+
+import (
+	"bytes"
+	"fmt"
+	"html"
+	"io"
+	"strings"
+)
+
+// WriteSVG グラフをSVGで出力
+func (c *Chart) WriteSVG(w io.Writer) error {
+	if err := c.Validate(); err != nil {
+		return err
+	}
+	width, height := c.size()
+	l := c.layout()
+
+	var b bytes.Buffer
+	fmt.Fprintf(&b, `<svg xmlns="http://www.w3.org/2000/svg" width="%d" height="%d" viewBox="0 0 %d %d" font-family="sans-serif" font-size="12">`+"\n",
+		width, height, width, height)
+	fmt.Fprintf(&b, `<rect width="100%%" height="100%%" fill="#ffffff"/>`+"\n")
+	if c.Title != "" {
+		fmt.Fprintf(&b, `<text x="%.1f" y="24" font-size="16" font-weight="bold">%s</text>`+"\n", l.plot.x0, esc(c.Title))
+	}
+
+	// 目盛りと補助線
+	for _, t := range l.lTicks {
+		fmt.Fprintf(&b, `<line x1="%.1f" y1="%.1f" x2="%.1f" y2="%.1f" stroke="#e0e0e0"/>`+"\n", l.plot.x0, t.pos, l.plot.x1, t.pos)
+		fmt.Fprintf(&b, `<text x="%.1f" y="%.1f" text-anchor="end">%s</text>`+"\n", l.plot.x0-6, t.pos+4, t.label)
+	}
+	for _, t := range l.rTicks {
+		fmt.Fprintf(&b, `<text x="%.1f" y="%.1f">%s</text>`+"\n", l.plot.x1+6, t.pos+4, t.label)
+	}
+	for _, t := range l.xTicks {
+		fmt.Fprintf(&b, `<line x1="%.1f" y1="%.1f" x2="%.1f" y2="%.1f" stroke="#999"/>`+"\n", t.pos, l.plot.y1, t.pos, l.plot.y1+4)
+		fmt.Fprintf(&b, `<text x="%.1f" y="%.1f" text-anchor="middle">%s</text>`+"\n", t.pos, l.plot.y1+18, t.label)
+	}
+	fmt.Fprintf(&b, `<rect x="%.1f" y="%.1f" width="%.1f" height="%.1f" fill="none" stroke="#999"/>`+"\n",
+		l.plot.x0, l.plot.y0, l.plot.x1-l.plot.x0, l.plot.y1-l.plot.y0)
+	if c.LeftLabel != "" {
+		fmt.Fprintf(&b, `<text x="%.1f" y="%.1f" text-anchor="middle">%s</text>`+"\n", l.plot.x0, l.plot.y0-8, esc(c.LeftLabel))
+	}
+	if l.hasR && c.RightLabel != "" {
+		fmt.Fprintf(&b, `<text x="%.1f" y="%.1f" text-anchor="middle">%s</text>`+"\n", l.plot.x1, l.plot.y0-8, esc(c.RightLabel))
+	}
+
+	// 注記（縦の破線とマーカー）
+	for _, a := range c.Annotations {
+		x, y, marker, leftLabel, ok := l.annotationPos(a)
+		if !ok {
+			continue
+		}
+		fmt.Fprintf(&b, `<line x1="%.1f" y1="%.1f" x2="%.1f" y2="%.1f" stroke="%s" stroke-dasharray="4 3"/>`+"\n",
+			x, l.plot.y0, x, l.plot.y1, annotationColor)
+		if marker {
+			fmt.Fprintf(&b, `<circle cx="%.1f" cy="%.1f" r="5" fill="none" stroke="%s" stroke-width="2"/>`+"\n",
+				x, y, annotationColor)
+		}
+		if a.Label != "" {
+			lx, anchor := x+4, "start"
+			if leftLabel {
+				lx, anchor = x-4, "end"
+			}
+			fmt.Fprintf(&b, `<text x="%.1f" y="%.1f" font-size="11" fill="%s" text-anchor="%s">%s</text>`+"\n",
+				lx, l.plot.y0+12, annotationColor, anchor, esc(a.Label))
+		}
+	}
+
+	// 折れ線
+	for i, line := range c.Lines {
+		pts := l.points(line)
+		if len(pts) == 0 {
+			continue
+		}
+		coords := make([]string, len(pts))
+		for j, p := range pts {
+			coords[j] = fmt.Sprintf("%.1f,%.1f", p[0], p[1])
+		}
+		dash := ""
+		if line.Dashed {
+			dash = ` stroke-dasharray="6 4"`
+		}
+		fmt.Fprintf(&b, `<polyline fill="none" stroke="%s" stroke-width="2"%s points="%s"/>`+"\n",
+			palette[i%len(palette)], dash, strings.Join(coords, " "))
+	}
+
+	// 凡例
+	x := l.plot.x0
+	for i, line := range c.Lines {
+		label := legendLabel(line)
+		fmt.Fprintf(&b, `<rect x="%.1f" y="%.1f" width="14" height="4" fill="%s"/>`+"\n", x, float64(height)-20, palette[i%len(palette)])
+		fmt.Fprintf(&b, `<text x="%.1f" y="%.1f">%s</text>`+"\n", x+18, float64(height)-14, esc(label))
+		x += 30 + float64(len([]rune(label)))*10
+	}
+
+	b.WriteString("</svg>\n")
+	_, err := w.Write(b.Bytes())
+	return err
+}
+
+func esc(s string) string { return html.EscapeString(s) }
@@ -0,0 +1,263 @@
+// Package config 設定ファイル（YAML）の読み込み・環境変数による上書き・検証
+package config
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"net/url"
+	"os"
+	"regexp"
+	"time"
+
+	"gasinsight/internal/detect"
+	fetcher "gasinsight/internal/fetch"
+	"gasinsight/internal/notify"
+	"gasinsight/internal/prompt"
+
+	"gopkg.in/yaml.v3"
+)
+
+// DefaultPath 設定ファイルのデフォルトの場所（存在しなければデフォルト値を使う）
+const DefaultPath = "./config/gasinsight.yaml"
+
+// PathEnv 設定ファイルの場所を指定する環境変数
+const PathEnv = "GASINSIGHT_CONFIG"
+
+// Config GasInsightの設定
+// 優先順位は デフォルト値 < 設定ファイル < 環境変数 < コマンドラインのフラグ
+type Config struct {
+	Database   DatabaseConfig            `yaml:"database"`
+	Server     ServerConfig              `yaml:"server"`
+	Sources    SourcesConfig             `yaml:"sources"`
+	News       NewsConfig                `yaml:"news"`
+	Thresholds ThresholdsConfig          `yaml:"thresholds"`
+	Analyzer   AnalyzerConfig            `yaml:"analyzer"`
+	Schedules  map[string]string         `yaml:"schedules"` // モード → cron式（例: fetch-all: "0 9 * * *"）。検証とcrontab行の表示のみで、実行はしない
+	Notifiers  map[string]NotifierConfig `yaml:"notifiers"` // 名前 → 通知先
+	Report     ReportConfig              `yaml:"report"`
+
+	Path      string   `yaml:"-"` // 読み込んだ設定ファイル（なければ空）
+	Overrides []string `yaml:"-"` // 値を上書きした環境変数
+}
+
+// DatabaseConfig データベースの設定
+type DatabaseConfig struct {
+	Path string `yaml:"path"` // DBパスまたはDSN（postgres://... ならPostgreSQL）
+}
+
+// ServerConfig APIサーバーの設定
+type ServerConfig struct {
+	Port string `yaml:"port"` // 空なら環境変数PORTまたは8080
+}
+
+// SourcesConfig データの取得元
+type SourcesConfig struct {
+	Mock   bool     `yaml:"mock"`   // モックデータを使う
+	Scrape bool     `yaml:"scrape"` // ガソリン価格をスクレイピングで取得
+	News   []string `yaml:"news"`   // ニュース取得元（mock/newsapi/rss。空ならmockに従う）
+	Feeds  []string `yaml:"feeds"`  // RSS/Atomフィード（"名前=URL"形式も可。空ならデフォルト）
+}
+
+// NewsConfig ニュース取得の設定
+type NewsConfig struct {
+	QueriesFile     string                       `yaml:"queries_file"`      // NewsAPIの名前付き検索条件ファイル（JSON）
+	Queries         map[string]fetcher.NewsQuery `yaml:"queries"`           // 名前付き検索条件（ファイルの同じ名前を上書き）
+	Query           string                       `yaml:"query"`             // 使用する名前付き検索条件
+	Extract         bool                         `yaml:"extract"`           // 記事URLから本文を取得して分析に使う
+	MaxArticleChars int                          `yaml:"max_article_chars"` // 抽出する本文の最大文字数
+}
+
+// ThresholdsConfig 変動・異常を判定するしきい値
+type ThresholdsConfig struct {
+	PriceChangePct float64                    `yaml:"price_change_pct"` // 価格変動を記録するしきい値（±%）
+	Anomaly        map[string]DetectorsConfig `yaml:"anomaly"`          // 系列（defaultまたは gas/, fx/USD などの接頭辞）ごとの異常検知器
+}
+
+// DetectorsConfig 系列に使う異常検知器（指定した検知器だけを使う）
+type DetectorsConfig struct {
+	ZScore *ZScoreConfig `yaml:"zscore,omitempty"`
+	EWMA   *EWMAConfig   `yaml:"ewma,omitempty"`
+	Streak *StreakConfig `yaml:"streak,omitempty"`
+}
+
+// ZScoreConfig zスコアによる検知の設定
+type ZScoreConfig struct {
+	Window    int     `yaml:"window"`
+	Threshold float64 `yaml:"threshold"`
+}
+
+// EWMAConfig EWMA管理図による検知の設定
+type EWMAConfig struct {
+	Lambda float64 `yaml:"lambda"`
+	L      float64 `yaml:"l"`
+	Window int     `yaml:"window"`
+}
+
+// StreakConfig 連続上昇（下落）による検知の設定
+type StreakConfig struct {
+	MinLength int  `yaml:"min_length"`
+	Down      bool `yaml:"down,omitempty"`
+}
+
+// AnalyzerConfig ニュース分析の設定
+type AnalyzerConfig struct {
+	Backend       string        `yaml:"backend"`        // mock/gemini/openai（空なら-mock-analysisに従う）
+	Model         string        `yaml:"model"`          // 空ならバックエンドのデフォルト
+	PromptsDir    string        `yaml:"prompts_dir"`    // プロンプトテンプレートのディレクトリ
+	PromptVersion string        `yaml:"prompt_version"` // 空なら最新
+	CacheTTL      time.Duration `yaml:"cache_ttl"`      // 分析結果キャッシュの有効期間（例: 720h）
+	DailyBudget   float64       `yaml:"daily_budget"`   // LLMの1日の利用上限（ドル、0なら無制限）
+}
+
+// NotifierConfig 通知先
+type NotifierConfig struct {
+	Kind string `yaml:"kind"` // slack/webhook
+	URL  string `yaml:"url"`  // 空なら環境変数（SLACK_WEBHOOK_URL / NOTIFY_WEBHOOK_URL）
+}
+
+// ReportConfig レポートの設定
+type ReportConfig struct {
+	Notify string `yaml:"notify"` // 通知先（notifiersの名前、またはslack/webhook。空なら通知しない）
+}
+
+// Default デフォルトの設定（設定ファイルがない場合の値）
+func Default() *Config {
+	return &Config{
+		Database: DatabaseConfig{Path: "./data/gasinsight.db"},
+		Sources:  SourcesConfig{Mock: true},
+		News: NewsConfig{
+			QueriesFile:     "./config/news_queries.json",
+			Query:           "default",
+			MaxArticleChars: fetcher.DefaultMaxArticleRunes,
+		},
+		Thresholds: ThresholdsConfig{PriceChangePct: detect.DefaultChangeThresholdPct},
+		Analyzer: AnalyzerConfig{
+			PromptsDir: prompt.DefaultDir,
+			CacheTTL:   30 * 24 * time.Hour,
+		},
+	}
+}
+
+// Load 設定ファイルを読み込み、環境変数で上書きする（検証はValidateで行う）
+// pathが空ならGASINSIGHT_CONFIG、それもなければDefaultPathを使い、DefaultPathがなければデフォルト値のまま
+func Load(path string) (*Config, error) {
+	cfg := Default()
+	optional := false
+	if path == "" {
+		path = os.Getenv(PathEnv)
+	}
+	if path == "" {
+		path, optional = DefaultPath, true
+	}
+
+	data, err := os.ReadFile(path)
+	switch {
+	case err == nil:
+		if err := cfg.decode(data); err != nil {
+			return nil, fmt.Errorf("設定ファイル解析エラー (%s): %w", path, err)
+		}
+		cfg.Path = path
+	case optional && errors.Is(err, os.ErrNotExist):
+	default:
+		return nil, fmt.Errorf("設定ファイル読み込みエラー: %w", err)
+	}
+
+	if err := cfg.applyEnv(); err != nil {
+		return nil, err
+	}
+	return cfg, nil
+}
+
+// envRef 設定ファイル中の環境変数の参照（${NAME}）
+var envRef = regexp.MustCompile(`\$\{([A-Za-z_][A-Za-z0-9_]*)\}`)
+
+// decode YAMLを読み取る。${NAME}は環境変数の値に置き換え、未知のキーはエラーにする
+func (c *Config) decode(data []byte) error {
+	data = envRef.ReplaceAllFunc(data, func(m []byte) []byte {
+		return []byte(os.Getenv(string(envRef.FindSubmatch(m)[1])))
+	})
+	dec := yaml.NewDecoder(bytes.NewReader(data))
+	dec.KnownFields(true)
+	if err := dec.Decode(c); err != nil && err != io.EOF {
+		return err
+	}
+	return nil
+}
+
+// LoadQueries 検索条件ファイルを読み込み、設定ファイルの検索条件で上書きする
+func (n NewsConfig) LoadQueries() (map[string]fetcher.NewsQuery, error) {
+	queries, err := fetcher.LoadNewsQueries(n.QueriesFile)
+	if err != nil {
+		return nil, err
+	}
+	for name, q := range n.Queries {
+		queries[name] = q
+	}
+	return queries, nil
+}
+
+// AnomalyConfig 異常検知器の設定（設定ファイルで指定した系列はデフォルトを置き換える）
+func (t ThresholdsConfig) AnomalyConfig() detect.AnomalyConfig {
+	cfg := detect.DefaultAnomalyConfig()
+	for series, d := range t.Anomaly {
+		if series == "default" {
+			cfg.Default = d.detectors()
+			continue
+		}
+		cfg.PerSeries[series] = d.detectors()
+	}
+	return cfg
+}
+
+func (d DetectorsConfig) detectors() []detect.Detector {
+	var detectors []detect.Detector
+	if d.ZScore != nil {
+		detectors = append(detectors, detect.ZScoreDetector{Window: d.ZScore.Window, Threshold: d.ZScore.Threshold})
+	}
+	if d.EWMA != nil {
+		detectors = append(detectors, detect.EWMADetector{Lambda: d.EWMA.Lambda, L: d.EWMA.L, Window: d.EWMA.Window})
+	}
+	if d.Streak != nil {
+		detectors = append(detectors, detect.StreakDetector{MinLength: d.Streak.MinLength, Down: d.Streak.Down})
+	}
+	return detectors
+}
+
+// Notifier 名前の通知先を作成（notifiersにない名前は通知の種類として扱い、URLは環境変数から取得）
+func (c *Config) Notifier(name string) (notify.Notifier, error) {
+	if n, ok := c.Notifiers[name]; ok {
+		return notify.New(n.Kind, n.URL)
+	}
+	return notify.New(name, "")
+}
+
+// Crontab スケジュールをcrontabの行にする（commandにモードの指定を付けて実行する）
+func (c *Config) Crontab(command string) []string {
+	var lines []string
+	for _, mode := range sortedKeys(c.Schedules) {
+		lines = append(lines, fmt.Sprintf("%s %s -mode=%s", c.Schedules[mode], command, mode))
+	}
+	return lines
+}
+
+// YAML 表示用のYAML（通知先URLのパスはWebhookの秘密を含むため伏せる）
+func (c *Config) YAML() ([]byte, error) {
+	r := *c
+	r.Notifiers = make(map[string]NotifierConfig, len(c.Notifiers))
+	for name, n := range c.Notifiers {
+		if u, err := url.Parse(n.URL); err == nil && u.Host != "" {
+			n.URL = u.Scheme + "://" + u.Host + "/***"
+		}
+		r.Notifiers[name] = n
+	}
+
+	var buf bytes.Buffer
+	enc := yaml.NewEncoder(&buf)
+	enc.SetIndent(2)
+	if err := enc.Encode(&r); err != nil {
+		return nil, fmt.Errorf("設定の変換エラー: %w", err)
+	}
+	return buf.Bytes(), enc.Close()
+}
@@ -0,0 +1,87 @@
+package config
+
+import (
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// envOverride 設定を上書きする環境変数
+type envOverride struct {
+	name string
+	set  func(c *Config, v string) error
+}
+
+var envOverrides = []envOverride{
+	{"GASINSIGHT_DB", func(c *Config, v string) error { c.Database.Path = v; return nil }},
+	{"GASINSIGHT_PORT", func(c *Config, v string) error { c.Server.Port = v; return nil }},
+	{"GASINSIGHT_MOCK", setBool(func(c *Config) *bool { return &c.Sources.Mock })},
+	{"GASINSIGHT_SCRAPE", setBool(func(c *Config) *bool { return &c.Sources.Scrape })},
+	{"GASINSIGHT_NEWS_SOURCES", func(c *Config, v string) error { c.Sources.News = splitList(v); return nil }},
+	{"GASINSIGHT_FEEDS", func(c *Config, v string) error { c.Sources.Feeds = splitList(v); return nil }},
+	{"GASINSIGHT_NEWS_QUERY", func(c *Config, v string) error { c.News.Query = v; return nil }},
+	{"GASINSIGHT_PRICE_CHANGE_PCT", setFloat(func(c *Config) *float64 { return &c.Thresholds.PriceChangePct })},
+	{"GASINSIGHT_ANALYZER", func(c *Config, v string) error { c.Analyzer.Backend = v; return nil }},
+	{"GASINSIGHT_MODEL", func(c *Config, v string) error { c.Analyzer.Model = v; return nil }},
+	{"GASINSIGHT_PROMPT_VERSION", func(c *Config, v string) error { c.Analyzer.PromptVersion = v; return nil }},
+	{"GASINSIGHT_CACHE_TTL", func(c *Config, v string) error {
+		d, err := time.ParseDuration(v)
+		c.Analyzer.CacheTTL = d
+		return err
+	}},
+	{"GASINSIGHT_DAILY_BUDGET", setFloat(func(c *Config) *float64 { return &c.Analyzer.DailyBudget })},
+	{"GASINSIGHT_REPORT_NOTIFY", func(c *Config, v string) error { c.Report.Notify = v; return nil }},
+}
+
+// EnvNames 設定を上書きできる環境変数
+func EnvNames() []string {
+	names := make([]string, len(envOverrides))
+	for i, o := range envOverrides {
+		names[i] = o.name
+	}
+	return names
+}
+
+// applyEnv 空でない環境変数の値で設定を上書きする
+func (c *Config) applyEnv() error {
+	for _, o := range envOverrides {
+		v := os.Getenv(o.name)
+		if v == "" {
+			continue
+		}
+		if err := o.set(c, v); err != nil {
+			return fmt.Errorf("環境変数%sの値が不正です: %s", o.name, v)
+		}
+		c.Overrides = append(c.Overrides, o.name)
+	}
+	return nil
+}
+
+func setBool(field func(c *Config) *bool) func(c *Config, v string) error {
+	return func(c *Config, v string) error {
+		b, err := strconv.ParseBool(v)
+		*field(c) = b
+		return err
+	}
+}
+
+func setFloat(field func(c *Config) *float64) func(c *Config, v string) error {
+	return func(c *Config, v string) error {
+		f, err := strconv.ParseFloat(v, 64)
+		*field(c) = f
+		return err
+	}
+}
+
+// splitList カンマ区切りの値を分割（空の要素は除く）
+func splitList(s string) []string {
+	var items []string
+	for _, item := range strings.Split(s, ",") {
+		if item = strings.TrimSpace(item); item != "" {
+			items = append(items, item)
+		}
+	}
+	return items
+}
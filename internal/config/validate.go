@@ -0,0 +1,216 @@
+package config
+
+import (
+	"errors"
+	"fmt"
+	"net/url"
+	"sort"
+	"strconv"
+	"strings"
+
+	fetcher "gasinsight/internal/fetch"
+)
+
+// ScheduleModes スケジュールを設定できるモード
+var ScheduleModes = []string{
+	"fetch", "fetch-exchange", "fetch-all", "fetch-news",
+	"detect-anomalies", "analyze-fluctuation", "report",
+	"cache-purge", "maintain", "backup",
+}
+
+var (
+	newsSourceNames = map[string]bool{"mock": true, "newsapi": true, "rss": true}
+	analyzerNames   = map[string]bool{"": true, "mock": true, "gemini": true, "openai": true}
+	notifierKinds   = map[string]bool{"slack": true, "webhook": true}
+	newsSortOrders  = map[string]bool{"": true, "publishedAt": true, "relevancy": true, "popularity": true}
+)
+
+// Validate 設定値を検証し、見つかった問題をまとめて返す
+func (c *Config) Validate() error {
+	var errs []error
+	add := func(format string, args ...any) {
+		errs = append(errs, fmt.Errorf(format, args...))
+	}
+
+	if c.Database.Path == "" {
+		add("database.path が空です")
+	}
+	if c.Server.Port != "" {
+		if p, err := strconv.Atoi(c.Server.Port); err != nil || p <= 0 || p > 65535 {
+			add("server.port が不正です: %s", c.Server.Port)
+		}
+	}
+
+	for _, name := range c.Sources.News {
+		if !newsSourceNames[name] {
+			add("sources.news: 不明なニュース取得元: %s（mock/newsapi/rss）", name)
+		}
+	}
+	for _, feed := range fetcher.ParseFeedList(strings.Join(c.Sources.Feeds, ",")) {
+		if !isHTTPURL(feed.URL) {
+			add("sources.feeds: URLが不正です: %s", feed.URL)
+		}
+	}
+
+	if c.News.MaxArticleChars <= 0 {
+		add("news.max_article_chars は1以上にしてください: %d", c.News.MaxArticleChars)
+	}
+	if queries, err := c.News.LoadQueries(); err != nil {
+		add("news: %v", err)
+	} else {
+		if _, ok := queries[c.News.Query]; !ok {
+			add("news.query: 検索条件 %q が見つかりません（利用可能: %s）", c.News.Query, strings.Join(sortedKeys(queries), ", "))
+		}
+		for _, name := range sortedKeys(queries) {
+			q := queries[name]
+			if !newsSortOrders[q.SortBy] {
+				add("news.queries.%s.sort_by が不正です: %s（publishedAt/relevancy/popularity）", name, q.SortBy)
+			}
+			if q.MaxResults < 0 {
+				add("news.queries.%s.max_results は0以上にしてください: %d", name, q.MaxResults)
+			}
+		}
+	}
+
+	if c.Thresholds.PriceChangePct <= 0 {
+		add("thresholds.price_change_pct は0より大きくしてください: %g", c.Thresholds.PriceChangePct)
+	}
+	for _, series := range sortedKeys(c.Thresholds.Anomaly) {
+		errs = append(errs, validateDetectors(series, c.Thresholds.Anomaly[series])...)
+	}
+
+	if !analyzerNames[c.Analyzer.Backend] {
+		add("analyzer.backend: 不明なバックエンド: %s（mock/gemini/openai）", c.Analyzer.Backend)
+	}
+	if c.Analyzer.CacheTTL < 0 {
+		add("analyzer.cache_ttl は0以上にしてください: %s", c.Analyzer.CacheTTL)
+	}
+	if c.Analyzer.DailyBudget < 0 {
+		add("analyzer.daily_budget は0以上にしてください: %g", c.Analyzer.DailyBudget)
+	}
+
+	modes := map[string]bool{}
+	for _, m := range ScheduleModes {
+		modes[m] = true
+	}
+	for _, mode := range sortedKeys(c.Schedules) {
+		if !modes[mode] {
+			add("schedules: スケジュールを設定できないモード: %s（%s）", mode, strings.Join(ScheduleModes, "/"))
+		}
+		if err := validateCron(c.Schedules[mode]); err != nil {
+			add("schedules.%s: %v", mode, err)
+		}
+	}
+
+	for _, name := range sortedKeys(c.Notifiers) {
+		n := c.Notifiers[name]
+		if !notifierKinds[n.Kind] {
+			add("notifiers.%s.kind: 不明な通知先: %s（slack/webhook）", name, n.Kind)
+		}
+		if n.URL != "" && !isHTTPURL(n.URL) {
+			add("notifiers.%s.url が不正です", name)
+		}
+	}
+	if target := c.Report.Notify; target != "" {
+		if _, ok := c.Notifiers[target]; !ok && !notifierKinds[target] {
+			add("report.notify: 不明な通知先: %s（notifiersの名前、またはslack/webhook）", target)
+		}
+	}
+
+	return errors.Join(errs...)
+}
+
+// validateDetectors 系列の異常検知器の設定を検証
+func validateDetectors(series string, d DetectorsConfig) []error {
+	var errs []error
+	add := func(format string, args ...any) {
+		errs = append(errs, fmt.Errorf("thresholds.anomaly.%s: "+format, append([]any{series}, args...)...))
+	}
+
+	if series != "default" && !strings.HasPrefix(series, "gas/") && !strings.HasPrefix(series, "fx/") {
+		add("系列は default、gas/〜、fx/〜 のいずれかにしてください")
+	}
+	if d.ZScore == nil && d.EWMA == nil && d.Streak == nil {
+		add("検知器（zscore/ewma/streak）を1つ以上指定してください")
+	}
+	if z := d.ZScore; z != nil && (z.Window < 2 || z.Threshold <= 0) {
+		add("zscore は window を2以上、threshold を0より大きくしてください")
+	}
+	if e := d.EWMA; e != nil && (e.Lambda <= 0 || e.Lambda > 1 || e.L <= 0 || e.Window < 2) {
+		add("ewma は lambda を0より大きく1以下、l を0より大きく、window を2以上にしてください")
+	}
+	if s := d.Streak; s != nil && s.MinLength < 2 {
+		add("streak は min_length を2以上にしてください")
+	}
+	return errs
+}
+
+// cronFields cron式の各フィールドの範囲（分 時 日 月 曜日）
+var cronFields = []struct {
+	name     string
+	min, max int
+}{
+	{"分", 0, 59}, {"時", 0, 23}, {"日", 1, 31}, {"月", 1, 12}, {"曜日", 0, 7},
+}
+
+// cronMacros crontabで使える省略形
+var cronMacros = map[string]bool{
+	"@yearly": true, "@annually": true, "@monthly": true, "@weekly": true,
+	"@daily": true, "@midnight": true, "@hourly": true,
+}
+
+// validateCron crontab形式（分 時 日 月 曜日）の式を検証（数値・*・範囲・間隔・リストに対応）
+func validateCron(expr string) error {
+	if cronMacros[expr] {
+		return nil
+	}
+	fields := strings.Fields(expr)
+	if len(fields) != len(cronFields) {
+		return fmt.Errorf("cron式は「分 時 日 月 曜日」の5つで指定してください: %q", expr)
+	}
+	for i, field := range fields {
+		f := cronFields[i]
+		for _, part := range strings.Split(field, ",") {
+			if err := validateCronPart(part, f.min, f.max); err != nil {
+				return fmt.Errorf("cron式の%sが不正です（%d〜%d）: %q", f.name, f.min, f.max, expr)
+			}
+		}
+	}
+	return nil
+}
+
+func validateCronPart(part string, min, max int) error {
+	rng, step, hasStep := strings.Cut(part, "/")
+	if hasStep {
+		if n, err := strconv.Atoi(step); err != nil || n <= 0 {
+			return errors.New("invalid step")
+		}
+	}
+	if rng == "*" {
+		return nil
+	}
+	lo, hi, isRange := strings.Cut(rng, "-")
+	if !isRange {
+		hi = lo
+	}
+	a, err1 := strconv.Atoi(lo)
+	b, err2 := strconv.Atoi(hi)
+	if err1 != nil || err2 != nil || a < min || b > max || a > b {
+		return errors.New("out of range")
+	}
+	return nil
+}
+
+func isHTTPURL(s string) bool {
+	u, err := url.Parse(s)
+	return err == nil && (u.Scheme == "http" || u.Scheme == "https") && u.Host != ""
+}
+
+func sortedKeys[V any](m map[string]V) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
@@ -0,0 +1,135 @@
+package database
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"gasinsight/internal/timeseries"
+)
+
+// 集計対象の燃料種別・通貨（未指定なら全て）
+var (
+	AggregateFuels      = []string{"regular", "premium", "diesel"}
+	AggregateCurrencies = []string{"USD", "EUR", "GBP", "CNY"}
+)
+
+// AggregateQuery 集計の条件
+type AggregateQuery struct {
+	Period     timeseries.Period // week/month
+	From       string            // 開始日（YYYY-MM-DD、両端含む）
+	To         string            // 終了日
+	Region     string            // 地域（ガソリン価格のみ。空なら地域ごとに集計）
+	Source     string            // データソース
+	Fuels      []string          // 燃料種別（ガソリン価格のみ）
+	Currencies []string          // 通貨（為替レートのみ）
+}
+
+// SeriesStats 系列（燃料種別×地域、または通貨）ごとの期間集計
+type SeriesStats struct {
+	Series string             `json:"series"` // regular / USD など
+	Region string             `json:"region,omitempty"`
+	Period timeseries.Period  `json:"period"`
+	Stats  []timeseries.Stats `json:"stats"`
+}
+
+// aggregateGasPrices ガソリン価格を燃料種別・地域ごとに週次/月次で集計
+func aggregateGasPrices(s GasPriceStore, q AggregateQuery) ([]*SeriesStats, error) {
+	fuels, err := aggregateTargets(q, q.Fuels, AggregateFuels, "燃料種別")
+	if err != nil {
+		return nil, err
+	}
+	prices, _, err := s.ListGasPrices(ListQuery{From: q.From, To: q.To, Region: q.Region, Source: q.Source, Sort: "date"})
+	if err != nil {
+		return nil, err
+	}
+
+	regionSet := map[string]bool{}
+	for _, p := range prices {
+		regionSet[p.Region] = true
+	}
+	regions := make([]string, 0, len(regionSet))
+	for r := range regionSet {
+		regions = append(regions, r)
+	}
+	sort.Strings(regions)
+
+	var result []*SeriesStats
+	for _, region := range regions {
+		for _, fuel := range fuels {
+			stats := timeseries.FromGasPrices(prices, fuel, region).Aggregate(q.Period)
+			if len(stats) == 0 {
+				continue
+			}
+			result = append(result, &SeriesStats{Series: fuel, Region: region, Period: q.Period, Stats: stats})
+		}
+	}
+	return result, nil
+}
+
+// aggregateExchangeRates 為替レートを通貨ごとに週次/月次で集計
+func aggregateExchangeRates(s ExchangeRateStore, q AggregateQuery) ([]*SeriesStats, error) {
+	currencies, err := aggregateTargets(q, q.Currencies, AggregateCurrencies, "通貨")
+	if err != nil {
+		return nil, err
+	}
+	rates, _, err := s.ListExchangeRates(ListQuery{From: q.From, To: q.To, Source: q.Source, Sort: "date"})
+	if err != nil {
+		return nil, err
+	}
+
+	var result []*SeriesStats
+	for _, currency := range currencies {
+		stats := timeseries.FromExchangeRates(rates, currency).Aggregate(q.Period)
+		if len(stats) == 0 {
+			continue
+		}
+		result = append(result, &SeriesStats{Series: currency, Period: q.Period, Stats: stats})
+	}
+	return result, nil
+}
+
+// AggregateGasPrices ガソリン価格を燃料種別・地域ごとに週次/月次で集計
+func (s *SQLiteClient) AggregateGasPrices(q AggregateQuery) ([]*SeriesStats, error) {
+	return aggregateGasPrices(s, q)
+}
+
+// AggregateExchangeRates 為替レートを通貨ごとに週次/月次で集計
+func (s *SQLiteClient) AggregateExchangeRates(q AggregateQuery) ([]*SeriesStats, error) {
+	return aggregateExchangeRates(s, q)
+}
+
+// AggregateGasPrices ガソリン価格を燃料種別・地域ごとに週次/月次で集計
+func (p *PostgresClient) AggregateGasPrices(q AggregateQuery) ([]*SeriesStats, error) {
+	return aggregateGasPrices(p, q)
+}
+
+// AggregateExchangeRates 為替レートを通貨ごとに週次/月次で集計
+func (p *PostgresClient) AggregateExchangeRates(q AggregateQuery) ([]*SeriesStats, error) {
+	return aggregateExchangeRates(p, q)
+}
+
+// aggregateTargets 集計単位を確認し、集計する系列（未指定なら全て）を返す
+func aggregateTargets(q AggregateQuery, names, all []string, kind string) ([]string, error) {
+	if _, err := timeseries.ParsePeriod(string(q.Period)); err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidQuery, err)
+	}
+	if len(names) == 0 {
+		return all, nil
+	}
+	for _, name := range names {
+		if !contains(all, name) {
+			return nil, fmt.Errorf("%w: 不明な%s: %s（%s）", ErrInvalidQuery, kind, name, strings.Join(all, "/"))
+		}
+	}
+	return names, nil
+}
+
+func contains(list []string, s string) bool {
+	for _, v := range list {
+		if v == s {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,221 @@
+package database
+
+import (
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"gasinsight/internal/detect"
+	"time"
+)
+
+// CreateAnalysisCacheTable 分析結果キャッシュのテーブルを作成
+func (s *SQLiteClient) CreateAnalysisCacheTable() error {
+	query := `
+	CREATE TABLE IF NOT EXISTS analysis_cache (
+		content_hash TEXT NOT NULL,
+		model TEXT NOT NULL,
+		prompt_version TEXT NOT NULL,
+		result TEXT NOT NULL,
+		hit_count INTEGER NOT NULL DEFAULT 0,
+		created_at INTEGER NOT NULL,
+		last_hit_at INTEGER,
+		PRIMARY KEY (content_hash, model, prompt_version)
+	);
+	CREATE INDEX IF NOT EXISTS idx_analysis_cache_created_at ON analysis_cache(created_at);
+	`
+
+	if _, err := s.db.Exec(query); err != nil {
+		return fmt.Errorf("分析キャッシュテーブル作成エラー: %w", err)
+	}
+	return nil
+}
+
+// analysisCacheTablePostgres PostgreSQLの分析キャッシュテーブル
+const analysisCacheTablePostgres = `
+	CREATE TABLE IF NOT EXISTS analysis_cache (
+		content_hash TEXT NOT NULL,
+		model TEXT NOT NULL,
+		prompt_version TEXT NOT NULL,
+		result TEXT NOT NULL,
+		hit_count BIGINT NOT NULL DEFAULT 0,
+		created_at BIGINT NOT NULL,
+		last_hit_at BIGINT,
+		PRIMARY KEY (content_hash, model, prompt_version)
+	);
+	CREATE INDEX IF NOT EXISTS idx_analysis_cache_created_at ON analysis_cache(created_at);
+	`
+
+// getCachedAnalysis キャッシュされた分析結果を取得（なければnil）
+func getCachedAnalysis(db dbtx, d dialect, key detect.AnalysisCacheKey, maxAge time.Duration) (*detect.AnalyzedNews, error) {
+	since := int64(0)
+	if maxAge > 0 {
+		since = time.Now().Add(-maxAge).Unix()
+	}
+
+	var result string
+	err := db.QueryRow(d.bind(`
+		SELECT result FROM analysis_cache
+		WHERE content_hash = ? AND model = ? AND prompt_version = ? AND created_at >= ?`),
+		key.ContentHash, key.Model, key.PromptVersion, since,
+	).Scan(&result)
+	if err == sql.ErrNoRows {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("分析キャッシュ取得エラー: %w", err)
+	}
+
+	var news detect.AnalyzedNews
+	if err := json.Unmarshal([]byte(result), &news); err != nil {
+		return nil, fmt.Errorf("分析キャッシュ解析エラー: %w", err)
+	}
+
+	if _, err := db.Exec(d.bind(`
+		UPDATE analysis_cache SET hit_count = hit_count + 1, last_hit_at = ?
+		WHERE content_hash = ? AND model = ? AND prompt_version = ?`),
+		time.Now().Unix(), key.ContentHash, key.Model, key.PromptVersion,
+	); err != nil {
+		return nil, fmt.Errorf("分析キャッシュ更新エラー: %w", err)
+	}
+	return &news, nil
+}
+
+// saveCachedAnalysis 分析結果をキャッシュに保存（同じキーは上書き）
+func saveCachedAnalysis(db dbtx, d dialect, key detect.AnalysisCacheKey, news *detect.AnalyzedNews) error {
+	result, err := json.Marshal(news)
+	if err != nil {
+		return fmt.Errorf("分析キャッシュ変換エラー: %w", err)
+	}
+
+	_, err = db.Exec(d.bind(`
+		INSERT INTO analysis_cache
+		(content_hash, model, prompt_version, result, hit_count, created_at)
+		VALUES (?, ?, ?, ?, 0, ?)
+		ON CONFLICT (content_hash, model, prompt_version) DO UPDATE SET
+			result = excluded.result, hit_count = 0, created_at = excluded.created_at, last_hit_at = NULL`),
+		key.ContentHash, key.Model, key.PromptVersion, string(result), time.Now().Unix(),
+	)
+	if err != nil {
+		return fmt.Errorf("分析キャッシュ保存エラー: %w", err)
+	}
+	return nil
+}
+
+// AnalysisCacheModelStats モデル・プロンプトごとのキャッシュ統計
+type AnalysisCacheModelStats struct {
+	Model         string `json:"model"`
+	PromptVersion string `json:"prompt_version"`
+	Entries       int    `json:"entries"`
+	Hits          int    `json:"hits"`
+}
+
+// AnalysisCacheStats 分析キャッシュの統計
+type AnalysisCacheStats struct {
+	Entries int                        `json:"entries"`
+	Hits    int                        `json:"hits"`    // キャッシュが使われた回数（節約できたAPI呼び出し数）
+	Expired int                        `json:"expired"` // TTLを過ぎたエントリ数
+	Oldest  string                     `json:"oldest,omitempty"`
+	Newest  string                     `json:"newest,omitempty"`
+	ByModel []*AnalysisCacheModelStats `json:"by_model"`
+}
+
+// getAnalysisCacheStats 分析キャッシュの統計を取得（ttlが0以下なら期限切れは数えない）
+func getAnalysisCacheStats(db dbtx, d dialect, ttl time.Duration) (*AnalysisCacheStats, error) {
+	stats := &AnalysisCacheStats{}
+
+	var oldest, newest sql.NullInt64
+	err := db.QueryRow(`
+		SELECT COUNT(*), COALESCE(SUM(hit_count), 0), MIN(created_at), MAX(created_at)
+		FROM analysis_cache`,
+	).Scan(&stats.Entries, &stats.Hits, &oldest, &newest)
+	if err != nil {
+		return nil, fmt.Errorf("分析キャッシュ統計取得エラー: %w", err)
+	}
+	if oldest.Valid {
+		stats.Oldest = time.Unix(oldest.Int64, 0).Format(time.RFC3339)
+		stats.Newest = time.Unix(newest.Int64, 0).Format(time.RFC3339)
+	}
+
+	if ttl > 0 {
+		err := db.QueryRow(d.bind(`SELECT COUNT(*) FROM analysis_cache WHERE created_at < ?`),
+			time.Now().Add(-ttl).Unix(),
+		).Scan(&stats.Expired)
+		if err != nil {
+			return nil, fmt.Errorf("分析キャッシュ統計取得エラー: %w", err)
+		}
+	}
+
+	rows, err := db.Query(`
+		SELECT model, prompt_version, COUNT(*), COALESCE(SUM(hit_count), 0)
+		FROM analysis_cache
+		GROUP BY model, prompt_version
+		ORDER BY model, prompt_version`)
+	if err != nil {
+		return nil, fmt.Errorf("分析キャッシュ統計取得エラー: %w", err)
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		m := &AnalysisCacheModelStats{}
+		if err := rows.Scan(&m.Model, &m.PromptVersion, &m.Entries, &m.Hits); err != nil {
+			return nil, err
+		}
+		stats.ByModel = append(stats.ByModel, m)
+	}
+	return stats, rows.Err()
+}
+
+// purgeAnalysisCache ttlより古いエントリを削除（ttlが0以下なら全件削除）し、削除件数を返す
+func purgeAnalysisCache(db dbtx, d dialect, ttl time.Duration) (int64, error) {
+	var res sql.Result
+	var err error
+	if ttl > 0 {
+		res, err = db.Exec(d.bind(`DELETE FROM analysis_cache WHERE created_at < ?`), time.Now().Add(-ttl).Unix())
+	} else {
+		res, err = db.Exec(`DELETE FROM analysis_cache`)
+	}
+	if err != nil {
+		return 0, fmt.Errorf("分析キャッシュ削除エラー: %w", err)
+	}
+	return res.RowsAffected()
+}
+
+// GetCachedAnalysis キャッシュされた分析結果を取得（なければnil）
+func (s *SQLiteClient) GetCachedAnalysis(key detect.AnalysisCacheKey, maxAge time.Duration) (*detect.AnalyzedNews, error) {
+	return getCachedAnalysis(s.db, dialectSQLite, key, maxAge)
+}
+
+// SaveCachedAnalysis 分析結果をキャッシュに保存（同じキーは上書き）
+func (s *SQLiteClient) SaveCachedAnalysis(key detect.AnalysisCacheKey, news *detect.AnalyzedNews) error {
+	return saveCachedAnalysis(s.db, dialectSQLite, key, news)
+}
+
+// GetAnalysisCacheStats 分析キャッシュの統計を取得（ttlが0以下なら期限切れは数えない）
+func (s *SQLiteClient) GetAnalysisCacheStats(ttl time.Duration) (*AnalysisCacheStats, error) {
+	return getAnalysisCacheStats(s.db, dialectSQLite, ttl)
+}
+
+// PurgeAnalysisCache ttlより古いエントリを削除（ttlが0以下なら全件削除）し、削除件数を返す
+func (s *SQLiteClient) PurgeAnalysisCache(ttl time.Duration) (int64, error) {
+	return purgeAnalysisCache(s.db, dialectSQLite, ttl)
+}
+
+// GetCachedAnalysis キャッシュされた分析結果を取得（なければnil）
+func (p *PostgresClient) GetCachedAnalysis(key detect.AnalysisCacheKey, maxAge time.Duration) (*detect.AnalyzedNews, error) {
+	return getCachedAnalysis(p.db, dialectPostgres, key, maxAge)
+}
+
+// SaveCachedAnalysis 分析結果をキャッシュに保存（同じキーは上書き）
+func (p *PostgresClient) SaveCachedAnalysis(key detect.AnalysisCacheKey, news *detect.AnalyzedNews) error {
+	return saveCachedAnalysis(p.db, dialectPostgres, key, news)
+}
+
+// GetAnalysisCacheStats 分析キャッシュの統計を取得（ttlが0以下なら期限切れは数えない）
+func (p *PostgresClient) GetAnalysisCacheStats(ttl time.Duration) (*AnalysisCacheStats, error) {
+	return getAnalysisCacheStats(p.db, dialectPostgres, ttl)
+}
+
+// PurgeAnalysisCache ttlより古いエントリを削除（ttlが0以下なら全件削除）し、削除件数を返す
+func (p *PostgresClient) PurgeAnalysisCache(ttl time.Duration) (int64, error) {
+	return purgeAnalysisCache(p.db, dialectPostgres, ttl)
+}
@@ -0,0 +1,143 @@
+package database
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	sqlite3 "github.com/mattn/go-sqlite3"
+)
+
+// バックアップで1回にコピーするページ数と、その間の待ち時間
+// 少しずつコピーすることで、バックアップ中も他の接続から書き込める
+const (
+	backupStepPages = 256
+	backupStepPause = 10 * time.Millisecond
+)
+
+// BackupTimeLayout バックアップファイル名に付ける日時の形式
+const BackupTimeLayout = "20060102-150405"
+
+// Backup SQLiteのオンラインバックアップAPIでDBをdestにコピーする
+// コピー中のファイルは.tmpとして書き込み、完了後に名前を変える
+func (s *SQLiteClient) Backup(ctx context.Context, dest string) error {
+	if s.conn == nil {
+		return errors.New("トランザクション内ではバックアップできません")
+	}
+	if err := os.MkdirAll(filepath.Dir(dest), 0755); err != nil {
+		return fmt.Errorf("ディレクトリ作成エラー: %w", err)
+	}
+	tmp := dest + ".tmp"
+	if err := os.Remove(tmp); err != nil && !errors.Is(err, os.ErrNotExist) {
+		return fmt.Errorf("一時ファイル削除エラー: %w", err)
+	}
+
+	if err := s.backupTo(ctx, tmp); err != nil {
+		_ = os.Remove(tmp)
+		return err
+	}
+	if err := os.Rename(tmp, dest); err != nil {
+		return fmt.Errorf("バックアップファイル作成エラー: %w", err)
+	}
+	return nil
+}
+
+func (s *SQLiteClient) backupTo(ctx context.Context, path string) error {
+	destDB, err := sql.Open("sqlite3", path)
+	if err != nil {
+		return fmt.Errorf("バックアップ先オープンエラー: %w", err)
+	}
+	defer destDB.Close()
+
+	destConn, err := destDB.Conn(ctx)
+	if err != nil {
+		return fmt.Errorf("バックアップ先接続エラー: %w", err)
+	}
+	defer destConn.Close()
+	srcConn, err := s.conn.Conn(ctx)
+	if err != nil {
+		return fmt.Errorf("データベース接続エラー: %w", err)
+	}
+	defer srcConn.Close()
+
+	return destConn.Raw(func(destDriver interface{}) error {
+		return srcConn.Raw(func(srcDriver interface{}) error {
+			dest, ok := destDriver.(*sqlite3.SQLiteConn)
+			src, ok2 := srcDriver.(*sqlite3.SQLiteConn)
+			if !ok || !ok2 {
+				return errors.New("SQLiteの接続ではありません")
+			}
+			b, err := dest.Backup("main", src, "main")
+			if err != nil {
+				return fmt.Errorf("バックアップ開始エラー: %w", err)
+			}
+			for {
+				done, err := b.Step(backupStepPages)
+				if err != nil {
+					_ = b.Finish()
+					return fmt.Errorf("バックアップエラー: %w", err)
+				}
+				if done {
+					break
+				}
+				select {
+				case <-ctx.Done():
+					_ = b.Finish()
+					return ctx.Err()
+				case <-time.After(backupStepPause):
+				}
+			}
+			if err := b.Finish(); err != nil {
+				return fmt.Errorf("バックアップ完了エラー: %w", err)
+			}
+			return nil
+		})
+	})
+}
+
+// BackupPath dir内のバックアップファイル名（<prefix>-<日時>.db）
+func BackupPath(dir, prefix string, t time.Time) string {
+	return filepath.Join(dir, fmt.Sprintf("%s-%s.db", prefix, t.Format(BackupTimeLayout)))
+}
+
+// RotateBackups dir内の<prefix>-<日時>.dbを新しい順にkeep個だけ残し、削除したファイルを返す（keepが0以下なら削除しない）
+func RotateBackups(dir, prefix string, keep int) ([]string, error) {
+	if keep <= 0 {
+		return nil, nil
+	}
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return nil, fmt.Errorf("バックアップ一覧取得エラー: %w", err)
+	}
+
+	var backups []string
+	for _, e := range entries {
+		stamp, ok := strings.CutPrefix(e.Name(), prefix+"-")
+		if !ok || e.IsDir() {
+			continue
+		}
+		stamp, ok = strings.CutSuffix(stamp, ".db")
+		if _, err := time.Parse(BackupTimeLayout, stamp); !ok || err != nil {
+			continue
+		}
+		backups = append(backups, e.Name())
+	}
+	// 日時の形式は文字列の順が新旧の順になる
+	sort.Sort(sort.Reverse(sort.StringSlice(backups)))
+
+	var removed []string
+	for i := keep; i < len(backups); i++ {
+		path := filepath.Join(dir, backups[i])
+		if err := os.Remove(path); err != nil {
+			return removed, fmt.Errorf("バックアップ削除エラー: %w", err)
+		}
+		removed = append(removed, path)
+	}
+	return removed, nil
+}
@@ -1,6 +1,7 @@
 package database
 
 import (
+	"context"
 	"database/sql"
 	"fmt"
 	"log"
@@ -33,31 +34,20 @@ func (s *SQLiteClient) CreateExchangeRateTable() error {
 	return nil
 }
 
-// SaveExchangeRate 為替レートを保存
-func (s *SQLiteClient) SaveExchangeRate(rate *model.ExchangeRate) error {
-	query := `
-		INSERT OR REPLACE INTO exchange_rates 
-		(id, date, usd_jpy, eur_jpy, gbp_jpy, cny_jpy, source, created_at, updated_at)
-		VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?)`
-
-	_, err := s.db.Exec(query,
-		rate.ID,
-		rate.Date,
-		rate.USDJPY,
-		rate.EURJPY,
-		rate.GBPJPY,
-		rate.CNYJPY,
-		rate.Source,
-		rate.CreatedAt,
-		rate.UpdatedAt,
-	)
-
+// SaveExchangeRate 為替レートを保存（値が変わった通貨は改定履歴に追記し、作成日時は最初の保存のまま）
+func (s *SQLiteClient) SaveExchangeRate(rate *model.ExchangeRate) (bool, error) {
+	var changed bool
+	err := s.WithTx(context.Background(), func(tx Store) error {
+		var err error
+		changed, err = saveExchangeRate(tx.(*SQLiteClient).db, dialectSQLite, rate)
+		return err
+	})
 	if err != nil {
-		return fmt.Errorf("為替レート保存エラー: %w", err)
+		return false, err
 	}
 
 	log.Printf("✅ 為替レートを保存: %s", rate.Date)
-	return nil
+	return changed, nil
 }
 
 // GetAllExchangeRates 全ての為替レートを取得
@@ -73,6 +63,11 @@ func (s *SQLiteClient) GetAllExchangeRates() ([]*model.ExchangeRate, error) {
 	}
 	defer rows.Close()
 
+	return scanExchangeRates(rows)
+}
+
+// scanExchangeRates 為替レートの行を読み取る
+func scanExchangeRates(rows *sql.Rows) ([]*model.ExchangeRate, error) {
 	var rates []*model.ExchangeRate
 	for rows.Next() {
 		var rate model.ExchangeRate
@@ -93,15 +88,15 @@ func (s *SQLiteClient) GetAllExchangeRates() ([]*model.ExchangeRate, error) {
 		rates = append(rates, &rate)
 	}
 
-	return rates, nil
+	return rates, rows.Err()
 }
 
-// GetLatestExchangeRate 最新の為替レートを取得
+// GetLatestExchangeRate 最新の為替レートを取得（同じ日付に複数あればデータソースの順で最初）
 func (s *SQLiteClient) GetLatestExchangeRate() (*model.ExchangeRate, error) {
 	query := `
 		SELECT id, date, usd_jpy, eur_jpy, gbp_jpy, cny_jpy, source, created_at, updated_at
 		FROM exchange_rates
-		ORDER BY date DESC
+		ORDER BY date DESC, source
 		LIMIT 1`
 
 	var rate model.ExchangeRate
@@ -127,12 +122,14 @@ func (s *SQLiteClient) GetLatestExchangeRate() (*model.ExchangeRate, error) {
 	return &rate, nil
 }
 
-// GetExchangeRateByDate 特定日付の為替レートを取得
+// GetExchangeRateByDate 特定日付の為替レートを取得（複数あればデータソースの順で最初）
 func (s *SQLiteClient) GetExchangeRateByDate(date string) (*model.ExchangeRate, error) {
 	query := `
 		SELECT id, date, usd_jpy, eur_jpy, gbp_jpy, cny_jpy, source, created_at, updated_at
 		FROM exchange_rates
-		WHERE date = ?`
+		WHERE date = ?
+		ORDER BY source
+		LIMIT 1`
 
 	var rate model.ExchangeRate
 	err := s.db.QueryRow(query, date).Scan(
@@ -156,3 +153,35 @@ func (s *SQLiteClient) GetExchangeRateByDate(date string) (*model.ExchangeRate,
 
 	return &rate, nil
 }
+
+// GetExchangeRateOnOrBefore 指定日付以前で最新の為替レートを取得（登録がなければnil）
+func (s *SQLiteClient) GetExchangeRateOnOrBefore(date string) (*model.ExchangeRate, error) {
+	query := `
+		SELECT id, date, usd_jpy, eur_jpy, gbp_jpy, cny_jpy, source, created_at, updated_at
+		FROM exchange_rates
+		WHERE date <= ?
+		ORDER BY date DESC, source
+		LIMIT 1`
+
+	var rate model.ExchangeRate
+	err := s.db.QueryRow(query, date).Scan(
+		&rate.ID,
+		&rate.Date,
+		&rate.USDJPY,
+		&rate.EURJPY,
+		&rate.GBPJPY,
+		&rate.CNYJPY,
+		&rate.Source,
+		&rate.CreatedAt,
+		&rate.UpdatedAt,
+	)
+
+	if err == sql.ErrNoRows {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	return &rate, nil
+}
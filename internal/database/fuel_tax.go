@@ -0,0 +1,260 @@
+package database
+
+import (
+	"database/sql"
+	"fmt"
+	"log"
+
+	model "gasinsight/internal/model"
+)
+
+// CreateFuelTaxTables 補助金・原油価格テーブルを作成
+func (s *SQLiteClient) CreateFuelTaxTables() error {
+	query := `
+	CREATE TABLE IF NOT EXISTS fuel_subsidies (
+		id TEXT PRIMARY KEY,
+		week_start TEXT NOT NULL,
+		amount REAL NOT NULL,
+		source TEXT NOT NULL,
+		created_at INTEGER NOT NULL,
+		updated_at INTEGER NOT NULL
+	);
+	CREATE INDEX IF NOT EXISTS idx_fuel_subsidies_week_start ON fuel_subsidies(week_start);
+
+	CREATE TABLE IF NOT EXISTS crude_prices (
+		id TEXT PRIMARY KEY,
+		date TEXT NOT NULL,
+		usd_per_barrel REAL NOT NULL,
+		source TEXT NOT NULL,
+		created_at INTEGER NOT NULL,
+		updated_at INTEGER NOT NULL
+	);
+	CREATE INDEX IF NOT EXISTS idx_crude_prices_date ON crude_prices(date);
+	`
+
+	if _, err := s.db.Exec(query); err != nil {
+		return fmt.Errorf("補助金・原油価格テーブル作成エラー: %w", err)
+	}
+
+	log.Println("✅ 補助金・原油価格テーブルを作成しました")
+	return nil
+}
+
+// fuelTaxTablesPostgres PostgreSQLの補助金・原油価格テーブル
+const fuelTaxTablesPostgres = `
+	CREATE TABLE IF NOT EXISTS fuel_subsidies (
+		id TEXT PRIMARY KEY,
+		week_start TEXT NOT NULL,
+		amount DOUBLE PRECISION NOT NULL,
+		source TEXT NOT NULL,
+		created_at BIGINT NOT NULL,
+		updated_at BIGINT NOT NULL
+	);
+	CREATE INDEX IF NOT EXISTS idx_fuel_subsidies_week_start ON fuel_subsidies(week_start);
+
+	CREATE TABLE IF NOT EXISTS crude_prices (
+		id TEXT PRIMARY KEY,
+		date TEXT NOT NULL,
+		usd_per_barrel DOUBLE PRECISION NOT NULL,
+		source TEXT NOT NULL,
+		created_at BIGINT NOT NULL,
+		updated_at BIGINT NOT NULL
+	);
+	CREATE INDEX IF NOT EXISTS idx_crude_prices_date ON crude_prices(date);
+	`
+
+// saveFuelSubsidy 週次の補助金額を保存（同じ週は上書き）
+func saveFuelSubsidy(db dbtx, d dialect, subsidy *model.FuelSubsidy) error {
+	_, err := db.Exec(d.bind(`
+		INSERT INTO fuel_subsidies
+		(id, week_start, amount, source, created_at, updated_at)
+		VALUES (?, ?, ?, ?, ?, ?)
+		ON CONFLICT (id) DO UPDATE SET
+			week_start = excluded.week_start, amount = excluded.amount, source = excluded.source,
+			created_at = excluded.created_at, updated_at = excluded.updated_at`),
+		subsidy.ID,
+		subsidy.WeekStart,
+		subsidy.Amount,
+		subsidy.Source,
+		subsidy.CreatedAt,
+		subsidy.UpdatedAt,
+	)
+	if err != nil {
+		return fmt.Errorf("補助金保存エラー: %w", err)
+	}
+
+	log.Printf("✅ 補助金を保存: %s週 %.2f円/L", subsidy.WeekStart, subsidy.Amount)
+	return nil
+}
+
+// getAllFuelSubsidies 全ての補助金データを取得
+func getAllFuelSubsidies(db dbtx) ([]*model.FuelSubsidy, error) {
+	rows, err := db.Query(`
+		SELECT id, week_start, amount, source, created_at, updated_at
+		FROM fuel_subsidies
+		ORDER BY week_start DESC`)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var subsidies []*model.FuelSubsidy
+	for rows.Next() {
+		var sub model.FuelSubsidy
+		if err := rows.Scan(&sub.ID, &sub.WeekStart, &sub.Amount, &sub.Source,
+			&sub.CreatedAt, &sub.UpdatedAt); err != nil {
+			return nil, err
+		}
+		subsidies = append(subsidies, &sub)
+	}
+
+	return subsidies, rows.Err()
+}
+
+// getFuelSubsidyByWeek 指定週の補助金を取得（登録がなければnil）
+func getFuelSubsidyByWeek(db dbtx, d dialect, weekStart string) (*model.FuelSubsidy, error) {
+	var sub model.FuelSubsidy
+	err := db.QueryRow(d.bind(`
+		SELECT id, week_start, amount, source, created_at, updated_at
+		FROM fuel_subsidies
+		WHERE week_start = ?`), weekStart,
+	).Scan(&sub.ID, &sub.WeekStart, &sub.Amount, &sub.Source, &sub.CreatedAt, &sub.UpdatedAt)
+
+	if err == sql.ErrNoRows {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	return &sub, nil
+}
+
+// saveCrudePrice 原油価格を保存（同じ日付は上書き）
+func saveCrudePrice(db dbtx, d dialect, price *model.CrudePrice) error {
+	_, err := db.Exec(d.bind(`
+		INSERT INTO crude_prices
+		(id, date, usd_per_barrel, source, created_at, updated_at)
+		VALUES (?, ?, ?, ?, ?, ?)
+		ON CONFLICT (id) DO UPDATE SET
+			date = excluded.date, usd_per_barrel = excluded.usd_per_barrel, source = excluded.source,
+			created_at = excluded.created_at, updated_at = excluded.updated_at`),
+		price.ID,
+		price.Date,
+		price.USDPerBarrel,
+		price.Source,
+		price.CreatedAt,
+		price.UpdatedAt,
+	)
+	if err != nil {
+		return fmt.Errorf("原油価格保存エラー: %w", err)
+	}
+
+	log.Printf("✅ 原油価格を保存: %s $%.2f/bbl", price.Date, price.USDPerBarrel)
+	return nil
+}
+
+// getAllCrudePrices 全ての原油価格を取得
+func getAllCrudePrices(db dbtx) ([]*model.CrudePrice, error) {
+	rows, err := db.Query(`
+		SELECT id, date, usd_per_barrel, source, created_at, updated_at
+		FROM crude_prices
+		ORDER BY date DESC`)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var prices []*model.CrudePrice
+	for rows.Next() {
+		var p model.CrudePrice
+		if err := rows.Scan(&p.ID, &p.Date, &p.USDPerBarrel, &p.Source,
+			&p.CreatedAt, &p.UpdatedAt); err != nil {
+			return nil, err
+		}
+		prices = append(prices, &p)
+	}
+
+	return prices, rows.Err()
+}
+
+// getCrudePriceOnOrBefore 指定日付以前で最新の原油価格を取得（登録がなければnil）
+func getCrudePriceOnOrBefore(db dbtx, d dialect, date string) (*model.CrudePrice, error) {
+	var p model.CrudePrice
+	err := db.QueryRow(d.bind(`
+		SELECT id, date, usd_per_barrel, source, created_at, updated_at
+		FROM crude_prices
+		WHERE date <= ?
+		ORDER BY date DESC
+		LIMIT 1`), date,
+	).Scan(&p.ID, &p.Date, &p.USDPerBarrel, &p.Source, &p.CreatedAt, &p.UpdatedAt)
+
+	if err == sql.ErrNoRows {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	return &p, nil
+}
+
+// SaveFuelSubsidy 週次の補助金額を保存
+func (s *SQLiteClient) SaveFuelSubsidy(subsidy *model.FuelSubsidy) error {
+	return saveFuelSubsidy(s.db, dialectSQLite, subsidy)
+}
+
+// GetAllFuelSubsidies 全ての補助金データを取得
+func (s *SQLiteClient) GetAllFuelSubsidies() ([]*model.FuelSubsidy, error) {
+	return getAllFuelSubsidies(s.db)
+}
+
+// GetFuelSubsidyByWeek 指定週の補助金を取得（登録がなければnil）
+func (s *SQLiteClient) GetFuelSubsidyByWeek(weekStart string) (*model.FuelSubsidy, error) {
+	return getFuelSubsidyByWeek(s.db, dialectSQLite, weekStart)
+}
+
+// SaveCrudePrice 原油価格を保存
+func (s *SQLiteClient) SaveCrudePrice(price *model.CrudePrice) error {
+	return saveCrudePrice(s.db, dialectSQLite, price)
+}
+
+// GetAllCrudePrices 全ての原油価格を取得
+func (s *SQLiteClient) GetAllCrudePrices() ([]*model.CrudePrice, error) {
+	return getAllCrudePrices(s.db)
+}
+
+// GetCrudePriceOnOrBefore 指定日付以前で最新の原油価格を取得（登録がなければnil）
+func (s *SQLiteClient) GetCrudePriceOnOrBefore(date string) (*model.CrudePrice, error) {
+	return getCrudePriceOnOrBefore(s.db, dialectSQLite, date)
+}
+
+// SaveFuelSubsidy 週次の補助金額を保存
+func (p *PostgresClient) SaveFuelSubsidy(subsidy *model.FuelSubsidy) error {
+	return saveFuelSubsidy(p.db, dialectPostgres, subsidy)
+}
+
+// GetAllFuelSubsidies 全ての補助金データを取得
+func (p *PostgresClient) GetAllFuelSubsidies() ([]*model.FuelSubsidy, error) {
+	return getAllFuelSubsidies(p.db)
+}
+
+// GetFuelSubsidyByWeek 指定週の補助金を取得（登録がなければnil）
+func (p *PostgresClient) GetFuelSubsidyByWeek(weekStart string) (*model.FuelSubsidy, error) {
+	return getFuelSubsidyByWeek(p.db, dialectPostgres, weekStart)
+}
+
+// SaveCrudePrice 原油価格を保存
+func (p *PostgresClient) SaveCrudePrice(price *model.CrudePrice) error {
+	return saveCrudePrice(p.db, dialectPostgres, price)
+}
+
+// GetAllCrudePrices 全ての原油価格を取得
+func (p *PostgresClient) GetAllCrudePrices() ([]*model.CrudePrice, error) {
+	return getAllCrudePrices(p.db)
+}
+
+// GetCrudePriceOnOrBefore 指定日付以前で最新の原油価格を取得（登録がなければnil）
+func (p *PostgresClient) GetCrudePriceOnOrBefore(date string) (*model.CrudePrice, error) {
+	return getCrudePriceOnOrBefore(p.db, dialectPostgres, date)
+}
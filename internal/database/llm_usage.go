@@ -0,0 +1,160 @@
+package database
+
+import (
+	"fmt"
+	"gasinsight/internal/detect"
+	"time"
+)
+
+// CreateLLMUsageTable LLM利用量テーブルを作成
+func (s *SQLiteClient) CreateLLMUsageTable() error {
+	query := `
+	CREATE TABLE IF NOT EXISTS llm_usage (
+		id INTEGER PRIMARY KEY AUTOINCREMENT,
+		backend TEXT NOT NULL,
+		model TEXT NOT NULL,
+		prompt_version TEXT NOT NULL,
+		operation TEXT NOT NULL,
+		prompt_tokens INTEGER NOT NULL,
+		response_tokens INTEGER NOT NULL,
+		latency_ms INTEGER NOT NULL,
+		cost_usd REAL NOT NULL,
+		error TEXT NOT NULL DEFAULT '',
+		created_at INTEGER NOT NULL
+	);
+	CREATE INDEX IF NOT EXISTS idx_llm_usage_created_at ON llm_usage(created_at);
+	`
+
+	if _, err := s.db.Exec(query); err != nil {
+		return fmt.Errorf("LLM利用量テーブル作成エラー: %w", err)
+	}
+	return nil
+}
+
+// llmUsageTablePostgres PostgreSQLのLLM利用量テーブル
+const llmUsageTablePostgres = `
+	CREATE TABLE IF NOT EXISTS llm_usage (
+		id BIGSERIAL PRIMARY KEY,
+		backend TEXT NOT NULL,
+		model TEXT NOT NULL,
+		prompt_version TEXT NOT NULL,
+		operation TEXT NOT NULL,
+		prompt_tokens BIGINT NOT NULL,
+		response_tokens BIGINT NOT NULL,
+		latency_ms BIGINT NOT NULL,
+		cost_usd DOUBLE PRECISION NOT NULL,
+		error TEXT NOT NULL DEFAULT '',
+		created_at BIGINT NOT NULL
+	);
+	CREATE INDEX IF NOT EXISTS idx_llm_usage_created_at ON llm_usage(created_at);
+	`
+
+// jstDay unix秒のカラムを日本時間のYYYY-MM-DDにする式
+func (d dialect) jstDay(column string) string {
+	if d == dialectPostgres {
+		return "to_char(to_timestamp(" + column + " + 9 * 3600) AT TIME ZONE 'UTC', 'YYYY-MM-DD')"
+	}
+	return "date(" + column + " + 9 * 3600, 'unixepoch')"
+}
+
+// saveLLMUsage LLM呼び出しの利用量を保存
+func saveLLMUsage(db dbtx, d dialect, u *detect.LLMUsage) error {
+	query := `
+		INSERT INTO llm_usage
+		(backend, model, prompt_version, operation, prompt_tokens, response_tokens, latency_ms, cost_usd, error, created_at)
+		VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?)`
+
+	_, err := db.Exec(d.bind(query), u.Backend, u.Model, u.PromptVersion, u.Operation,
+		u.PromptTokens, u.ResponseTokens, u.Latency.Milliseconds(), u.CostUSD, u.Error, u.CreatedAt.Unix())
+	if err != nil {
+		return fmt.Errorf("LLM利用量保存エラー: %w", err)
+	}
+	return nil
+}
+
+// getLLMCostSince 指定時刻以降の推定コスト合計（ドル）
+func getLLMCostSince(db dbtx, d dialect, since time.Time) (float64, error) {
+	var cost float64
+	err := db.QueryRow(d.bind(`SELECT COALESCE(SUM(cost_usd), 0) FROM llm_usage WHERE created_at >= ?`),
+		since.Unix()).Scan(&cost)
+	if err != nil {
+		return 0, fmt.Errorf("LLM利用量取得エラー: %w", err)
+	}
+	return cost, nil
+}
+
+// LLMUsageSummary 日別・モデル別の利用量集計
+type LLMUsageSummary struct {
+	Day            string  `json:"day"` // 日本時間の日付
+	Backend        string  `json:"backend"`
+	Model          string  `json:"model"`
+	Operation      string  `json:"operation"`
+	Calls          int     `json:"calls"`
+	Errors         int     `json:"errors"`
+	PromptTokens   int     `json:"prompt_tokens"`
+	ResponseTokens int     `json:"response_tokens"`
+	AvgLatencyMs   float64 `json:"avg_latency_ms"`
+	CostUSD        float64 `json:"cost_usd"`
+}
+
+// getLLMUsageSummary 期間（日本時間のYYYY-MM-DD, 両端含む）の利用量を日別・モデル別に集計
+func getLLMUsageSummary(db dbtx, d dialect, from, to string) ([]*LLMUsageSummary, error) {
+	// PostgreSQLはWHEREで列の別名を参照できないため、日付の式をそのまま書く
+	day := d.jstDay("created_at")
+	query := `
+		SELECT ` + day + ` AS day, backend, model, operation,
+			COUNT(*), SUM(CASE WHEN error != '' THEN 1 ELSE 0 END),
+			SUM(prompt_tokens), SUM(response_tokens),
+			CAST(AVG(latency_ms) AS DOUBLE PRECISION), SUM(cost_usd)
+		FROM llm_usage
+		WHERE ` + day + ` BETWEEN ? AND ?
+		GROUP BY day, backend, model, operation
+		ORDER BY day, backend, model, operation`
+
+	rows, err := db.Query(d.bind(query), from, to)
+	if err != nil {
+		return nil, fmt.Errorf("LLM利用量集計エラー: %w", err)
+	}
+	defer rows.Close()
+
+	var list []*LLMUsageSummary
+	for rows.Next() {
+		u := &LLMUsageSummary{}
+		if err := rows.Scan(&u.Day, &u.Backend, &u.Model, &u.Operation, &u.Calls, &u.Errors,
+			&u.PromptTokens, &u.ResponseTokens, &u.AvgLatencyMs, &u.CostUSD); err != nil {
+			return nil, err
+		}
+		list = append(list, u)
+	}
+	return list, rows.Err()
+}
+
+// SaveLLMUsage LLM呼び出しの利用量を保存
+func (s *SQLiteClient) SaveLLMUsage(u *detect.LLMUsage) error {
+	return saveLLMUsage(s.db, dialectSQLite, u)
+}
+
+// GetLLMCostSince 指定時刻以降の推定コスト合計（ドル）
+func (s *SQLiteClient) GetLLMCostSince(since time.Time) (float64, error) {
+	return getLLMCostSince(s.db, dialectSQLite, since)
+}
+
+// GetLLMUsageSummary 期間（日本時間のYYYY-MM-DD, 両端含む）の利用量を日別・モデル別に集計
+func (s *SQLiteClient) GetLLMUsageSummary(from, to string) ([]*LLMUsageSummary, error) {
+	return getLLMUsageSummary(s.db, dialectSQLite, from, to)
+}
+
+// SaveLLMUsage LLM呼び出しの利用量を保存
+func (p *PostgresClient) SaveLLMUsage(u *detect.LLMUsage) error {
+	return saveLLMUsage(p.db, dialectPostgres, u)
+}
+
+// GetLLMCostSince 指定時刻以降の推定コスト合計（ドル）
+func (p *PostgresClient) GetLLMCostSince(since time.Time) (float64, error) {
+	return getLLMCostSince(p.db, dialectPostgres, since)
+}
+
+// GetLLMUsageSummary 期間（日本時間のYYYY-MM-DD, 両端含む）の利用量を日別・モデル別に集計
+func (p *PostgresClient) GetLLMUsageSummary(from, to string) ([]*LLMUsageSummary, error) {
+	return getLLMUsageSummary(p.db, dialectPostgres, from, to)
+}
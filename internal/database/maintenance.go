@@ -0,0 +1,299 @@
+package database
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"gasinsight/internal/timeseries"
+)
+
+// retentionTarget 保持期間を適用できるテーブルと、行の古さを判定する列
+type retentionTarget struct {
+	column string
+	unix   bool   // trueなら列はUNIX時刻、falseなら日付（YYYY-MM-DD）
+	with   string // 同じ日付の列で合わせて削除するテーブル（改定履歴）
+}
+
+var retentionTargets = map[string]retentionTarget{
+	"gas_prices":                {column: "date", with: "gas_price_revisions"},
+	"exchange_rates":            {column: "date", with: "exchange_rate_revisions"},
+	"crude_prices":              {column: "date"},
+	"fuel_subsidies":            {column: "week_start"},
+	"news_summaries":            {column: "substr(date, 1, 10)"},
+	"news_articles":             {column: "fetched_at", unix: true},
+	"price_change":              {column: "date_new"},
+	"price_change_attributions": {column: "created_at", unix: true},
+	"analysis_cache":            {column: "created_at", unix: true},
+	"llm_usage":                 {column: "created_at", unix: true},
+}
+
+// RetentionPolicy テーブルの保持期間（MaxAgeが0なら削除しない）
+type RetentionPolicy struct {
+	Table  string
+	MaxAge time.Duration
+}
+
+// DefaultRetention デフォルトの保持期間（取得した記事本文は1年。分析結果のニュースは残す）
+var DefaultRetention = []RetentionPolicy{
+	{Table: "news_articles", MaxAge: 365 * 24 * time.Hour},
+}
+
+// ParseRetention "テーブル=期間"のカンマ区切りを読み取り、basesの同じテーブルの保持期間を上書きする
+// 期間は 30d（日）, 12w（週）, 1y（365日）, 720h などで、offか0なら削除しない
+func ParseRetention(s string, bases []RetentionPolicy) ([]RetentionPolicy, error) {
+	byTable := map[string]time.Duration{}
+	for _, p := range bases {
+		byTable[p.Table] = p.MaxAge
+	}
+	for _, item := range strings.Split(s, ",") {
+		item = strings.TrimSpace(item)
+		if item == "" {
+			continue
+		}
+		table, age, ok := strings.Cut(item, "=")
+		if !ok {
+			return nil, fmt.Errorf("保持期間は テーブル=期間 で指定してください: %s", item)
+		}
+		table = strings.TrimSpace(table)
+		if _, ok := retentionTargets[table]; !ok {
+			return nil, fmt.Errorf("保持期間を指定できないテーブル: %s（%s）", table, strings.Join(RetentionTables(), "/"))
+		}
+		d, err := ParseAge(strings.TrimSpace(age))
+		if err != nil {
+			return nil, err
+		}
+		byTable[table] = d
+	}
+
+	policies := make([]RetentionPolicy, 0, len(byTable))
+	for table, d := range byTable {
+		policies = append(policies, RetentionPolicy{Table: table, MaxAge: d})
+	}
+	sort.Slice(policies, func(i, j int) bool { return policies[i].Table < policies[j].Table })
+	return policies, nil
+}
+
+// ParseAge 期間を読み取る（30d, 12w, 1y, 720h など。offか0なら0）
+func ParseAge(s string) (time.Duration, error) {
+	if s == "off" || s == "0" {
+		return 0, nil
+	}
+	units := map[string]time.Duration{"d": 24 * time.Hour, "w": 7 * 24 * time.Hour, "y": 365 * 24 * time.Hour}
+	for suffix, unit := range units {
+		if n, ok := strings.CutSuffix(s, suffix); ok {
+			v, err := strconv.Atoi(n)
+			if err != nil || v < 0 {
+				return 0, fmt.Errorf("期間の形式エラー: %s", s)
+			}
+			return time.Duration(v) * unit, nil
+		}
+	}
+	d, err := time.ParseDuration(s)
+	if err != nil || d < 0 {
+		return 0, fmt.Errorf("期間の形式エラー: %s（例: 30d, 12w, 1y, 720h）", s)
+	}
+	return d, nil
+}
+
+// RetentionTables 保持期間を指定できるテーブル
+func RetentionTables() []string {
+	tables := make([]string, 0, len(retentionTargets))
+	for t := range retentionTargets {
+		tables = append(tables, t)
+	}
+	sort.Strings(tables)
+	return tables
+}
+
+// RetentionResult テーブルごとの削除結果
+type RetentionResult struct {
+	Table   string
+	Cutoff  string // この日時（日付）より前の行が対象
+	Deleted int64  // 削除した（DryRunでは削除対象の）行数
+}
+
+// MaintenanceOptions メンテナンスの設定
+type MaintenanceOptions struct {
+	Retention       []RetentionPolicy
+	AnalyzeInterval time.Duration // ANALYZEの間隔（前回から経過していれば実行、0なら毎回）
+	VacuumInterval  time.Duration // VACUUMの間隔（0なら実行しない）
+	Force           bool          // 間隔に関わらずANALYZE・VACUUMを実行
+	DryRun          bool          // 削除対象の件数を数えるだけで、削除・ANALYZE・VACUUMはしない
+}
+
+// MaintenanceReport メンテナンスの結果
+type MaintenanceReport struct {
+	Retention  []RetentionResult
+	Analyzed   bool
+	Vacuumed   bool
+	SizeBefore int64 // DBファイルのサイズ（バイト）
+	SizeAfter  int64
+}
+
+// CreateMaintenanceTable ANALYZE・VACUUMの実行記録テーブルを作成
+func (s *SQLiteClient) CreateMaintenanceTable() error {
+	query := `
+	CREATE TABLE IF NOT EXISTS maintenance_runs (
+		task TEXT PRIMARY KEY,
+		ran_at INTEGER NOT NULL
+	);
+	`
+
+	if _, err := s.db.Exec(query); err != nil {
+		return fmt.Errorf("メンテナンス記録テーブル作成エラー: %w", err)
+	}
+	return nil
+}
+
+// Maintain 保持期間を過ぎた行を削除し、間隔が経過していればANALYZE・VACUUMを実行する
+// cronなどから定期的に実行する想定で、実行日時はmaintenance_runsに記録する
+func (s *SQLiteClient) Maintain(ctx context.Context, opts MaintenanceOptions) (*MaintenanceReport, error) {
+	if s.conn == nil {
+		return nil, errors.New("トランザクション内ではメンテナンスできません")
+	}
+	now := time.Now()
+	report := &MaintenanceReport{}
+
+	var err error
+	if report.SizeBefore, err = s.databaseSize(); err != nil {
+		return nil, err
+	}
+
+	err = s.WithTx(ctx, func(tx Store) error {
+		report.Retention, err = tx.(*SQLiteClient).applyRetention(opts.Retention, now, opts.DryRun)
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+	if opts.DryRun {
+		report.SizeAfter = report.SizeBefore
+		return report, nil
+	}
+
+	due, err := s.maintenanceDue("analyze", opts.AnalyzeInterval, now)
+	if err != nil {
+		return nil, err
+	}
+	if opts.Force || due {
+		if _, err := s.db.Exec(`ANALYZE`); err != nil {
+			return nil, fmt.Errorf("ANALYZEエラー: %w", err)
+		}
+		if err := s.recordMaintenance("analyze", now); err != nil {
+			return nil, err
+		}
+		report.Analyzed = true
+	}
+
+	due = false
+	if opts.VacuumInterval > 0 {
+		if due, err = s.maintenanceDue("vacuum", opts.VacuumInterval, now); err != nil {
+			return nil, err
+		}
+	}
+	if opts.Force || due {
+		if _, err := s.db.Exec(`VACUUM`); err != nil {
+			return nil, fmt.Errorf("VACUUMエラー: %w", err)
+		}
+		if err := s.recordMaintenance("vacuum", now); err != nil {
+			return nil, err
+		}
+		report.Vacuumed = true
+	}
+
+	if report.SizeAfter, err = s.databaseSize(); err != nil {
+		return nil, err
+	}
+	return report, nil
+}
+
+// applyRetention 保持期間を過ぎた行を削除（dryRunなら数えるだけ）
+func (s *SQLiteClient) applyRetention(policies []RetentionPolicy, now time.Time, dryRun bool) ([]RetentionResult, error) {
+	var results []RetentionResult
+	for _, p := range policies {
+		if p.MaxAge <= 0 {
+			continue
+		}
+		target, ok := retentionTargets[p.Table]
+		if !ok {
+			return results, fmt.Errorf("保持期間を指定できないテーブル: %s", p.Table)
+		}
+
+		// 日付の列は日本時間の日付で比較する
+		cutoffTime := now.Add(-p.MaxAge).In(timeseries.JST)
+		result := RetentionResult{Table: p.Table, Cutoff: cutoffTime.Format("2006-01-02")}
+		var cutoff interface{} = result.Cutoff
+		if target.unix {
+			cutoff = cutoffTime.Unix()
+			result.Cutoff = cutoffTime.Format("2006-01-02 15:04")
+		}
+
+		where := ` FROM ` + p.Table + ` WHERE ` + target.column + ` < ?`
+		if dryRun {
+			if err := s.db.QueryRow(`SELECT COUNT(*)`+where, cutoff).Scan(&result.Deleted); err != nil {
+				return results, fmt.Errorf("%sの件数取得エラー: %w", p.Table, err)
+			}
+		} else {
+			res, err := s.db.Exec(`DELETE`+where, cutoff)
+			if err != nil {
+				return results, fmt.Errorf("%sの削除エラー: %w", p.Table, err)
+			}
+			result.Deleted, _ = res.RowsAffected()
+			if target.with != "" {
+				if _, err := s.db.Exec(`DELETE FROM `+target.with+` WHERE `+target.column+` < ?`, cutoff); err != nil {
+					return results, fmt.Errorf("%sの削除エラー: %w", target.with, err)
+				}
+			}
+		}
+		results = append(results, result)
+	}
+
+	// 削除した価格変動の要因分析も削除する
+	if !dryRun {
+		if _, err := s.db.Exec(`DELETE FROM price_change_attributions
+			WHERE price_change_id NOT IN (SELECT id FROM price_change)`); err != nil {
+			return results, fmt.Errorf("要因分析の削除エラー: %w", err)
+		}
+	}
+	return results, nil
+}
+
+// maintenanceDue 前回の実行からintervalが経過しているか（未実行ならtrue）
+func (s *SQLiteClient) maintenanceDue(task string, interval time.Duration, now time.Time) (bool, error) {
+	var ranAt int64
+	err := s.db.QueryRow(`SELECT ran_at FROM maintenance_runs WHERE task = ?`, task).Scan(&ranAt)
+	if err == sql.ErrNoRows {
+		return true, nil
+	}
+	if err != nil {
+		return false, fmt.Errorf("メンテナンス記録取得エラー: %w", err)
+	}
+	return now.Sub(time.Unix(ranAt, 0)) >= interval, nil
+}
+
+func (s *SQLiteClient) recordMaintenance(task string, now time.Time) error {
+	_, err := s.db.Exec(`INSERT INTO maintenance_runs (task, ran_at) VALUES (?, ?)
+		ON CONFLICT (task) DO UPDATE SET ran_at = excluded.ran_at`, task, now.Unix())
+	if err != nil {
+		return fmt.Errorf("メンテナンス記録エラー: %w", err)
+	}
+	return nil
+}
+
+// databaseSize DBのサイズ（ページ数×ページサイズ）
+func (s *SQLiteClient) databaseSize() (int64, error) {
+	var pages, pageSize int64
+	if err := s.db.QueryRow(`PRAGMA page_count`).Scan(&pages); err != nil {
+		return 0, fmt.Errorf("DBサイズ取得エラー: %w", err)
+	}
+	if err := s.db.QueryRow(`PRAGMA page_size`).Scan(&pageSize); err != nil {
+		return 0, fmt.Errorf("DBサイズ取得エラー: %w", err)
+	}
+	return pages * pageSize, nil
+}
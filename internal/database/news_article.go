@@ -0,0 +1,101 @@
+package database
+
+import (
+	"database/sql"
+	"fmt"
+	"time"
+
+	fetcher "gasinsight/internal/fetch"
+)
+
+// CreateNewsArticleTable 取得した記事本文（分析前の原文）のテーブルを作成
+func (s *SQLiteClient) CreateNewsArticleTable() error {
+	query := `
+	CREATE TABLE IF NOT EXISTS news_articles (
+		url TEXT PRIMARY KEY,
+		title TEXT NOT NULL,
+		content TEXT NOT NULL,
+		source TEXT NOT NULL,
+		language TEXT NOT NULL,
+		published_at TEXT NOT NULL,
+		fetched_at INTEGER NOT NULL
+	);
+	CREATE INDEX IF NOT EXISTS idx_news_articles_published_at ON news_articles(published_at);
+	`
+
+	if _, err := s.db.Exec(query); err != nil {
+		return fmt.Errorf("記事本文テーブル作成エラー: %w", err)
+	}
+	return nil
+}
+
+// newsArticleTablePostgres PostgreSQLの記事本文テーブル
+const newsArticleTablePostgres = `
+	CREATE TABLE IF NOT EXISTS news_articles (
+		url TEXT PRIMARY KEY,
+		title TEXT NOT NULL,
+		content TEXT NOT NULL,
+		source TEXT NOT NULL,
+		language TEXT NOT NULL,
+		published_at TEXT NOT NULL,
+		fetched_at BIGINT NOT NULL
+	);
+	CREATE INDEX IF NOT EXISTS idx_news_articles_published_at ON news_articles(published_at);
+	`
+
+// saveNewsArticle 記事本文を保存（同じURLは上書き）
+func saveNewsArticle(db dbtx, d dialect, a fetcher.NewsArticle) error {
+	if a.URL == "" {
+		return nil
+	}
+
+	query := `
+		INSERT INTO news_articles
+		(url, title, content, source, language, published_at, fetched_at)
+		VALUES (?, ?, ?, ?, ?, ?, ?)
+		ON CONFLICT (url) DO UPDATE SET
+			title = excluded.title, content = excluded.content, source = excluded.source,
+			language = excluded.language, published_at = excluded.published_at, fetched_at = excluded.fetched_at`
+
+	_, err := db.Exec(d.bind(query), a.URL, a.Title, a.Content, a.Source, a.Language, a.Date, time.Now().Unix())
+	if err != nil {
+		return fmt.Errorf("記事本文保存エラー: %w", err)
+	}
+	return nil
+}
+
+// getNewsArticle URLから記事本文を取得（存在しなければnil）
+func getNewsArticle(db dbtx, d dialect, url string) (*fetcher.NewsArticle, error) {
+	var a fetcher.NewsArticle
+	err := db.QueryRow(d.bind(`
+		SELECT url, title, content, source, language, published_at
+		FROM news_articles WHERE url = ?`), url,
+	).Scan(&a.URL, &a.Title, &a.Content, &a.Source, &a.Language, &a.Date)
+	if err == sql.ErrNoRows {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("記事本文取得エラー: %w", err)
+	}
+	return &a, nil
+}
+
+// SaveNewsArticle 記事本文を保存（同じURLは上書き）
+func (s *SQLiteClient) SaveNewsArticle(a fetcher.NewsArticle) error {
+	return saveNewsArticle(s.db, dialectSQLite, a)
+}
+
+// GetNewsArticle URLから記事本文を取得（存在しなければnil）
+func (s *SQLiteClient) GetNewsArticle(url string) (*fetcher.NewsArticle, error) {
+	return getNewsArticle(s.db, dialectSQLite, url)
+}
+
+// SaveNewsArticle 記事本文を保存（同じURLは上書き）
+func (p *PostgresClient) SaveNewsArticle(a fetcher.NewsArticle) error {
+	return saveNewsArticle(p.db, dialectPostgres, a)
+}
+
+// GetNewsArticle URLから記事本文を取得（存在しなければnil）
+func (p *PostgresClient) GetNewsArticle(url string) (*fetcher.NewsArticle, error) {
+	return getNewsArticle(p.db, dialectPostgres, url)
+}
@@ -0,0 +1,356 @@
+package database
+
+import (
+	"fmt"
+	"html"
+	"log"
+	"regexp"
+	"sort"
+	"strings"
+	"unicode/utf8"
+
+	"gasinsight/internal/detect"
+)
+
+// ニュース検索の件数（省略時と上限）
+const (
+	DefaultNewsSearchLimit = 20
+	MaxNewsSearchLimit     = 100
+)
+
+// trigramMinRunes trigramトークナイザーで検索できる語の最小文字数
+const trigramMinRunes = 3
+
+// NewsSearchQuery ニュースの全文検索条件
+type NewsSearchQuery struct {
+	Query          string // 検索語（空白区切りで全てを含む記事を検索）
+	Sentiment      string // 感情で絞り込む（positive/neutral/negative）
+	From           string // 期間の開始日（YYYY-MM-DD）
+	To             string // 期間の終了日（YYYY-MM-DD）
+	Limit          int    // 最大件数（0ならDefaultNewsSearchLimit）
+	HighlightStart string // 一致箇所の前に付ける文字列（省略時は<mark>）
+	HighlightEnd   string // 一致箇所の後に付ける文字列（省略時は</mark>）
+	PlainText      bool   // タイトル・抜粋をHTMLエスケープしない（端末に表示する場合）
+}
+
+// NewsSearchHit 検索に一致したニュース
+type NewsSearchHit struct {
+	*detect.AnalyzedNews
+	Score          float64 // 関連度（大きいほど関連が高い。同じ検索の中でのみ比較できる）
+	TitleHighlight string  // 一致箇所を強調したタイトル（PlainTextでなければHTMLエスケープ済み）
+	Snippet        string  // 一致箇所の周辺を強調した要約の抜粋（同上）
+}
+
+// 強調箇所の目印（Unicodeの私用領域）
+// 検索結果のテキストをHTMLエスケープしてから、強調の文字列に置き換える
+const (
+	highlightOpen  = "\uE000"
+	highlightClose = "\uE001"
+)
+
+// highlight 目印で囲んだ一致箇所を強調の文字列にする（PlainTextでなければ先にテキストをHTMLエスケープ）
+func (q NewsSearchQuery) highlight(s string) string {
+	if !q.PlainText {
+		s = html.EscapeString(s)
+	}
+	return strings.NewReplacer(highlightOpen, q.HighlightStart, highlightClose, q.HighlightEnd).Replace(s)
+}
+
+// FullTextSearchEnabled ニュースの全文検索（FTS5）が使えるか
+// go-sqlite3をsqlite_fts5タグ付きでビルドしていない場合はfalseで、検索はLIKEで行う
+func (s *SQLiteClient) FullTextSearchEnabled() bool {
+	return s.fts
+}
+
+// setupNewsSearch ニュースのタイトル・要約の全文検索インデックス（news_fts）を用意する
+// 日本語は単語の区切りがないため、3文字単位で索引するtrigramトークナイザーを使う
+// news_summariesの変更はトリガーでインデックスに反映する
+func (s *SQLiteClient) setupNewsSearch() error {
+	var enabled bool
+	if err := s.db.QueryRow(`SELECT sqlite_compileoption_used('ENABLE_FTS5')`).Scan(&enabled); err != nil {
+		return fmt.Errorf("FTS5の確認エラー: %w", err)
+	}
+
+	var triggers int
+	if err := s.db.QueryRow(`SELECT COUNT(*) FROM sqlite_master WHERE type = 'trigger' AND name LIKE 'news_fts_%'`).Scan(&triggers); err != nil {
+		return fmt.Errorf("全文検索トリガー確認エラー: %w", err)
+	}
+
+	if !enabled {
+		if triggers == 0 {
+			return nil
+		}
+		// FTS5付きのビルドで作成したDBでも書き込めるよう、インデックスを更新するトリガーを外す
+		// （FTS5付きのビルドで次に開いた時にインデックスを作り直す）
+		log.Println("⚠️  FTS5なしのビルドのため、ニュースの全文検索のトリガーを外します（-tags sqlite_fts5 付きのビルドで開くと索引を作り直します）")
+		for _, name := range []string{"news_fts_ai", "news_fts_ad", "news_fts_au"} {
+			if _, err := s.db.Exec(`DROP TRIGGER IF EXISTS ` + name); err != nil {
+				return fmt.Errorf("全文検索トリガー削除エラー: %w", err)
+			}
+		}
+		return nil
+	}
+
+	// VACUUMでrowidが変わってもずれないよう、外部コンテンツではなくIDを持たせる
+	if _, err := s.db.Exec(`CREATE VIRTUAL TABLE IF NOT EXISTS news_fts USING fts5(
+		id UNINDEXED, title, summary, tokenize = 'trigram'
+	)`); err != nil {
+		return fmt.Errorf("全文検索インデックス作成エラー: %w", err)
+	}
+
+	if triggers < 3 {
+		// 新規作成時、またはFTS5なしのビルドで書き込まれた可能性がある場合は作り直す
+		for _, q := range []string{
+			`DROP TRIGGER IF EXISTS news_fts_ai`,
+			`DROP TRIGGER IF EXISTS news_fts_ad`,
+			`DROP TRIGGER IF EXISTS news_fts_au`,
+			`CREATE TRIGGER news_fts_ai AFTER INSERT ON news_summaries BEGIN
+				INSERT INTO news_fts (id, title, summary) VALUES (new.id, new.title, new.summary);
+			END`,
+			`CREATE TRIGGER news_fts_ad AFTER DELETE ON news_summaries BEGIN
+				DELETE FROM news_fts WHERE id = old.id;
+			END`,
+			`CREATE TRIGGER news_fts_au AFTER UPDATE OF id, title, summary ON news_summaries BEGIN
+				DELETE FROM news_fts WHERE id = old.id;
+				INSERT INTO news_fts (id, title, summary) VALUES (new.id, new.title, new.summary);
+			END`,
+			`DELETE FROM news_fts`,
+			`INSERT INTO news_fts (id, title, summary) SELECT id, title, summary FROM news_summaries`,
+		} {
+			if _, err := s.db.Exec(q); err != nil {
+				return fmt.Errorf("全文検索インデックス作成エラー: %w", err)
+			}
+		}
+		log.Println("✅ ニュースの全文検索インデックスを作成しました")
+	}
+
+	s.fts = true
+	return nil
+}
+
+// SearchNews タイトル・要約を検索し、関連度の高い順に返す
+// 全文検索では3文字未満の語を検索できないため、検索語に3文字未満の語を含む場合と
+// FTS5が使えない場合はLIKEで検索し、一致した回数（タイトルは2倍）を関連度とする
+func (s *SQLiteClient) SearchNews(q NewsSearchQuery) ([]*NewsSearchHit, error) {
+	q, terms, where, args, err := newsSearchFilters(q)
+	if err != nil {
+		return nil, err
+	}
+	if s.fts && minRunes(terms) >= trigramMinRunes {
+		return s.searchNewsFTS(q, terms, where, args)
+	}
+	return searchNewsLike(s.db, dialectSQLite, q, terms, where, args)
+}
+
+// SearchNews タイトル・要約をLIKE（英字の大文字・小文字は区別しない）で検索し、関連度の高い順に返す
+func (p *PostgresClient) SearchNews(q NewsSearchQuery) ([]*NewsSearchHit, error) {
+	q, terms, where, args, err := newsSearchFilters(q)
+	if err != nil {
+		return nil, err
+	}
+	return searchNewsLike(p.db, dialectPostgres, q, terms, where, args)
+}
+
+// FullTextSearchEnabled ニュースの全文検索が使えるか（PostgreSQLでは常にLIKEで検索する）
+func (p *PostgresClient) FullTextSearchEnabled() bool {
+	return false
+}
+
+// newsSearchFilters 検索条件を確認して省略値を補い、検索語と共通の絞り込み条件を返す
+func newsSearchFilters(q NewsSearchQuery) (NewsSearchQuery, []string, []string, []interface{}, error) {
+	terms := strings.Fields(q.Query)
+	if len(terms) == 0 {
+		return q, nil, nil, nil, fmt.Errorf("%w: 検索語を指定してください", ErrInvalidQuery)
+	}
+	if q.Limit < 0 || q.Limit > MaxNewsSearchLimit {
+		return q, nil, nil, nil, fmt.Errorf("%w: limitは1〜%dで指定してください", ErrInvalidQuery, MaxNewsSearchLimit)
+	}
+	if q.Limit == 0 {
+		q.Limit = DefaultNewsSearchLimit
+	}
+	if q.HighlightStart == "" && q.HighlightEnd == "" {
+		q.HighlightStart, q.HighlightEnd = "<mark>", "</mark>"
+	}
+
+	var where []string
+	var args []interface{}
+	if q.Sentiment != "" {
+		switch detect.Sentiment(q.Sentiment) {
+		case detect.SentimentPositive, detect.SentimentNeutral, detect.SentimentNegative:
+		default:
+			return q, nil, nil, nil, fmt.Errorf("%w: 感情はpositive/neutral/negativeで指定してください: %s", ErrInvalidQuery, q.Sentiment)
+		}
+		where = append(where, "n.sentiment = ?")
+		args = append(args, q.Sentiment)
+	}
+	if err := checkDates(q.From, q.To); err != nil {
+		return q, nil, nil, nil, err
+	}
+	if q.From != "" {
+		where = append(where, "substr(n.date, 1, 10) >= ?")
+		args = append(args, q.From)
+	}
+	if q.To != "" {
+		where = append(where, "substr(n.date, 1, 10) <= ?")
+		args = append(args, q.To)
+	}
+	return q, terms, where, args, nil
+}
+
+// searchNewsFTS FTS5で検索し、BM25（タイトルを2倍に重み付け）の順に返す
+func (s *SQLiteClient) searchNewsFTS(q NewsSearchQuery, terms []string, where []string, args []interface{}) ([]*NewsSearchHit, error) {
+	// 各語をフレーズとして扱い、FTS5の演算子として解釈させない
+	phrases := make([]string, len(terms))
+	for i, t := range terms {
+		phrases[i] = `"` + strings.ReplaceAll(t, `"`, `""`) + `"`
+	}
+	where = append([]string{"news_fts MATCH ?"}, where...)
+	args = append([]interface{}{strings.Join(phrases, " ")}, args...)
+
+	query := `SELECT ` + prefixColumns(newsColumns, "n") + `,
+			bm25(news_fts, 0, 2.0, 1.0),
+			highlight(news_fts, 1, ?, ?),
+			snippet(news_fts, 2, ?, ?, '…', 32)
+		FROM news_fts JOIN news_summaries n ON n.id = news_fts.id
+		WHERE ` + strings.Join(where, " AND ") + `
+		ORDER BY bm25(news_fts, 0, 2.0, 1.0), n.date DESC
+		LIMIT ?`
+	args = append([]interface{}{highlightOpen, highlightClose, highlightOpen, highlightClose}, args...)
+	args = append(args, q.Limit)
+
+	rows, err := s.db.Query(query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("ニュース検索エラー: %w", err)
+	}
+	defer rows.Close()
+
+	var hits []*NewsSearchHit
+	for rows.Next() {
+		hit := &NewsSearchHit{AnalyzedNews: &detect.AnalyzedNews{}}
+		var updatedAt int64
+		var rank float64
+		dest := append(newsScanDest(hit.AnalyzedNews, &updatedAt), &rank, &hit.TitleHighlight, &hit.Snippet)
+		if err := rows.Scan(dest...); err != nil {
+			return nil, err
+		}
+		// bm25は関連が高いほど小さい負の値になる
+		hit.Score = -rank
+		hit.TitleHighlight, hit.Snippet = q.highlight(hit.TitleHighlight), q.highlight(hit.Snippet)
+		hits = append(hits, hit)
+	}
+	return hits, rows.Err()
+}
+
+// searchNewsLike LIKEで全ての語を含む記事を検索し、一致した回数の順に返す
+func searchNewsLike(db dbtx, d dialect, q NewsSearchQuery, terms []string, where []string, args []interface{}) ([]*NewsSearchHit, error) {
+	like := d.caseInsensitiveLike()
+	for _, t := range terms {
+		where = append(where, `(n.title `+like+` ? ESCAPE '\' OR n.summary `+like+` ? ESCAPE '\')`)
+		pattern := "%" + escapeLike(t) + "%"
+		args = append(args, pattern, pattern)
+	}
+
+	rows, err := db.Query(d.bind(`SELECT `+prefixColumns(newsColumns, "n")+`
+		FROM news_summaries n WHERE `+strings.Join(where, " AND ")), args...)
+	if err != nil {
+		return nil, fmt.Errorf("ニュース検索エラー: %w", err)
+	}
+	defer rows.Close()
+	list, err := scanNews(rows)
+	if err != nil {
+		return nil, err
+	}
+
+	re := termsPattern(terms)
+	hits := make([]*NewsSearchHit, 0, len(list))
+	for _, n := range list {
+		score := 2*len(re.FindAllStringIndex(n.Title, -1)) + len(re.FindAllStringIndex(n.Summary, -1))
+		hits = append(hits, &NewsSearchHit{
+			AnalyzedNews:   n,
+			Score:          float64(score),
+			TitleHighlight: q.highlight(re.ReplaceAllString(n.Title, highlightOpen+"$0"+highlightClose)),
+			Snippet:        q.highlight(likeSnippet(n.Summary, re)),
+		})
+	}
+	sort.SliceStable(hits, func(i, j int) bool {
+		if hits[i].Score != hits[j].Score {
+			return hits[i].Score > hits[j].Score
+		}
+		return hits[i].Date > hits[j].Date
+	})
+	if len(hits) > q.Limit {
+		hits = hits[:q.Limit]
+	}
+	return hits, nil
+}
+
+// snippetRunes LIKE検索の抜粋で一致箇所の前後に含める文字数
+const snippetRunes = 16
+
+// likeSnippet 最初の一致箇所の前後を抜き出し、抜粋内の一致箇所を目印で囲む
+func likeSnippet(text string, re *regexp.Regexp) string {
+	loc := re.FindStringIndex(text)
+	if loc == nil {
+		loc = []int{0, 0}
+	}
+	from, to := loc[0], loc[1]
+	for i := 0; i < snippetRunes && from > 0; i++ {
+		_, size := utf8.DecodeLastRuneInString(text[:from])
+		from -= size
+	}
+	for i := 0; i < snippetRunes && to < len(text); i++ {
+		_, size := utf8.DecodeRuneInString(text[to:])
+		to += size
+	}
+	snippet := re.ReplaceAllString(text[from:to], highlightOpen+"$0"+highlightClose)
+	if from > 0 {
+		snippet = "…" + snippet
+	}
+	if to < len(text) {
+		snippet += "…"
+	}
+	return snippet
+}
+
+// termsPattern いずれかの語に一致する正規表現（英字の大文字・小文字は区別しない）
+func termsPattern(terms []string) *regexp.Regexp {
+	quoted := make([]string, len(terms))
+	for i, t := range terms {
+		quoted[i] = regexp.QuoteMeta(t)
+	}
+	// 重なる語は長い方を優先して強調する
+	sort.SliceStable(quoted, func(i, j int) bool { return len(quoted[i]) > len(quoted[j]) })
+	return regexp.MustCompile(`(?i)` + strings.Join(quoted, "|"))
+}
+
+// caseInsensitiveLike 英字の大文字・小文字を区別しないLIKE演算子（SQLiteのLIKEは区別しない）
+func (d dialect) caseInsensitiveLike() string {
+	if d == dialectPostgres {
+		return "ILIKE"
+	}
+	return "LIKE"
+}
+
+// escapeLike LIKEのワイルドカードをエスケープ
+func escapeLike(s string) string {
+	return strings.NewReplacer(`\`, `\\`, `%`, `\%`, `_`, `\_`).Replace(s)
+}
+
+// prefixColumns カンマ区切りの列名にテーブルの別名を付ける
+func prefixColumns(columns, alias string) string {
+	cols := strings.Split(columns, ",")
+	for i, c := range cols {
+		cols[i] = alias + "." + strings.TrimSpace(c)
+	}
+	return strings.Join(cols, ", ")
+}
+
+func minRunes(terms []string) int {
+	n := -1
+	for _, t := range terms {
+		if l := utf8.RuneCountInString(t); n < 0 || l < n {
+			n = l
+		}
+	}
+	return n
+}
@@ -0,0 +1,66 @@
+package database
+
+import (
+	"strings"
+	"testing"
+
+	"gasinsight/internal/detect"
+)
+
+func newSearchStore(t *testing.T) *SQLiteClient {
+	t.Helper()
+	s, err := NewSQLiteClient(":memory:")
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { s.Close() })
+	for _, n := range []*detect.AnalyzedNews{
+		{Title: `<script>alert(1)</script> 原油価格が急騰`, Summary: `OPEC & 産油国が減産を延長し、原油価格が"急騰"した。`,
+			Sentiment: detect.SentimentNegative, Date: "2025-01-10", URL: "https://example.com/1"},
+		{Title: "円高で輸入コストが低下", Summary: "為替は円高に振れた。", Sentiment: detect.SentimentPositive,
+			Date: "2025-01-11", URL: "https://example.com/2"},
+	} {
+		if err := s.SaveNews(n); err != nil {
+			t.Fatal(err)
+		}
+	}
+	return s
+}
+
+func TestSearchNewsEscapesHTML(t *testing.T) {
+	s := newSearchStore(t)
+	t.Logf("FTS5: %v", s.FullTextSearchEnabled())
+	// 3文字以上はFTS5（タグ付きのビルド）、2文字の語を含む場合はLIKEで検索する
+	for _, query := range []string{"原油価格", "原油"} {
+		hits, err := s.SearchNews(NewsSearchQuery{Query: query})
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(hits) != 1 {
+			t.Fatalf("%s: %d件, want 1", query, len(hits))
+		}
+		h := hits[0]
+		if want := "&lt;script&gt;alert(1)&lt;/script&gt; <mark>" + query + "</mark>"; !strings.HasPrefix(h.TitleHighlight, want) {
+			t.Errorf("%s: タイトル = %q", query, h.TitleHighlight)
+		}
+		for _, raw := range []string{"<script>", " & ", `"急騰"`} {
+			if strings.Contains(h.TitleHighlight+h.Snippet, raw) {
+				t.Errorf("%s: %q がエスケープされていません: %q / %q", query, raw, h.TitleHighlight, h.Snippet)
+			}
+		}
+		if !strings.Contains(h.Snippet, "<mark>"+query+"</mark>") || !strings.Contains(h.Snippet, "&amp;") {
+			t.Errorf("%s: 抜粋 = %q", query, h.Snippet)
+		}
+	}
+}
+
+func TestSearchNewsPlainText(t *testing.T) {
+	s := newSearchStore(t)
+	hits, err := s.SearchNews(NewsSearchQuery{Query: "原油", HighlightStart: "**", HighlightEnd: "**", PlainText: true})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(hits) != 1 || hits[0].TitleHighlight != "<script>alert(1)</script> **原油**価格が急騰" {
+		t.Errorf("タイトル = %+v", hits)
+	}
+}
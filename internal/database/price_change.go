@@ -0,0 +1,217 @@
+package database
+
+import (
+	"database/sql"
+	"fmt"
+	"log"
+	"time"
+
+	model "gasinsight/internal/model"
+)
+
+// priceChangeTimeLayout 変動記録の作成日時の形式（SQLiteのdatetime('now')と同じ、UTC）
+const priceChangeTimeLayout = "2006-01-02 15:04:05"
+
+// priceChangeColumns 変動記録取得時の列
+const priceChangeColumns = `id, region, date_new, price_new, date_old, price_old, pct_change, flagged, created_at`
+
+// CreatePriceChangeTables 変動検知・要因分析テーブルを作成
+func (s *SQLiteClient) CreatePriceChangeTables() error {
+	query := `
+	CREATE TABLE IF NOT EXISTS price_change (
+		id INTEGER PRIMARY KEY AUTOINCREMENT,
+		region TEXT,
+		date_new TEXT,
+		price_new REAL,
+		date_old TEXT,
+		price_old REAL,
+		pct_change REAL,
+		flagged INTEGER,
+		created_at DATETIME DEFAULT (datetime('now'))
+	);
+
+	CREATE TABLE IF NOT EXISTS price_change_attributions (
+		id TEXT PRIMARY KEY,
+		price_change_id INTEGER NOT NULL,
+		analyzer TEXT NOT NULL,
+		news_count INTEGER NOT NULL,
+		usd_jpy_old REAL NOT NULL,
+		usd_jpy_new REAL NOT NULL,
+		report TEXT NOT NULL,
+		created_at INTEGER NOT NULL
+	);
+	CREATE INDEX IF NOT EXISTS idx_price_change_attributions_change ON price_change_attributions(price_change_id);
+	`
+
+	if _, err := s.db.Exec(query); err != nil {
+		return fmt.Errorf("変動検知テーブル作成エラー: %w", err)
+	}
+
+	log.Println("✅ 変動検知テーブルを作成しました")
+	return nil
+}
+
+// SavePriceChange 変動記録を保存し、採番されたIDと作成日時を設定
+func (s *SQLiteClient) SavePriceChange(c *model.PriceChangeRecord) error {
+	if c.CreatedAt == "" {
+		c.CreatedAt = time.Now().UTC().Format(priceChangeTimeLayout)
+	}
+	res, err := s.db.Exec(`
+		INSERT INTO price_change (region, date_new, price_new, date_old, price_old, pct_change, flagged, created_at)
+		VALUES (?, ?, ?, ?, ?, ?, ?, ?)`,
+		c.Region, c.DateNew, c.PriceNew, c.DateOld, c.PriceOld, c.PctChange, c.Flagged, c.CreatedAt)
+	if err != nil {
+		return fmt.Errorf("変動記録保存エラー: %w", err)
+	}
+	if c.ID, err = res.LastInsertId(); err != nil {
+		return fmt.Errorf("変動記録保存エラー: %w", err)
+	}
+	return nil
+}
+
+// PriceChangeExists 同じ地域・日付の組み合わせの変動記録があるか
+func (s *SQLiteClient) PriceChangeExists(region, dateNew, dateOld string) (bool, error) {
+	var n int
+	err := s.db.QueryRow(`SELECT COUNT(*) FROM price_change WHERE region = ? AND date_new = ? AND date_old = ?`,
+		region, dateNew, dateOld).Scan(&n)
+	if err != nil {
+		return false, fmt.Errorf("変動記録取得エラー: %w", err)
+	}
+	return n > 0, nil
+}
+
+// GetLatestFlaggedPriceChange しきい値を超えた最新の変動記録を取得
+func (s *SQLiteClient) GetLatestFlaggedPriceChange() (*model.PriceChangeRecord, error) {
+	query := `
+		SELECT id, region, date_new, price_new, date_old, price_old, pct_change, flagged, created_at
+		FROM price_change
+		WHERE flagged = 1
+		ORDER BY date_new DESC, id DESC
+		LIMIT 1`
+
+	var c model.PriceChangeRecord
+	err := s.db.QueryRow(query).Scan(&c.ID, &c.Region, &c.DateNew, &c.PriceNew,
+		&c.DateOld, &c.PriceOld, &c.PctChange, &c.Flagged, &c.CreatedAt)
+
+	if err == sql.ErrNoRows {
+		return nil, fmt.Errorf("しきい値を超えた価格変動が見つかりません")
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	return &c, nil
+}
+
+// GetFlaggedPriceChangesBetween 比較後の日付が指定期間（YYYY-MM-DD, 両端含む）でしきい値を超えた変動記録を取得
+func (s *SQLiteClient) GetFlaggedPriceChangesBetween(from, to string) ([]*model.PriceChangeRecord, error) {
+	query := `
+		SELECT id, region, date_new, price_new, date_old, price_old, pct_change, flagged, created_at
+		FROM price_change
+		WHERE flagged = 1 AND date_new BETWEEN ? AND ?
+		ORDER BY date_new, id`
+
+	rows, err := s.db.Query(query, from, to)
+	if err != nil {
+		return nil, fmt.Errorf("変動記録取得エラー: %w", err)
+	}
+	defer rows.Close()
+
+	return scanPriceChanges(rows)
+}
+
+// scanPriceChanges 変動記録の行を読み取る
+func scanPriceChanges(rows *sql.Rows) ([]*model.PriceChangeRecord, error) {
+	var list []*model.PriceChangeRecord
+	for rows.Next() {
+		var c model.PriceChangeRecord
+		if err := rows.Scan(&c.ID, &c.Region, &c.DateNew, &c.PriceNew,
+			&c.DateOld, &c.PriceOld, &c.PctChange, &c.Flagged, &c.CreatedAt); err != nil {
+			return nil, err
+		}
+		list = append(list, &c)
+	}
+	return list, rows.Err()
+}
+
+// upsertPriceChange 同じ地域・日付の組み合わせの変動記録があれば値を更新し、なければ新規に保存する
+// 渡されたIDは使わず、保存後は保存先のIDを設定する。追加・更新した場合はtrueを返す
+func upsertPriceChange(db dbtx, d dialect, save func(*model.PriceChangeRecord) error, c *model.PriceChangeRecord) (bool, error) {
+	var cur model.PriceChangeRecord
+	err := db.QueryRow(d.bind(`SELECT `+priceChangeColumns+` FROM price_change
+		WHERE region = ? AND date_new = ? AND date_old = ? ORDER BY id LIMIT 1`),
+		c.Region, c.DateNew, c.DateOld).Scan(&cur.ID, &cur.Region, &cur.DateNew, &cur.PriceNew,
+		&cur.DateOld, &cur.PriceOld, &cur.PctChange, &cur.Flagged, &cur.CreatedAt)
+	if err == sql.ErrNoRows {
+		c.ID = 0
+		return true, save(c)
+	}
+	if err != nil {
+		return false, fmt.Errorf("変動記録取得エラー: %w", err)
+	}
+
+	c.ID, c.CreatedAt = cur.ID, cur.CreatedAt
+	if cur.PriceNew == c.PriceNew && cur.PriceOld == c.PriceOld && cur.PctChange == c.PctChange && cur.Flagged == c.Flagged {
+		return false, nil
+	}
+	_, err = db.Exec(d.bind(`UPDATE price_change SET price_new = ?, price_old = ?, pct_change = ?, flagged = ? WHERE id = ?`),
+		c.PriceNew, c.PriceOld, c.PctChange, c.Flagged, c.ID)
+	if err != nil {
+		return false, fmt.Errorf("変動記録保存エラー: %w", err)
+	}
+	return true, nil
+}
+
+// UpsertPriceChange 地域・日付の組み合わせで変動記録を追加・更新（追加・更新した場合はtrue）
+func (s *SQLiteClient) UpsertPriceChange(c *model.PriceChangeRecord) (bool, error) {
+	return upsertPriceChange(s.db, dialectSQLite, s.SavePriceChange, c)
+}
+
+// ListPriceChanges 条件に合う変動記録と次ページのカーソル（日付は比較後の日付）
+func (s *SQLiteClient) ListPriceChanges(q ListQuery) ([]*model.PriceChangeRecord, string, error) {
+	return list(s.db, dialectSQLite, priceChangeList, q)
+}
+
+// SavePriceChangeAttribution 要因分析レポートを保存
+func (s *SQLiteClient) SavePriceChangeAttribution(a *model.PriceChangeAttribution) error {
+	query := `
+		INSERT INTO price_change_attributions
+		(id, price_change_id, analyzer, model, prompt_version, news_count, usd_jpy_old, usd_jpy_new, report, created_at)
+		VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?)`
+
+	_, err := s.db.Exec(query, a.ID, a.PriceChangeID, a.Analyzer, a.Model, a.PromptVersion, a.NewsCount,
+		a.USDJPYOld, a.USDJPYNew, a.Report, a.CreatedAt)
+	if err != nil {
+		return fmt.Errorf("要因分析レポート保存エラー: %w", err)
+	}
+
+	log.Printf("✅ 要因分析レポートを保存: price_change_id=%d", a.PriceChangeID)
+	return nil
+}
+
+// GetAttributionsByPriceChange 変動記録に紐づく要因分析レポートを取得
+func (s *SQLiteClient) GetAttributionsByPriceChange(priceChangeID int64) ([]*model.PriceChangeAttribution, error) {
+	query := `
+		SELECT id, price_change_id, analyzer, model, prompt_version, news_count, usd_jpy_old, usd_jpy_new, report, created_at
+		FROM price_change_attributions
+		WHERE price_change_id = ?
+		ORDER BY created_at DESC`
+
+	rows, err := s.db.Query(query, priceChangeID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var list []*model.PriceChangeAttribution
+	for rows.Next() {
+		var a model.PriceChangeAttribution
+		if err := rows.Scan(&a.ID, &a.PriceChangeID, &a.Analyzer, &a.Model, &a.PromptVersion, &a.NewsCount,
+			&a.USDJPYOld, &a.USDJPYNew, &a.Report, &a.CreatedAt); err != nil {
+			return nil, err
+		}
+		list = append(list, &a)
+	}
+
+	return list, rows.Err()
+}
@@ -0,0 +1,305 @@
+package database
+
+import (
+	"bytes"
+	"database/sql"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"gasinsight/internal/detect"
+	model "gasinsight/internal/model"
+)
+
+// ErrInvalidQuery 一覧・集計の条件が不正（並び順・カーソル・日付の形式など）
+var ErrInvalidQuery = errors.New("検索条件が不正です")
+
+// ListQuery 一覧取得の条件（期間・絞り込み・並び順・ページング）
+type ListQuery struct {
+	From   string // 開始日（YYYY-MM-DD、両端含む。空なら制限なし）
+	To     string // 終了日
+	Region string // 地域（ガソリン価格のみ）
+	Source string // データソース / ニュースの取得元
+	Sort   string // 並び順（date, -date など。先頭の-は降順。空なら-date）
+	Limit  int    // 最大件数（0なら制限なし）
+	Offset int    // 読み飛ばす件数（Cursorとは併用不可）
+	Cursor string // 前ページのNextCursor
+}
+
+// sortKey 並び順に使う列と、カーソル作成用にその値を取り出す関数
+type sortKey[T any] struct {
+	column string
+	value  func(T) interface{}
+}
+
+// listSpec テーブルごとの一覧取得の定義
+type listSpec[T any] struct {
+	name         string // エラーメッセージ用
+	query        string // SELECT ... FROM ...（WHERE以降は付けない）
+	dateColumn   string
+	regionColumn string // 空なら地域で絞り込めない
+	sourceColumn string
+	sorts        map[string]sortKey[T]
+	id           func(T) string
+	scan         func(*sql.Rows) ([]T, error)
+}
+
+// listCursor キーセットページングのカーソル（最後の行の並び順の値とID）
+type listCursor struct {
+	Sort  string      `json:"s"`
+	Value interface{} `json:"v"`
+	ID    string      `json:"id"`
+}
+
+func (c listCursor) encode() string {
+	data, _ := json.Marshal(c)
+	return base64.RawURLEncoding.EncodeToString(data)
+}
+
+func decodeListCursor(s string) (listCursor, error) {
+	var c listCursor
+	data, err := base64.RawURLEncoding.DecodeString(s)
+	if err == nil {
+		dec := json.NewDecoder(bytes.NewReader(data))
+		dec.UseNumber()
+		err = dec.Decode(&c)
+	}
+	if err != nil {
+		return c, fmt.Errorf("%w: カーソルの形式が不正です", ErrInvalidQuery)
+	}
+	// 整数の列（created_atなど）は整数のまま比較する
+	if n, ok := c.Value.(json.Number); ok {
+		if i, err := n.Int64(); err == nil {
+			c.Value = i
+		} else if f, err := n.Float64(); err == nil {
+			c.Value = f
+		}
+	}
+	return c, nil
+}
+
+// dialect SQLiteとPostgreSQLのSQLの違い
+type dialect int
+
+const (
+	dialectSQLite dialect = iota
+	dialectPostgres
+)
+
+// bind プレースホルダ?をPostgreSQLの$1, $2...に置き換える
+func (d dialect) bind(query string) string {
+	if d != dialectPostgres {
+		return query
+	}
+	var b strings.Builder
+	n := 0
+	for _, r := range query {
+		if r == '?' {
+			n++
+			fmt.Fprintf(&b, "$%d", n)
+			continue
+		}
+		b.WriteRune(r)
+	}
+	return b.String()
+}
+
+// noLimit 件数を制限しないLIMIT句（SQLiteはOFFSETだけを指定できない）
+func (d dialect) noLimit() string {
+	if d == dialectPostgres {
+		return "LIMIT ALL"
+	}
+	return "LIMIT -1"
+}
+
+// checkDates 空でない日付がYYYY-MM-DDか確認する
+func checkDates(dates ...string) error {
+	for _, d := range dates {
+		if _, err := time.Parse("2006-01-02", d); d != "" && err != nil {
+			return fmt.Errorf("%w: 日付はYYYY-MM-DDで指定してください: %s", ErrInvalidQuery, d)
+		}
+	}
+	return nil
+}
+
+// list 条件に合う行を取得し、続きがあり得る場合は次ページのカーソルを返す
+func list[T any](db dbtx, d dialect, spec listSpec[T], q ListQuery) ([]T, string, error) {
+	sortName := q.Sort
+	if sortName == "" {
+		sortName = "-date"
+	}
+	desc := strings.HasPrefix(sortName, "-")
+	key, ok := spec.sorts[strings.TrimPrefix(sortName, "-")]
+	if !ok {
+		names := make([]string, 0, len(spec.sorts))
+		for name := range spec.sorts {
+			names = append(names, name)
+		}
+		sort.Strings(names)
+		return nil, "", fmt.Errorf("%w: %sの並び順 %s（%s、先頭に-で降順）", ErrInvalidQuery, spec.name, q.Sort, strings.Join(names, "/"))
+	}
+	if q.Limit < 0 || q.Offset < 0 {
+		return nil, "", fmt.Errorf("%w: limit/offsetは0以上で指定してください", ErrInvalidQuery)
+	}
+	if q.Cursor != "" && q.Offset > 0 {
+		return nil, "", fmt.Errorf("%w: cursorとoffsetは併用できません", ErrInvalidQuery)
+	}
+	if err := checkDates(q.From, q.To); err != nil {
+		return nil, "", err
+	}
+
+	var where []string
+	var args []interface{}
+	if q.From != "" {
+		where = append(where, spec.dateColumn+" >= ?")
+		args = append(args, q.From)
+	}
+	if q.To != "" {
+		where = append(where, spec.dateColumn+" <= ?")
+		args = append(args, q.To)
+	}
+	if q.Region != "" {
+		if spec.regionColumn == "" {
+			return nil, "", fmt.Errorf("%w: %sは地域で絞り込めません", ErrInvalidQuery, spec.name)
+		}
+		where = append(where, spec.regionColumn+" = ?")
+		args = append(args, q.Region)
+	}
+	if q.Source != "" {
+		if spec.sourceColumn == "" {
+			return nil, "", fmt.Errorf("%w: %sはデータソースで絞り込めません", ErrInvalidQuery, spec.name)
+		}
+		where = append(where, spec.sourceColumn+" = ?")
+		args = append(args, q.Source)
+	}
+
+	op, dir := ">", "ASC"
+	if desc {
+		op, dir = "<", "DESC"
+	}
+	if q.Cursor != "" {
+		c, err := decodeListCursor(q.Cursor)
+		if err != nil {
+			return nil, "", err
+		}
+		if c.Sort != sortName {
+			return nil, "", fmt.Errorf("%w: カーソルの並び順（%s）が指定と異なります: %s", ErrInvalidQuery, c.Sort, sortName)
+		}
+		where = append(where, fmt.Sprintf("(%s, id) %s (?, ?)", key.column, op))
+		args = append(args, c.Value, c.ID)
+	}
+
+	query := spec.query
+	if len(where) > 0 {
+		query += " WHERE " + strings.Join(where, " AND ")
+	}
+	query += fmt.Sprintf(" ORDER BY %s %s, id %s", key.column, dir, dir)
+	if q.Limit > 0 {
+		query += " LIMIT ?"
+		args = append(args, q.Limit)
+	} else if q.Offset > 0 {
+		query += " " + d.noLimit()
+	}
+	if q.Offset > 0 {
+		query += " OFFSET ?"
+		args = append(args, q.Offset)
+	}
+
+	rows, err := db.Query(d.bind(query), args...)
+	if err != nil {
+		return nil, "", fmt.Errorf("%s取得エラー: %w", spec.name, err)
+	}
+	defer rows.Close()
+
+	items, err := spec.scan(rows)
+	if err != nil {
+		return nil, "", fmt.Errorf("%s取得エラー: %w", spec.name, err)
+	}
+
+	var next string
+	if q.Limit > 0 && len(items) == q.Limit {
+		last := items[len(items)-1]
+		next = listCursor{Sort: sortName, Value: key.value(last), ID: spec.id(last)}.encode()
+	}
+	return items, next, nil
+}
+
+var gasPriceList = listSpec[*model.GasPrice]{
+	name: "ガソリン価格",
+	query: `SELECT id, date, regular_price, premium_price, diesel_price,
+		region, source, created_at, updated_at FROM gas_prices`,
+	dateColumn:   "date",
+	regionColumn: "region",
+	sourceColumn: "source",
+	sorts: map[string]sortKey[*model.GasPrice]{
+		"date":    {"date", func(p *model.GasPrice) interface{} { return p.Date }},
+		"regular": {"regular_price", func(p *model.GasPrice) interface{} { return p.RegularPrice }},
+		"premium": {"premium_price", func(p *model.GasPrice) interface{} { return p.PremiumPrice }},
+		"diesel":  {"diesel_price", func(p *model.GasPrice) interface{} { return p.DieselPrice }},
+	},
+	id:   func(p *model.GasPrice) string { return p.ID },
+	scan: scanGasPrices,
+}
+
+var exchangeRateList = listSpec[*model.ExchangeRate]{
+	name: "為替レート",
+	query: `SELECT id, date, usd_jpy, eur_jpy, gbp_jpy, cny_jpy, source, created_at, updated_at
+		FROM exchange_rates`,
+	dateColumn:   "date",
+	sourceColumn: "source",
+	sorts: map[string]sortKey[*model.ExchangeRate]{
+		"date":    {"date", func(r *model.ExchangeRate) interface{} { return r.Date }},
+		"usd_jpy": {"usd_jpy", func(r *model.ExchangeRate) interface{} { return r.USDJPY }},
+		"eur_jpy": {"eur_jpy", func(r *model.ExchangeRate) interface{} { return r.EURJPY }},
+	},
+	id:   func(r *model.ExchangeRate) string { return r.ID },
+	scan: scanExchangeRates,
+}
+
+var newsList = listSpec[*detect.AnalyzedNews]{
+	name:         "ニュース",
+	query:        `SELECT ` + newsColumns + ` FROM news_summaries`,
+	dateColumn:   "substr(date, 1, 10)",
+	sourceColumn: "source",
+	sorts: map[string]sortKey[*detect.AnalyzedNews]{
+		"date":      {"date", func(n *detect.AnalyzedNews) interface{} { return n.Date }},
+		"created":   {"created_at", func(n *detect.AnalyzedNews) interface{} { return n.CreatedAt }},
+		"impact":    {"impact_score", func(n *detect.AnalyzedNews) interface{} { return n.ImpactScore }},
+		"sentiment": {"sentiment_score", func(n *detect.AnalyzedNews) interface{} { return n.SentimentScore }},
+	},
+	id:   func(n *detect.AnalyzedNews) string { return n.ID },
+	scan: scanNews,
+}
+
+var priceChangeList = listSpec[*model.PriceChangeRecord]{
+	name:         "変動記録",
+	query:        `SELECT ` + priceChangeColumns + ` FROM price_change`,
+	dateColumn:   "date_new",
+	regionColumn: "region",
+	sorts: map[string]sortKey[*model.PriceChangeRecord]{
+		"date": {"date_new", func(c *model.PriceChangeRecord) interface{} { return c.DateNew }},
+		"pct":  {"pct_change", func(c *model.PriceChangeRecord) interface{} { return c.PctChange }},
+	},
+	id:   func(c *model.PriceChangeRecord) string { return strconv.FormatInt(c.ID, 10) },
+	scan: scanPriceChanges,
+}
+
+// ListGasPrices 条件に合うガソリン価格と次ページのカーソル（続きがなければ空）
+func (s *SQLiteClient) ListGasPrices(q ListQuery) ([]*model.GasPrice, string, error) {
+	return list(s.db, dialectSQLite, gasPriceList, q)
+}
+
+// ListExchangeRates 条件に合う為替レートと次ページのカーソル
+func (s *SQLiteClient) ListExchangeRates(q ListQuery) ([]*model.ExchangeRate, string, error) {
+	return list(s.db, dialectSQLite, exchangeRateList, q)
+}
+
+// ListNews 条件に合うニュースと次ページのカーソル
+func (s *SQLiteClient) ListNews(q ListQuery) ([]*detect.AnalyzedNews, string, error) {
+	return list(s.db, dialectSQLite, newsList, q)
+}
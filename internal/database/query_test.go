@@ -0,0 +1,26 @@
+package database
+
+import "testing"
+
+func TestDialectBind(t *testing.T) {
+	query := "SELECT * FROM gas_prices WHERE date >= ? AND region = ? AND (date, id) < (?, ?) LIMIT ?"
+	if got := dialectSQLite.bind(query); got != query {
+		t.Errorf("SQLite: %s", got)
+	}
+	want := "SELECT * FROM gas_prices WHERE date >= $1 AND region = $2 AND (date, id) < ($3, $4) LIMIT $5"
+	if got := dialectPostgres.bind(query); got != want {
+		t.Errorf("PostgreSQL: %s, want %s", got, want)
+	}
+}
+
+func TestListCursorKeepsNumericTypes(t *testing.T) {
+	for _, value := range []interface{}{int64(1736000000), 2.5, "2025-01-06"} {
+		c, err := decodeListCursor(listCursor{Sort: "-date", Value: value, ID: "42"}.encode())
+		if err != nil {
+			t.Fatal(err)
+		}
+		if c.Value != value || c.ID != "42" {
+			t.Errorf("カーソル %v (%T) を戻すと %v (%T)", value, value, c.Value, c.Value)
+		}
+	}
+}
@@ -0,0 +1,507 @@
+package database
+
+import (
+	"database/sql"
+	"fmt"
+	"log"
+	"sort"
+	"strings"
+	"time"
+
+	model "gasinsight/internal/model"
+	"gasinsight/internal/timeseries"
+)
+
+// ガソリン価格・為替レートは改定履歴（*_revisions）に追記し、gas_prices / exchange_ratesには
+// 最新の改定を現在の値として反映する（作成日時は最初に保存した日時のまま）
+// 現在の値は日付・地域・データソース（為替レートは日付・データソース）ごとに1行で、
+// IDはmodel.GasPriceID / model.ExchangeRateIDで決まる
+
+// RevisionQuery 改定履歴・過去時点の値の取得条件
+type RevisionQuery struct {
+	From     string    // 開始日（YYYY-MM-DD、両端含む）
+	To       string    // 終了日
+	Region   string    // 地域（ガソリン価格のみ）
+	Currency string    // 通貨（為替レートのみ。USD/EUR/GBP/CNY）
+	Source   string    // データソース
+	AsOf     time.Time // この日時までに記録した値（ゼロなら現在）
+}
+
+// revisionCurrency 為替レートの通貨と列・値の対応
+type revisionCurrency struct {
+	code   string
+	column string
+	rate   func(r *model.ExchangeRate) *float64
+}
+
+var revisionCurrencies = []revisionCurrency{
+	{"USD", "usd_jpy", func(r *model.ExchangeRate) *float64 { return &r.USDJPY }},
+	{"EUR", "eur_jpy", func(r *model.ExchangeRate) *float64 { return &r.EURJPY }},
+	{"GBP", "gbp_jpy", func(r *model.ExchangeRate) *float64 { return &r.GBPJPY }},
+	{"CNY", "cny_jpy", func(r *model.ExchangeRate) *float64 { return &r.CNYJPY }},
+}
+
+// revisionTablesSQLite SQLiteの改定履歴テーブル
+const revisionTablesSQLite = `
+	CREATE TABLE IF NOT EXISTS gas_price_revisions (
+		id INTEGER PRIMARY KEY AUTOINCREMENT,
+		date TEXT NOT NULL,
+		region TEXT NOT NULL,
+		source TEXT NOT NULL,
+		regular_price REAL NOT NULL,
+		premium_price REAL NOT NULL,
+		diesel_price REAL NOT NULL,
+		recorded_at INTEGER NOT NULL
+	);
+	CREATE INDEX IF NOT EXISTS idx_gas_price_revisions_key ON gas_price_revisions(date, region, source, recorded_at);
+
+	CREATE TABLE IF NOT EXISTS exchange_rate_revisions (
+		id INTEGER PRIMARY KEY AUTOINCREMENT,
+		date TEXT NOT NULL,
+		currency TEXT NOT NULL,
+		source TEXT NOT NULL,
+		rate REAL NOT NULL,
+		recorded_at INTEGER NOT NULL
+	);
+	CREATE INDEX IF NOT EXISTS idx_exchange_rate_revisions_key ON exchange_rate_revisions(date, currency, source, recorded_at);
+`
+
+// revisionTablesPostgres PostgreSQLの改定履歴テーブル
+const revisionTablesPostgres = `
+	CREATE TABLE IF NOT EXISTS gas_price_revisions (
+		id BIGSERIAL PRIMARY KEY,
+		date TEXT NOT NULL,
+		region TEXT NOT NULL,
+		source TEXT NOT NULL,
+		regular_price DOUBLE PRECISION NOT NULL,
+		premium_price DOUBLE PRECISION NOT NULL,
+		diesel_price DOUBLE PRECISION NOT NULL,
+		recorded_at BIGINT NOT NULL
+	);
+	CREATE INDEX IF NOT EXISTS idx_gas_price_revisions_key ON gas_price_revisions(date, region, source, recorded_at);
+
+	CREATE TABLE IF NOT EXISTS exchange_rate_revisions (
+		id BIGSERIAL PRIMARY KEY,
+		date TEXT NOT NULL,
+		currency TEXT NOT NULL,
+		source TEXT NOT NULL,
+		rate DOUBLE PRECISION NOT NULL,
+		recorded_at BIGINT NOT NULL
+	);
+	CREATE INDEX IF NOT EXISTS idx_exchange_rate_revisions_key ON exchange_rate_revisions(date, currency, source, recorded_at);
+`
+
+// CreateRevisionTables 改定履歴テーブルを作成し、履歴のない既存の行を最初の改定として登録する
+func (s *SQLiteClient) CreateRevisionTables() error {
+	if _, err := s.db.Exec(revisionTablesSQLite); err != nil {
+		return fmt.Errorf("改定履歴テーブル作成エラー: %w", err)
+	}
+	if err := migrateCurrentKeys(s.db); err != nil {
+		return err
+	}
+	return backfillRevisions(s.db, dialectSQLite)
+}
+
+// recordedAtColumn 既存の行を記録した日時（更新日時、なければ作成日時、どちらもなければ現在時刻）
+const recordedAtColumn = `CASE WHEN updated_at > 0 THEN updated_at WHEN created_at > 0 THEN created_at ELSE ? END`
+
+// migrateCurrentKeys 日付だけをIDにしていた既存の行を、日付・地域・データソースから決まるIDに改める
+// （以前は同じ日付の別の地域・データソースの保存で上書きされていた）
+func migrateCurrentKeys(db dbtx) error {
+	res, err := db.Exec(`UPDATE gas_prices SET id = date || '_' || region || '_' || source
+		WHERE id <> date || '_' || region || '_' || source`)
+	if err != nil {
+		return fmt.Errorf("ガソリン価格のID変更エラー: %w", err)
+	}
+	if n, _ := res.RowsAffected(); n > 0 {
+		log.Printf("✅ ガソリン価格のIDを日付・地域・データソースの形式に変更しました: %d件", n)
+	}
+	res, err = db.Exec(`UPDATE exchange_rates SET id = date || '_' || source WHERE id <> date || '_' || source`)
+	if err != nil {
+		return fmt.Errorf("為替レートのID変更エラー: %w", err)
+	}
+	if n, _ := res.RowsAffected(); n > 0 {
+		log.Printf("✅ 為替レートのIDを日付・データソースの形式に変更しました: %d件", n)
+	}
+	return nil
+}
+
+// backfillRevisions 改定履歴がない行を、最初の改定として登録する
+func backfillRevisions(db dbtx, d dialect) error {
+	now := time.Now().Unix()
+	res, err := db.Exec(d.bind(`
+		INSERT INTO gas_price_revisions (date, region, source, regular_price, premium_price, diesel_price, recorded_at)
+		SELECT date, region, source, regular_price, premium_price, diesel_price, `+recordedAtColumn+` FROM gas_prices g
+		WHERE NOT EXISTS (SELECT 1 FROM gas_price_revisions r
+			WHERE r.date = g.date AND r.region = g.region AND r.source = g.source)`), now)
+	if err != nil {
+		return fmt.Errorf("ガソリン価格の改定履歴登録エラー: %w", err)
+	}
+	if n, _ := res.RowsAffected(); n > 0 {
+		log.Printf("✅ ガソリン価格の改定履歴を登録しました: %d件", n)
+	}
+
+	for _, c := range revisionCurrencies {
+		res, err := db.Exec(d.bind(`
+			INSERT INTO exchange_rate_revisions (date, currency, source, rate, recorded_at)
+			SELECT date, ?, source, `+c.column+`, `+recordedAtColumn+` FROM exchange_rates e
+			WHERE `+c.column+` > 0 AND NOT EXISTS (SELECT 1 FROM exchange_rate_revisions r
+				WHERE r.date = e.date AND r.currency = ? AND r.source = e.source)`), c.code, now, c.code)
+		if err != nil {
+			return fmt.Errorf("為替レートの改定履歴登録エラー: %w", err)
+		}
+		if n, _ := res.RowsAffected(); n > 0 {
+			log.Printf("✅ 為替レート（%s）の改定履歴を登録しました: %d件", c.code, n)
+		}
+	}
+	return nil
+}
+
+// saveGasPrice 値が前回の改定と異なれば改定を追記し、現在の値を更新する
+// IDは日付・地域・データソースから決め直す（同じ日付の別の地域・データソースを上書きしない）
+// 新規の保存か値が変わった場合はtrueを返す
+func saveGasPrice(db dbtx, d dialect, price *model.GasPrice) (bool, error) {
+	price.ID = model.GasPriceID(price.Date, price.Region, price.Source)
+	var prev model.GasPriceRevision
+	err := db.QueryRow(d.bind(`SELECT regular_price, premium_price, diesel_price FROM gas_price_revisions
+		WHERE date = ? AND region = ? AND source = ? ORDER BY recorded_at DESC, id DESC LIMIT 1`),
+		price.Date, price.Region, price.Source).Scan(&prev.RegularPrice, &prev.PremiumPrice, &prev.DieselPrice)
+	if err != nil && err != sql.ErrNoRows {
+		return false, fmt.Errorf("改定履歴取得エラー: %w", err)
+	}
+	found := err == nil
+	changed := prev.RegularPrice != price.RegularPrice || prev.PremiumPrice != price.PremiumPrice || prev.DieselPrice != price.DieselPrice
+	if !found || changed {
+		if _, err := db.Exec(d.bind(`INSERT INTO gas_price_revisions
+			(date, region, source, regular_price, premium_price, diesel_price, recorded_at)
+			VALUES (?, ?, ?, ?, ?, ?, ?)`),
+			price.Date, price.Region, price.Source, price.RegularPrice, price.PremiumPrice, price.DieselPrice, time.Now().Unix()); err != nil {
+			return false, fmt.Errorf("改定履歴保存エラー: %w", err)
+		}
+		if found {
+			log.Printf("🔁 ガソリン価格の改定を記録: %s %s（%s）レギュラー %.2f → %.2f円",
+				price.Date, price.Region, price.Source, prev.RegularPrice, price.RegularPrice)
+		}
+	}
+
+	_, err = db.Exec(d.bind(`INSERT INTO gas_prices (`+gasPriceColumns+`)
+		VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?)
+		ON CONFLICT (id) DO UPDATE SET
+			date = excluded.date, regular_price = excluded.regular_price, premium_price = excluded.premium_price,
+			diesel_price = excluded.diesel_price, region = excluded.region, source = excluded.source,
+			updated_at = excluded.updated_at`),
+		price.ID, price.Date, price.RegularPrice, price.PremiumPrice, price.DieselPrice, price.Region, price.Source,
+		price.CreatedAt, price.UpdatedAt)
+	if err != nil {
+		return false, fmt.Errorf("データ保存エラー: %w", err)
+	}
+	return !found || changed, nil
+}
+
+// saveExchangeRate 通貨ごとに値が前回の改定と異なれば改定を追記し、現在の値を更新する（0の通貨は記録しない）
+// IDは日付・データソースから決め直す。いずれかの通貨の改定を追記した場合はtrueを返す
+func saveExchangeRate(db dbtx, d dialect, rate *model.ExchangeRate) (bool, error) {
+	rate.ID = model.ExchangeRateID(rate.Date, rate.Source)
+	changed := false
+	for _, c := range revisionCurrencies {
+		value := *c.rate(rate)
+		if value <= 0 {
+			continue
+		}
+		var prev float64
+		err := db.QueryRow(d.bind(`SELECT rate FROM exchange_rate_revisions
+			WHERE date = ? AND currency = ? AND source = ? ORDER BY recorded_at DESC, id DESC LIMIT 1`),
+			rate.Date, c.code, rate.Source).Scan(&prev)
+		if err != nil && err != sql.ErrNoRows {
+			return false, fmt.Errorf("改定履歴取得エラー: %w", err)
+		}
+		found := err == nil
+		if found && prev == value {
+			continue
+		}
+		if _, err := db.Exec(d.bind(`INSERT INTO exchange_rate_revisions (date, currency, source, rate, recorded_at)
+			VALUES (?, ?, ?, ?, ?)`), rate.Date, c.code, rate.Source, value, time.Now().Unix()); err != nil {
+			return false, fmt.Errorf("改定履歴保存エラー: %w", err)
+		}
+		changed = true
+		if found {
+			log.Printf("🔁 為替レートの改定を記録: %s %s/JPY（%s）%.4f → %.4f", rate.Date, c.code, rate.Source, prev, value)
+		}
+	}
+
+	_, err := db.Exec(d.bind(`INSERT INTO exchange_rates (`+exchangeRateColumns+`)
+		VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?)
+		ON CONFLICT (id) DO UPDATE SET
+			date = excluded.date, usd_jpy = excluded.usd_jpy, eur_jpy = excluded.eur_jpy,
+			gbp_jpy = excluded.gbp_jpy, cny_jpy = excluded.cny_jpy, source = excluded.source,
+			updated_at = excluded.updated_at`),
+		rate.ID, rate.Date, rate.USDJPY, rate.EURJPY, rate.GBPJPY, rate.CNYJPY, rate.Source, rate.CreatedAt, rate.UpdatedAt)
+	if err != nil {
+		return false, fmt.Errorf("為替レート保存エラー: %w", err)
+	}
+	return changed, nil
+}
+
+// revisionFilter 改定履歴の絞り込み条件（列名は別名rの付いた改定履歴テーブル）
+func revisionFilter(q RevisionQuery, keyColumn, key string) ([]string, []interface{}, error) {
+	if err := checkDates(q.From, q.To); err != nil {
+		return nil, nil, err
+	}
+	var where []string
+	var args []interface{}
+	if q.From != "" {
+		where = append(where, "r.date >= ?")
+		args = append(args, q.From)
+	}
+	if q.To != "" {
+		where = append(where, "r.date <= ?")
+		args = append(args, q.To)
+	}
+	if key != "" {
+		where = append(where, "r."+keyColumn+" = ?")
+		args = append(args, key)
+	}
+	if q.Source != "" {
+		where = append(where, "r.source = ?")
+		args = append(args, q.Source)
+	}
+	return where, args, nil
+}
+
+// asOfFilter 各キーで指定日時までの最新の改定だけに絞り込む条件
+func asOfFilter(table, keyColumn string, asOf time.Time) (string, []interface{}) {
+	if asOf.IsZero() {
+		asOf = time.Now()
+	}
+	return `r.recorded_at <= ? AND r.id = (SELECT r2.id FROM ` + table + ` r2
+		WHERE r2.date = r.date AND r2.` + keyColumn + ` = r.` + keyColumn + ` AND r2.source = r.source AND r2.recorded_at <= ?
+		ORDER BY r2.recorded_at DESC, r2.id DESC LIMIT 1)`, []interface{}{asOf.Unix(), asOf.Unix()}
+}
+
+func gasPriceRevisionFilter(q RevisionQuery) ([]string, []interface{}, error) {
+	if q.Currency != "" {
+		return nil, nil, fmt.Errorf("%w: ガソリン価格は通貨で絞り込めません", ErrInvalidQuery)
+	}
+	return revisionFilter(q, "region", q.Region)
+}
+
+func exchangeRateRevisionFilter(q RevisionQuery) ([]string, []interface{}, error) {
+	if q.Region != "" {
+		return nil, nil, fmt.Errorf("%w: 為替レートは地域で絞り込めません", ErrInvalidQuery)
+	}
+	if q.Currency != "" && !contains(AggregateCurrencies, q.Currency) {
+		return nil, nil, fmt.Errorf("%w: 通貨 %s（%s）", ErrInvalidQuery, q.Currency, strings.Join(AggregateCurrencies, "/"))
+	}
+	return revisionFilter(q, "currency", q.Currency)
+}
+
+const gasPriceRevisionColumns = `r.id, r.date, r.region, r.source, r.regular_price, r.premium_price, r.diesel_price, r.recorded_at`
+
+func queryGasPriceRevisions(db dbtx, d dialect, where []string, args []interface{}) ([]*model.GasPriceRevision, error) {
+	query := `SELECT ` + gasPriceRevisionColumns + ` FROM gas_price_revisions r`
+	if len(where) > 0 {
+		query += ` WHERE ` + strings.Join(where, " AND ")
+	}
+	rows, err := db.Query(d.bind(query+` ORDER BY r.date, r.region, r.source, r.recorded_at, r.id`), args...)
+	if err != nil {
+		return nil, fmt.Errorf("改定履歴取得エラー: %w", err)
+	}
+	defer rows.Close()
+
+	var revisions []*model.GasPriceRevision
+	for rows.Next() {
+		var r model.GasPriceRevision
+		if err := rows.Scan(&r.ID, &r.Date, &r.Region, &r.Source, &r.RegularPrice, &r.PremiumPrice, &r.DieselPrice, &r.RecordedAt); err != nil {
+			return nil, err
+		}
+		revisions = append(revisions, &r)
+	}
+	return revisions, rows.Err()
+}
+
+func queryExchangeRateRevisions(db dbtx, d dialect, where []string, args []interface{}) ([]*model.ExchangeRateRevision, error) {
+	query := `SELECT r.id, r.date, r.currency, r.source, r.rate, r.recorded_at FROM exchange_rate_revisions r`
+	if len(where) > 0 {
+		query += ` WHERE ` + strings.Join(where, " AND ")
+	}
+	rows, err := db.Query(d.bind(query+` ORDER BY r.date, r.source, r.currency, r.recorded_at, r.id`), args...)
+	if err != nil {
+		return nil, fmt.Errorf("改定履歴取得エラー: %w", err)
+	}
+	defer rows.Close()
+
+	var revisions []*model.ExchangeRateRevision
+	for rows.Next() {
+		var r model.ExchangeRateRevision
+		if err := rows.Scan(&r.ID, &r.Date, &r.Currency, &r.Source, &r.Rate, &r.RecordedAt); err != nil {
+			return nil, err
+		}
+		revisions = append(revisions, &r)
+	}
+	return revisions, rows.Err()
+}
+
+// gasPriceRevisions 改定履歴を日付・地域・データソース・記録日時の順に返す（AsOfを指定した場合はその日時までの改定）
+func gasPriceRevisions(db dbtx, d dialect, q RevisionQuery) ([]*model.GasPriceRevision, error) {
+	where, args, err := gasPriceRevisionFilter(q)
+	if err != nil {
+		return nil, err
+	}
+	if !q.AsOf.IsZero() {
+		where = append(where, "r.recorded_at <= ?")
+		args = append(args, q.AsOf.Unix())
+	}
+	return queryGasPriceRevisions(db, d, where, args)
+}
+
+// gasPricesAsOf AsOfの時点で記録されていた値を、日付の降順で返す
+// 作成日時はその日付・地域・データソースの最初の改定、更新日時は採用した改定の記録日時
+func gasPricesAsOf(db dbtx, d dialect, q RevisionQuery) ([]*model.GasPrice, error) {
+	where, args, err := gasPriceRevisionFilter(q)
+	if err != nil {
+		return nil, err
+	}
+	cond, condArgs := asOfFilter("gas_price_revisions", "region", q.AsOf)
+	revisions, err := queryGasPriceRevisions(db, d, append(where, cond), append(args, condArgs...))
+	if err != nil {
+		return nil, err
+	}
+	firsts, err := firstRecorded(db, d, "gas_price_revisions", "region")
+	if err != nil {
+		return nil, err
+	}
+
+	prices := make([]*model.GasPrice, 0, len(revisions))
+	for _, r := range revisions {
+		prices = append(prices, &model.GasPrice{
+			ID: model.GasPriceID(r.Date, r.Region, r.Source), Date: r.Date, RegularPrice: r.RegularPrice, PremiumPrice: r.PremiumPrice, DieselPrice: r.DieselPrice,
+			Region: r.Region, Source: r.Source,
+			CreatedAt: firsts[r.Date+"\x00"+r.Region+"\x00"+r.Source], UpdatedAt: r.RecordedAt,
+		})
+	}
+	sort.SliceStable(prices, func(i, j int) bool { return prices[i].Date > prices[j].Date })
+	return prices, nil
+}
+
+// exchangeRateRevisions 改定履歴を日付・データソース・通貨・記録日時の順に返す
+func exchangeRateRevisions(db dbtx, d dialect, q RevisionQuery) ([]*model.ExchangeRateRevision, error) {
+	where, args, err := exchangeRateRevisionFilter(q)
+	if err != nil {
+		return nil, err
+	}
+	if !q.AsOf.IsZero() {
+		where = append(where, "r.recorded_at <= ?")
+		args = append(args, q.AsOf.Unix())
+	}
+	return queryExchangeRateRevisions(db, d, where, args)
+}
+
+// exchangeRatesAsOf AsOfの時点で記録されていた値を、日付・データソースごとにまとめて日付の降順で返す
+// その時点で記録のない通貨は0
+func exchangeRatesAsOf(db dbtx, d dialect, q RevisionQuery) ([]*model.ExchangeRate, error) {
+	where, args, err := exchangeRateRevisionFilter(q)
+	if err != nil {
+		return nil, err
+	}
+	cond, condArgs := asOfFilter("exchange_rate_revisions", "currency", q.AsOf)
+	revisions, err := queryExchangeRateRevisions(db, d, append(where, cond), append(args, condArgs...))
+	if err != nil {
+		return nil, err
+	}
+
+	var rates []*model.ExchangeRate
+	byKey := map[string]*model.ExchangeRate{}
+	for _, r := range revisions {
+		key := r.Date + "\x00" + r.Source
+		rate, ok := byKey[key]
+		if !ok {
+			rate = &model.ExchangeRate{ID: model.ExchangeRateID(r.Date, r.Source), Date: r.Date, Source: r.Source, CreatedAt: r.RecordedAt}
+			byKey[key] = rate
+			rates = append(rates, rate)
+		}
+		for _, c := range revisionCurrencies {
+			if c.code == r.Currency {
+				*c.rate(rate) = r.Rate
+			}
+		}
+		rate.CreatedAt = min(rate.CreatedAt, r.RecordedAt)
+		rate.UpdatedAt = max(rate.UpdatedAt, r.RecordedAt)
+	}
+	sort.SliceStable(rates, func(i, j int) bool { return rates[i].Date > rates[j].Date })
+	return rates, nil
+}
+
+// firstRecorded キー（日付・keyColumn・データソース）ごとの最初の記録日時
+func firstRecorded(db dbtx, d dialect, table, keyColumn string) (map[string]int64, error) {
+	rows, err := db.Query(d.bind(`SELECT date, ` + keyColumn + `, source, MIN(recorded_at) FROM ` + table +
+		` GROUP BY date, ` + keyColumn + `, source`))
+	if err != nil {
+		return nil, fmt.Errorf("改定履歴取得エラー: %w", err)
+	}
+	defer rows.Close()
+
+	firsts := map[string]int64{}
+	for rows.Next() {
+		var date, key, source string
+		var recordedAt int64
+		if err := rows.Scan(&date, &key, &source, &recordedAt); err != nil {
+			return nil, err
+		}
+		firsts[date+"\x00"+key+"\x00"+source] = recordedAt
+	}
+	return firsts, rows.Err()
+}
+
+// ParseAsOf 過去時点の日時を読み取る（RFC3339、"YYYY-MM-DD HH:MM"（日本時間）、または日付のみならその日の終わり）
+func ParseAsOf(s string) (time.Time, error) {
+	if t, err := time.Parse(time.RFC3339, s); err == nil {
+		return t, nil
+	}
+	if t, err := time.ParseInLocation("2006-01-02 15:04", s, timeseries.JST); err == nil {
+		return t, nil
+	}
+	if t, err := time.ParseInLocation("2006-01-02", s, timeseries.JST); err == nil {
+		return t.AddDate(0, 0, 1).Add(-time.Second), nil
+	}
+	return time.Time{}, fmt.Errorf("%w: 日時はRFC3339、YYYY-MM-DD HH:MM、YYYY-MM-DDのいずれかで指定してください: %s", ErrInvalidQuery, s)
+}
+
+// GasPriceRevisions ガソリン価格の改定履歴
+func (s *SQLiteClient) GasPriceRevisions(q RevisionQuery) ([]*model.GasPriceRevision, error) {
+	return gasPriceRevisions(s.db, dialectSQLite, q)
+}
+
+// GasPricesAsOf 指定日時の時点で記録されていたガソリン価格
+func (s *SQLiteClient) GasPricesAsOf(q RevisionQuery) ([]*model.GasPrice, error) {
+	return gasPricesAsOf(s.db, dialectSQLite, q)
+}
+
+// ExchangeRateRevisions 為替レートの改定履歴
+func (s *SQLiteClient) ExchangeRateRevisions(q RevisionQuery) ([]*model.ExchangeRateRevision, error) {
+	return exchangeRateRevisions(s.db, dialectSQLite, q)
+}
+
+// ExchangeRatesAsOf 指定日時の時点で記録されていた為替レート
+func (s *SQLiteClient) ExchangeRatesAsOf(q RevisionQuery) ([]*model.ExchangeRate, error) {
+	return exchangeRatesAsOf(s.db, dialectSQLite, q)
+}
+
+// GasPriceRevisions ガソリン価格の改定履歴
+func (p *PostgresClient) GasPriceRevisions(q RevisionQuery) ([]*model.GasPriceRevision, error) {
+	return gasPriceRevisions(p.db, dialectPostgres, q)
+}
+
+// GasPricesAsOf 指定日時の時点で記録されていたガソリン価格
+func (p *PostgresClient) GasPricesAsOf(q RevisionQuery) ([]*model.GasPrice, error) {
+	return gasPricesAsOf(p.db, dialectPostgres, q)
+}
+
+// ExchangeRateRevisions 為替レートの改定履歴
+func (p *PostgresClient) ExchangeRateRevisions(q RevisionQuery) ([]*model.ExchangeRateRevision, error) {
+	return exchangeRateRevisions(p.db, dialectPostgres, q)
+}
+
+// ExchangeRatesAsOf 指定日時の時点で記録されていた為替レート
+func (p *PostgresClient) ExchangeRatesAsOf(q RevisionQuery) ([]*model.ExchangeRate, error) {
+	return exchangeRatesAsOf(p.db, dialectPostgres, q)
+}
@@ -0,0 +1,56 @@
+package database
+
+import (
+	"path/filepath"
+	"testing"
+
+	models "gasinsight/internal/model"
+)
+
+func TestMigrateCurrentKeys(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "legacy.db")
+	s, err := NewSQLiteClient(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	// 日付だけをIDにしていた頃の行
+	if _, err := s.db.Exec(`INSERT INTO gas_prices (id, date, regular_price, premium_price, diesel_price, region, source, created_at, updated_at)
+		VALUES ('2025-01-06', '2025-01-06', 175, 186, 158, '全国平均', 'e-nenpi', 1736000000, 1736000000)`); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := s.db.Exec(`INSERT INTO exchange_rates (id, date, usd_jpy, eur_jpy, gbp_jpy, cny_jpy, source, created_at, updated_at)
+		VALUES ('2025-01-06', '2025-01-06', 157.2, 162.8, 196.1, 21.5, 'exchangerate-api.com', 1736000000, 1736000000)`); err != nil {
+		t.Fatal(err)
+	}
+	s.Close()
+
+	s, err = NewSQLiteClient(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer s.Close()
+
+	// 同じ日付の別の地域を保存しても、移行した行は上書きされない
+	if _, err := s.SaveGasPrice(models.NewGasPrice("2025-01-06", "東京都", 178, 189, 161)); err != nil {
+		t.Fatal(err)
+	}
+	prices, err := s.GetAllGasPrices()
+	if err != nil {
+		t.Fatal(err)
+	}
+	ids := map[string]float64{}
+	for _, p := range prices {
+		ids[p.ID] = p.RegularPrice
+	}
+	if len(ids) != 2 || ids[models.GasPriceID("2025-01-06", "全国平均", "e-nenpi")] != 175 || ids[models.GasPriceID("2025-01-06", "東京都", "e-nenpi")] != 178 {
+		t.Errorf("ガソリン価格 = %v", ids)
+	}
+
+	rates, err := s.GetAllExchangeRates()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(rates) != 1 || rates[0].ID != models.ExchangeRateID("2025-01-06", "exchangerate-api.com") {
+		t.Errorf("為替レート = %+v", rates)
+	}
+}
@@ -1,6 +1,7 @@
 package database
 
 import (
+	"context"
 	"database/sql"
 	"fmt"
 	"gasinsight/internal/detect"
@@ -15,7 +16,16 @@ import (
 )
 
 type SQLiteClient struct {
-	db *sql.DB
+	conn *sql.DB // トランザクション内のクライアントではnil
+	db   dbtx
+	fts  bool // ニュースの全文検索（FTS5）が使えるか
+}
+
+// dbtx *sql.DBと*sql.Txに共通の操作
+type dbtx interface {
+	Exec(query string, args ...interface{}) (sql.Result, error)
+	Query(query string, args ...interface{}) (*sql.Rows, error)
+	QueryRow(query string, args ...interface{}) *sql.Row
 }
 
 func (c *SQLiteClient) Exec(query string, args ...interface{}) (sql.Result, error) {
@@ -26,11 +36,49 @@ func (db *SQLiteClient) SaveNews(news *detect.AnalyzedNews) error {
 	now := time.Now().Unix()
 	id := uuid.New().String()
 	_, err := db.Exec(`
-        INSERT INTO news_summaries (id, date, title, summary, sentiment, url, created_at, updated_at)
-        VALUES (?, ?, ?, ?, ?, ?, ?, ?)`,
-		id, news.Date, news.Title, news.Summary, news.Sentiment, news.URL, now, now,
+        INSERT INTO news_summaries (id, date, title, summary, sentiment, sentiment_score, impact_score, direction, confidence,
+            url, source, language, model, prompt_version, created_at, updated_at)
+        VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)`,
+		id, news.Date, news.Title, news.Summary, news.Sentiment, news.SentimentScore, news.ImpactScore, news.Direction, news.Confidence,
+		news.URL, news.Source, news.Language, news.Model, news.PromptVersion, now, now,
 	)
-	return err
+	if err != nil {
+		return err
+	}
+	news.ID, news.CreatedAt = id, now
+	return nil
+}
+
+// UpsertNews IDを指定してニュースを追加・更新（IDが空なら新規に保存）
+func (db *SQLiteClient) UpsertNews(news *detect.AnalyzedNews) error {
+	if news.ID == "" {
+		return db.SaveNews(news)
+	}
+	return upsertNews(db.db, dialectSQLite, news)
+}
+
+// upsertNews IDが同じニュースは上書きする（作成日時は指定がなければ現在時刻）
+func upsertNews(db dbtx, d dialect, news *detect.AnalyzedNews) error {
+	now := time.Now().Unix()
+	if news.CreatedAt == 0 {
+		news.CreatedAt = now
+	}
+	_, err := db.Exec(d.bind(`
+        INSERT INTO news_summaries (`+newsColumns+`)
+        VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)
+        ON CONFLICT (id) DO UPDATE SET
+            date = excluded.date, title = excluded.title, summary = excluded.summary, sentiment = excluded.sentiment,
+            sentiment_score = excluded.sentiment_score, impact_score = excluded.impact_score, direction = excluded.direction,
+            confidence = excluded.confidence, url = excluded.url, source = excluded.source, language = excluded.language,
+            model = excluded.model, prompt_version = excluded.prompt_version,
+            created_at = excluded.created_at, updated_at = excluded.updated_at`),
+		news.ID, news.Date, news.Title, news.Summary, news.Sentiment, news.SentimentScore, news.ImpactScore, news.Direction, news.Confidence,
+		news.URL, news.Source, news.Language, news.Model, news.PromptVersion, news.CreatedAt, now,
+	)
+	if err != nil {
+		return fmt.Errorf("ニュース保存エラー: %w", err)
+	}
+	return nil
 }
 
 func NewSQLiteClient(dbPath string) (*SQLiteClient, error) {
@@ -47,8 +95,12 @@ func NewSQLiteClient(dbPath string) (*SQLiteClient, error) {
 	if err := db.Ping(); err != nil {
 		return nil, fmt.Errorf("データベース接続エラー: %w", err)
 	}
+	// インメモリDBは接続ごとに別のDBになるため、接続を1つに限る
+	if dbPath == ":memory:" {
+		db.SetMaxOpenConns(1)
+	}
 
-	client := &SQLiteClient{db: db}
+	client := &SQLiteClient{conn: db, db: db}
 	if err := client.createTables(); err != nil {
 		return nil, err
 	}
@@ -80,6 +132,41 @@ func (s *SQLiteClient) createTables() error {
 		return err
 	}
 
+	// 補助金・原油価格テーブルを作成
+	if err := s.CreateFuelTaxTables(); err != nil {
+		return err
+	}
+
+	// 変動検知・要因分析テーブルを作成
+	if err := s.CreatePriceChangeTables(); err != nil {
+		return err
+	}
+
+	// 記事本文テーブルを作成
+	if err := s.CreateNewsArticleTable(); err != nil {
+		return err
+	}
+
+	// 分析結果キャッシュテーブルを作成
+	if err := s.CreateAnalysisCacheTable(); err != nil {
+		return err
+	}
+
+	// LLM利用量テーブルを作成
+	if err := s.CreateLLMUsageTable(); err != nil {
+		return err
+	}
+
+	// 改定履歴テーブルを作成
+	if err := s.CreateRevisionTables(); err != nil {
+		return err
+	}
+
+	// メンテナンス記録テーブルを作成
+	if err := s.CreateMaintenanceTable(); err != nil {
+		return err
+	}
+
 	// ニューステーブルを作成
 	newsQuery := `CREATE TABLE IF NOT EXISTS news_summaries (
 		id TEXT PRIMARY KEY,
@@ -96,25 +183,117 @@ func (s *SQLiteClient) createTables() error {
 		return fmt.Errorf("ニューステーブル作成エラー: %w", err)
 	}
 
+	// 既存DBへの列追加
+	if err := s.addColumnIfMissing("news_summaries", "source", "TEXT NOT NULL DEFAULT ''"); err != nil {
+		return err
+	}
+	if err := s.addColumnIfMissing("news_summaries", "language", "TEXT NOT NULL DEFAULT ''"); err != nil {
+		return err
+	}
+	if err := s.addColumnIfMissing("news_summaries", "model", "TEXT NOT NULL DEFAULT ''"); err != nil {
+		return err
+	}
+	if err := s.addColumnIfMissing("news_summaries", "prompt_version", "TEXT NOT NULL DEFAULT ''"); err != nil {
+		return err
+	}
+	if err := s.addColumnIfMissing("price_change_attributions", "prompt_version", "TEXT NOT NULL DEFAULT ''"); err != nil {
+		return err
+	}
+	if err := s.addColumnIfMissing("price_change_attributions", "model", "TEXT NOT NULL DEFAULT ''"); err != nil {
+		return err
+	}
+	for _, col := range []struct{ name, definition string }{
+		{"sentiment_score", "REAL NOT NULL DEFAULT 0"},
+		{"impact_score", "REAL NOT NULL DEFAULT 0"},
+		{"direction", "TEXT NOT NULL DEFAULT ''"},
+		{"confidence", "REAL NOT NULL DEFAULT 0"},
+	} {
+		if err := s.addColumnIfMissing("news_summaries", col.name, col.definition); err != nil {
+			return err
+		}
+	}
+	if err := s.migrateNewsSentiment(); err != nil {
+		return err
+	}
+	if err := s.setupNewsSearch(); err != nil {
+		return err
+	}
+
 	log.Println("✅ 全テーブルを作成しました")
 	return nil
 }
 
-func (s *SQLiteClient) SaveGasPrice(price *models.GasPrice) error {
-	query := `INSERT OR REPLACE INTO gas_prices 
-		(id, date, regular_price, premium_price, diesel_price, region, source, created_at, updated_at)
-		VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?)`
+// migrateNewsSentiment 日本語・英語が混在していた感情を正規の値に揃え、スコアをラベルから補完する
+// 確信度は不明のため0のままにする
+func (s *SQLiteClient) migrateNewsSentiment() error {
+	res, err := s.db.Exec(`
+		UPDATE news_summaries SET sentiment = CASE
+			WHEN sentiment = 'ポジティブ' OR lower(sentiment) = 'positive' THEN 'positive'
+			WHEN sentiment = 'ネガティブ' OR lower(sentiment) = 'negative' THEN 'negative'
+			ELSE 'neutral' END
+		WHERE sentiment NOT IN ('positive', 'neutral', 'negative')`)
+	if err != nil {
+		return fmt.Errorf("感情の移行エラー: %w", err)
+	}
+	if n, _ := res.RowsAffected(); n > 0 {
+		log.Printf("✅ ニュースの感情を正規化しました: %d件", n)
+	}
+
+	_, err = s.db.Exec(`
+		UPDATE news_summaries SET
+			sentiment_score = CASE sentiment WHEN 'positive' THEN 0.5 WHEN 'negative' THEN -0.5 ELSE 0 END,
+			direction = CASE sentiment WHEN 'positive' THEN 'down' WHEN 'negative' THEN 'up' ELSE 'flat' END
+		WHERE direction = ''`)
+	if err != nil {
+		return fmt.Errorf("感情スコアの移行エラー: %w", err)
+	}
+	return nil
+}
+
+// addColumnIfMissing テーブルに列が存在しなければ追加（簡易マイグレーション）
+func (s *SQLiteClient) addColumnIfMissing(table, column, definition string) error {
+	rows, err := s.db.Query(fmt.Sprintf("PRAGMA table_info(%s)", table))
+	if err != nil {
+		return fmt.Errorf("テーブル情報取得エラー: %w", err)
+	}
+	defer rows.Close()
 
-	_, err := s.db.Exec(query, price.ID, price.Date, price.RegularPrice,
-		price.PremiumPrice, price.DieselPrice, price.Region, price.Source,
-		price.CreatedAt, price.UpdatedAt)
+	for rows.Next() {
+		var cid, notNull, pk int
+		var name, colType string
+		var defaultValue sql.NullString
+		if err := rows.Scan(&cid, &name, &colType, &notNull, &defaultValue, &pk); err != nil {
+			return err
+		}
+		if name == column {
+			return nil
+		}
+	}
+	if err := rows.Err(); err != nil {
+		return err
+	}
+
+	if _, err := s.db.Exec(fmt.Sprintf("ALTER TABLE %s ADD COLUMN %s %s", table, column, definition)); err != nil {
+		return fmt.Errorf("列追加エラー (%s.%s): %w", table, column, err)
+	}
+	log.Printf("✅ 列を追加しました: %s.%s", table, column)
+	return nil
+}
 
+// SaveGasPrice ガソリン価格を保存（値が変わっていれば改定履歴に追記し、作成日時は最初の保存のまま）
+func (s *SQLiteClient) SaveGasPrice(price *models.GasPrice) (bool, error) {
+	var changed bool
+	err := s.WithTx(context.Background(), func(tx Store) error {
+		var err error
+		changed, err = saveGasPrice(tx.(*SQLiteClient).db, dialectSQLite, price)
+		return err
+	})
 	if err != nil {
-		return fmt.Errorf("データ保存エラー: %w", err)
+		return false, err
 	}
 
 	log.Printf("✅ ガソリン価格を保存: %s", price.Date)
-	return nil
+	return changed, nil
 }
 
 func (s *SQLiteClient) GetAllGasPrices() ([]*models.GasPrice, error) {
@@ -127,6 +306,11 @@ func (s *SQLiteClient) GetAllGasPrices() ([]*models.GasPrice, error) {
 	}
 	defer rows.Close()
 
+	return scanGasPrices(rows)
+}
+
+// scanGasPrices ガソリン価格の行を読み取る
+func scanGasPrices(rows *sql.Rows) ([]*models.GasPrice, error) {
 	var prices []*models.GasPrice
 	for rows.Next() {
 		var p models.GasPrice
@@ -137,12 +321,13 @@ func (s *SQLiteClient) GetAllGasPrices() ([]*models.GasPrice, error) {
 		prices = append(prices, &p)
 	}
 
-	return prices, nil
+	return prices, rows.Err()
 }
 
+// GetLatestGasPrice 最新のガソリン価格を取得（同じ日付に複数あれば地域・データソースの順で最初）
 func (s *SQLiteClient) GetLatestGasPrice() (*models.GasPrice, error) {
 	query := `SELECT id, date, regular_price, premium_price, diesel_price,
-		region, source, created_at, updated_at FROM gas_prices ORDER BY date DESC LIMIT 1`
+		region, source, created_at, updated_at FROM gas_prices ORDER BY date DESC, region, source LIMIT 1`
 
 	var p models.GasPrice
 	err := s.db.QueryRow(query).Scan(&p.ID, &p.Date, &p.RegularPrice,
@@ -155,17 +340,37 @@ func (s *SQLiteClient) GetLatestGasPrice() (*models.GasPrice, error) {
 }
 
 func (s *SQLiteClient) Close() error {
-	if s.db != nil {
+	if s.conn != nil {
 		log.Println("📪 データベース接続を閉じます")
-		return s.db.Close()
+		return s.conn.Close()
+	}
+	return nil
+}
+
+// WithTx fnを1つのトランザクションで実行（fnがエラーを返せばロールバック）
+// トランザクション内のクライアントで呼び出した場合は、そのトランザクションのままfnを実行する
+func (s *SQLiteClient) WithTx(ctx context.Context, fn func(Store) error) error {
+	if s.conn == nil {
+		return fn(s)
+	}
+	tx, err := s.conn.BeginTx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("トランザクション開始エラー: %w", err)
+	}
+	if err := fn(&SQLiteClient{db: tx, fts: s.fts}); err != nil {
+		_ = tx.Rollback()
+		return err
+	}
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("コミットエラー: %w", err)
 	}
 	return nil
 }
 
-// GetGasPriceByDate 特定日付のガソリン価格を取得
+// GetGasPriceByDate 特定日付のガソリン価格を取得（複数あれば地域・データソースの順で最初）
 func (s *SQLiteClient) GetGasPriceByDate(date string) (*models.GasPrice, error) {
 	query := `SELECT id, date, regular_price, premium_price, diesel_price,
-		region, source, created_at, updated_at FROM gas_prices WHERE date = ?`
+		region, source, created_at, updated_at FROM gas_prices WHERE date = ? ORDER BY region, source LIMIT 1`
 
 	var p models.GasPrice
 	err := s.db.QueryRow(query, date).Scan(&p.ID, &p.Date, &p.RegularPrice,
@@ -177,34 +382,78 @@ func (s *SQLiteClient) GetGasPriceByDate(date string) (*models.GasPrice, error)
 	return &p, err
 }
 
-// GetAllNews 全ニュースを取得
-func (s *SQLiteClient) GetAllNews() ([]*detect.AnalyzedNews, error) {
-	query := `SELECT id, date, title, summary, sentiment, url, created_at, updated_at 
-		FROM news_summaries ORDER BY created_at DESC`
+// GetGasPricesOnLatestDates 直近n日付（登録のある日付）の全地域のガソリン価格を日付の降順で取得
+func (s *SQLiteClient) GetGasPricesOnLatestDates(n int) ([]*models.GasPrice, error) {
+	query := `SELECT id, date, regular_price, premium_price, diesel_price,
+		region, source, created_at, updated_at FROM gas_prices
+		WHERE date IN (SELECT DISTINCT date FROM gas_prices ORDER BY date DESC LIMIT ?)
+		ORDER BY date DESC, region`
 
-	rows, err := s.db.Query(query)
+	rows, err := s.db.Query(query, n)
 	if err != nil {
 		return nil, err
 	}
 	defer rows.Close()
 
+	return scanGasPrices(rows)
+}
+
+// newsColumns ニュース取得時の列
+const newsColumns = `id, date, title, summary, sentiment, sentiment_score, impact_score, direction, confidence,
+	url, source, language, model, prompt_version, created_at, updated_at`
+
+// newsScanDest newsColumnsの順の読み取り先
+func newsScanDest(n *detect.AnalyzedNews, updatedAt *int64) []interface{} {
+	return []interface{}{&n.ID, &n.Date, &n.Title, &n.Summary, &n.Sentiment, &n.SentimentScore, &n.ImpactScore,
+		&n.Direction, &n.Confidence, &n.URL,
+		&n.Source, &n.Language, &n.Model, &n.PromptVersion, &n.CreatedAt, updatedAt}
+}
+
+// scanNews ニュースの行を読み取る
+func scanNews(rows *sql.Rows) ([]*detect.AnalyzedNews, error) {
 	var newsList []*detect.AnalyzedNews
 	for rows.Next() {
 		var n detect.AnalyzedNews
-		var id string
-		var createdAt, updatedAt int64
-		if err := rows.Scan(&id, &n.Date, &n.Title, &n.Summary, &n.Sentiment, &n.URL, &createdAt, &updatedAt); err != nil {
+		var updatedAt int64
+		if err := rows.Scan(newsScanDest(&n, &updatedAt)...); err != nil {
 			return nil, err
 		}
 		newsList = append(newsList, &n)
 	}
+	return newsList, rows.Err()
+}
+
+// GetAllNews 全ニュースを取得
+func (s *SQLiteClient) GetAllNews() ([]*detect.AnalyzedNews, error) {
+	query := `SELECT ` + newsColumns + `
+		FROM news_summaries ORDER BY created_at DESC`
+
+	rows, err := s.db.Query(query)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	return scanNews(rows)
+}
+
+// GetNewsBetween 記事日付が指定期間（YYYY-MM-DD, 両端含む）のニュースを取得
+func (s *SQLiteClient) GetNewsBetween(from, to string) ([]*detect.AnalyzedNews, error) {
+	query := `SELECT ` + newsColumns + `
+		FROM news_summaries WHERE substr(date, 1, 10) BETWEEN ? AND ? ORDER BY date DESC`
+
+	rows, err := s.db.Query(query, from, to)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
 
-	return newsList, nil
+	return scanNews(rows)
 }
 
 // GetLatestNews 最新ニュースを取得
 func (s *SQLiteClient) GetLatestNews(limit int) ([]*detect.AnalyzedNews, error) {
-	query := `SELECT id, date, title, summary, sentiment, url, created_at, updated_at 
+	query := `SELECT ` + newsColumns + `
 		FROM news_summaries ORDER BY created_at DESC LIMIT ?`
 
 	rows, err := s.db.Query(query, limit)
@@ -213,16 +462,5 @@ func (s *SQLiteClient) GetLatestNews(limit int) ([]*detect.AnalyzedNews, error)
 	}
 	defer rows.Close()
 
-	var newsList []*detect.AnalyzedNews
-	for rows.Next() {
-		var n detect.AnalyzedNews
-		var id string
-		var createdAt, updatedAt int64
-		if err := rows.Scan(&id, &n.Date, &n.Title, &n.Summary, &n.Sentiment, &n.URL, &createdAt, &updatedAt); err != nil {
-			return nil, err
-		}
-		newsList = append(newsList, &n)
-	}
-
-	return newsList, nil
+	return scanNews(rows)
 }
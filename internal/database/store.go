@@ -0,0 +1,137 @@
+package database
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"gasinsight/internal/detect"
+	fetcher "gasinsight/internal/fetch"
+	model "gasinsight/internal/model"
+)
+
+// GasPriceStore ガソリン価格の保存先
+type GasPriceStore interface {
+	SaveGasPrice(price *model.GasPrice) (bool, error) // 新規の保存か値が変わった場合はtrue
+	GetAllGasPrices() ([]*model.GasPrice, error)
+	GetLatestGasPrice() (*model.GasPrice, error)
+	GetGasPriceByDate(date string) (*model.GasPrice, error)
+	GetGasPricesOnLatestDates(n int) ([]*model.GasPrice, error)
+	ListGasPrices(q ListQuery) ([]*model.GasPrice, string, error)
+	GasPriceRevisions(q RevisionQuery) ([]*model.GasPriceRevision, error)
+	GasPricesAsOf(q RevisionQuery) ([]*model.GasPrice, error)
+}
+
+// ExchangeRateStore 為替レートの保存先
+type ExchangeRateStore interface {
+	SaveExchangeRate(rate *model.ExchangeRate) (bool, error) // いずれかの通貨の値が変わった場合はtrue
+	GetAllExchangeRates() ([]*model.ExchangeRate, error)
+	GetLatestExchangeRate() (*model.ExchangeRate, error)
+	GetExchangeRateByDate(date string) (*model.ExchangeRate, error)
+	GetExchangeRateOnOrBefore(date string) (*model.ExchangeRate, error)
+	ListExchangeRates(q ListQuery) ([]*model.ExchangeRate, string, error)
+	ExchangeRateRevisions(q RevisionQuery) ([]*model.ExchangeRateRevision, error)
+	ExchangeRatesAsOf(q RevisionQuery) ([]*model.ExchangeRate, error)
+}
+
+// NewsStore 分析済みニュースと、分析前の記事本文の保存先
+type NewsStore interface {
+	SaveNews(news *detect.AnalyzedNews) error
+	UpsertNews(news *detect.AnalyzedNews) error
+	GetAllNews() ([]*detect.AnalyzedNews, error)
+	GetNewsBetween(from, to string) ([]*detect.AnalyzedNews, error)
+	GetLatestNews(limit int) ([]*detect.AnalyzedNews, error)
+	ListNews(q ListQuery) ([]*detect.AnalyzedNews, string, error)
+	SearchNews(q NewsSearchQuery) ([]*NewsSearchHit, error)
+	FullTextSearchEnabled() bool
+	SaveNewsArticle(a fetcher.NewsArticle) error
+	GetNewsArticle(url string) (*fetcher.NewsArticle, error)
+}
+
+// FuelTaxStore 補助金・原油価格の保存先
+type FuelTaxStore interface {
+	SaveFuelSubsidy(subsidy *model.FuelSubsidy) error
+	GetAllFuelSubsidies() ([]*model.FuelSubsidy, error)
+	GetFuelSubsidyByWeek(weekStart string) (*model.FuelSubsidy, error)
+	SaveCrudePrice(price *model.CrudePrice) error
+	GetAllCrudePrices() ([]*model.CrudePrice, error)
+	GetCrudePriceOnOrBefore(date string) (*model.CrudePrice, error)
+}
+
+// LLMStore 分析キャッシュとLLM利用量の保存先
+type LLMStore interface {
+	detect.AnalysisCache
+	detect.UsageStore
+	GetAnalysisCacheStats(ttl time.Duration) (*AnalysisCacheStats, error)
+	PurgeAnalysisCache(ttl time.Duration) (int64, error)
+	GetLLMUsageSummary(from, to string) ([]*LLMUsageSummary, error)
+}
+
+// AggregateStore 週次/月次の集計
+type AggregateStore interface {
+	AggregateGasPrices(q AggregateQuery) ([]*SeriesStats, error)
+	AggregateExchangeRates(q AggregateQuery) ([]*SeriesStats, error)
+}
+
+// ChangeStore 価格変動の記録と要因分析レポートの保存先
+type ChangeStore interface {
+	SavePriceChange(c *model.PriceChangeRecord) error
+	PriceChangeExists(region, dateNew, dateOld string) (bool, error)
+	UpsertPriceChange(c *model.PriceChangeRecord) (bool, error)
+	ListPriceChanges(q ListQuery) ([]*model.PriceChangeRecord, string, error)
+	GetLatestFlaggedPriceChange() (*model.PriceChangeRecord, error)
+	GetFlaggedPriceChangesBetween(from, to string) ([]*model.PriceChangeRecord, error)
+	SavePriceChangeAttribution(a *model.PriceChangeAttribution) error
+	GetAttributionsByPriceChange(priceChangeID int64) ([]*model.PriceChangeAttribution, error)
+}
+
+// Store SQLite/PostgreSQLに共通の保存先
+// メンテナンスとバックアップ（Maintain, Backup）はSQLiteClientのみ
+type Store interface {
+	GasPriceStore
+	ExchangeRateStore
+	NewsStore
+	ChangeStore
+	FuelTaxStore
+	LLMStore
+	AggregateStore
+	WithTx(ctx context.Context, fn func(Store) error) error
+	Close() error
+}
+
+var (
+	_ Store = (*SQLiteClient)(nil)
+	_ Store = (*PostgresClient)(nil)
+)
+
+// Open DSNに応じた保存先に接続する
+//   - postgres:// または postgresql:// で始まる場合はPostgreSQL
+//   - sqlite:// で始まる場合、またはそれ以外はSQLiteのファイルパス
+func Open(dsn string) (Store, error) {
+	if IsPostgresDSN(dsn) {
+		return NewPostgresClient(dsn)
+	}
+	return NewSQLiteClient(strings.TrimPrefix(dsn, "sqlite://"))
+}
+
+// IsPostgresDSN PostgreSQLのDSNか
+func IsPostgresDSN(dsn string) bool {
+	return strings.HasPrefix(dsn, "postgres://") || strings.HasPrefix(dsn, "postgresql://")
+}
+
+// FindGasPrice 地域のガソリン価格を取得する（dateが空なら最新日、指定時はその日付）
+func FindGasPrice(s GasPriceStore, region, date string) (*model.GasPrice, error) {
+	q := ListQuery{Region: region, From: date, To: date, Sort: "-date", Limit: 1}
+	prices, _, err := s.ListGasPrices(q)
+	if err != nil {
+		return nil, err
+	}
+	if len(prices) == 0 {
+		if date == "" {
+			return nil, fmt.Errorf("%sのデータが見つかりません", region)
+		}
+		return nil, fmt.Errorf("指定日付のデータが見つかりません: %s（%s）", date, region)
+	}
+	return prices[0], nil
+}
@@ -0,0 +1,65 @@
+package database_test
+
+import (
+	"database/sql"
+	"fmt"
+	"net/url"
+	"os"
+	"testing"
+	"time"
+
+	"gasinsight/internal/database"
+	"gasinsight/internal/database/storetest"
+)
+
+// postgresDSNEnv PostgreSQLで適合テストを実行する場合の接続先（未設定ならスキップ）
+const postgresDSNEnv = "GASINSIGHT_TEST_POSTGRES_DSN"
+
+func TestSQLiteStore(t *testing.T) {
+	storetest.Run(t, func(t *testing.T) database.Store {
+		s, err := database.NewSQLiteClient(":memory:")
+		if err != nil {
+			t.Fatal(err)
+		}
+		t.Cleanup(func() { s.Close() })
+		return s
+	})
+}
+
+// TestPostgresStore ケースごとに専用のスキーマを作成し、search_pathで切り替えて実行する
+func TestPostgresStore(t *testing.T) {
+	dsn := os.Getenv(postgresDSNEnv)
+	if dsn == "" {
+		t.Skipf("%sが未設定のためスキップします", postgresDSNEnv)
+	}
+	admin, err := sql.Open("pgx", dsn)
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { admin.Close() })
+
+	n := 0
+	storetest.Run(t, func(t *testing.T) database.Store {
+		n++
+		schema := fmt.Sprintf("storetest_%d_%d", time.Now().UnixNano(), n)
+		if _, err := admin.Exec("CREATE SCHEMA " + schema); err != nil {
+			t.Fatal(err)
+		}
+		t.Cleanup(func() { admin.Exec("DROP SCHEMA " + schema + " CASCADE") })
+
+		u, err := url.Parse(dsn)
+		if err != nil {
+			t.Fatal(err)
+		}
+		params := u.Query()
+		params.Set("search_path", schema)
+		u.RawQuery = params.Encode()
+
+		s, err := database.NewPostgresClient(u.String())
+		if err != nil {
+			t.Fatal(err)
+		}
+		t.Cleanup(func() { s.Close() })
+		return s
+	})
+}
@@ -0,0 +1,808 @@
+// Package storetest database.Storeの実装（SQLite/PostgreSQL）に共通の適合テスト
+package storetest
+
+import (
+	"context"
+	"errors"
+	"slices"
+	"sort"
+	"testing"
+	"time"
+
+	"gasinsight/internal/database"
+	"gasinsight/internal/detect"
+	fetcher "gasinsight/internal/fetch"
+	model "gasinsight/internal/model"
+	"gasinsight/internal/timeseries"
+)
+
+// Run ケースごとにnewStoreで空の保存先を作成し、すべてのケースを実行する
+func Run(t *testing.T, newStore func(t *testing.T) database.Store) {
+	cases := []struct {
+		name string
+		fn   func(t *testing.T, s database.Store)
+	}{
+		{"GasPrices", testGasPrices},
+		{"FindGasPrice", testFindGasPrice},
+		{"GasPriceRevisions", testGasPriceRevisions},
+		{"CurrentValuesPerKey", testCurrentValuesPerKey},
+		{"ExchangeRates", testExchangeRates},
+		{"News", testNews},
+		{"PriceChanges", testPriceChanges},
+		{"UpsertPriceChangeByKey", testUpsertPriceChangeByKey},
+		{"Attributions", testAttributions},
+		{"FuelTax", testFuelTax},
+		{"NewsArticles", testNewsArticles},
+		{"SearchNews", testSearchNews},
+		{"AnalysisCache", testAnalysisCache},
+		{"LLMUsage", testLLMUsage},
+		{"Aggregate", testAggregate},
+		{"ListCursor", testListCursor},
+		{"ListInvalidQuery", testListInvalidQuery},
+		{"WithTxRollback", testWithTxRollback},
+	}
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			c.fn(t, newStore(t))
+		})
+	}
+}
+
+func must(t *testing.T, err error) {
+	t.Helper()
+	if err != nil {
+		t.Fatal(err)
+	}
+}
+
+// saveGasPrice 保存し、値が変わったかを返す
+func saveGasPrice(t *testing.T, s database.Store, p *model.GasPrice) bool {
+	t.Helper()
+	changed, err := s.SaveGasPrice(p)
+	must(t, err)
+	return changed
+}
+
+// testFindGasPrice 地域を指定した最新・指定日のガソリン価格
+func testFindGasPrice(t *testing.T, s database.Store) {
+	saveGasPrice(t, s, model.NewGasPrice("2025-01-06", "全国平均", 175, 186, 158))
+	saveGasPrice(t, s, model.NewGasPrice("2025-01-06", "北海道", 180, 191, 163))
+	saveGasPrice(t, s, model.NewGasPrice("2025-01-13", "北海道", 182, 193, 165))
+	saveGasPrice(t, s, model.NewGasPrice("2025-01-20", "沖縄県", 178, 189, 161))
+
+	tests := []struct {
+		region, date string
+		want         float64
+	}{
+		{"全国平均", "", 175},
+		{"北海道", "", 182},
+		{"北海道", "2025-01-06", 180},
+	}
+	for _, tt := range tests {
+		p, err := database.FindGasPrice(s, tt.region, tt.date)
+		must(t, err)
+		if p.Region != tt.region || p.RegularPrice != tt.want {
+			t.Errorf("FindGasPrice(%s, %q) = %s %.1f, want %.1f", tt.region, tt.date, p.Region, p.RegularPrice, tt.want)
+		}
+	}
+	if _, err := database.FindGasPrice(s, "全国平均", "2025-01-13"); err == nil {
+		t.Error("データのない日付でエラーになりません")
+	}
+}
+
+// saveExchangeRate 保存し、値が変わったかを返す
+func saveExchangeRate(t *testing.T, s database.Store, r *model.ExchangeRate) bool {
+	t.Helper()
+	changed, err := s.SaveExchangeRate(r)
+	must(t, err)
+	return changed
+}
+
+func gasPrice(date string, regular float64) *model.GasPrice {
+	return model.NewGasPrice(date, "全国平均", regular, regular+11, regular-17)
+}
+
+func testGasPrices(t *testing.T, s database.Store) {
+	for i, date := range []string{"2025-01-06", "2025-01-13", "2025-01-20"} {
+		saveGasPrice(t, s, gasPrice(date, 175+float64(i)))
+	}
+
+	latest, err := s.GetLatestGasPrice()
+	must(t, err)
+	if latest.Date != "2025-01-20" || latest.RegularPrice != 177 {
+		t.Errorf("GetLatestGasPrice = %s %.1f, want 2025-01-20 177.0", latest.Date, latest.RegularPrice)
+	}
+
+	p, err := s.GetGasPriceByDate("2025-01-13")
+	must(t, err)
+	if p.RegularPrice != 176 || p.PremiumPrice != 187 || p.DieselPrice != 159 || p.Region != "全国平均" {
+		t.Errorf("GetGasPriceByDate = %+v", p)
+	}
+	if _, err := s.GetGasPriceByDate("2024-12-30"); err == nil {
+		t.Error("存在しない日付でエラーになりません")
+	}
+
+	// 同じ日付を保存し直しても作成日時は最初のまま
+	updated := gasPrice("2025-01-13", 180)
+	updated.CreatedAt = p.CreatedAt + 100
+	saveGasPrice(t, s, updated)
+	p, err = s.GetGasPriceByDate("2025-01-13")
+	must(t, err)
+	if p.RegularPrice != 180 {
+		t.Errorf("更新後のレギュラー = %.1f, want 180", p.RegularPrice)
+	}
+	if p.CreatedAt != updated.CreatedAt-100 {
+		t.Errorf("作成日時が更新されています: %d", p.CreatedAt)
+	}
+
+	all, err := s.GetAllGasPrices()
+	must(t, err)
+	if len(all) != 3 {
+		t.Fatalf("GetAllGasPrices = %d件, want 3", len(all))
+	}
+
+	recent, err := s.GetGasPricesOnLatestDates(2)
+	must(t, err)
+	if got := gasPriceDates(recent); !slices.Equal(got, []string{"2025-01-20", "2025-01-13"}) {
+		t.Errorf("GetGasPricesOnLatestDates(2) = %v", got)
+	}
+}
+
+func testGasPriceRevisions(t *testing.T, s database.Store) {
+	for i, want := range []struct {
+		regular float64
+		changed bool
+	}{{175, true}, {175, false}, {176.5, true}} { // 値が同じなら改定を追記しない
+		if changed := saveGasPrice(t, s, gasPrice("2025-01-06", want.regular)); changed != want.changed {
+			t.Errorf("%d回目のSaveGasPrice = %v, want %v", i+1, changed, want.changed)
+		}
+	}
+
+	revisions, err := s.GasPriceRevisions(database.RevisionQuery{})
+	must(t, err)
+	if len(revisions) != 2 {
+		t.Fatalf("改定履歴 = %d件, want 2", len(revisions))
+	}
+	if revisions[0].RegularPrice != 175 || revisions[1].RegularPrice != 176.5 {
+		t.Errorf("改定履歴 = %.1f, %.1f", revisions[0].RegularPrice, revisions[1].RegularPrice)
+	}
+
+	current, err := s.GasPricesAsOf(database.RevisionQuery{})
+	must(t, err)
+	if len(current) != 1 || current[0].RegularPrice != 176.5 {
+		t.Errorf("GasPricesAsOf = %+v", current)
+	}
+
+	if _, err := s.GasPriceRevisions(database.RevisionQuery{Currency: "USD"}); !errors.Is(err, database.ErrInvalidQuery) {
+		t.Errorf("ガソリン価格を通貨で絞り込めています: %v", err)
+	}
+}
+
+// testCurrentValuesPerKey 同じ日付の別の地域・データソースは上書きせず、それぞれの現在の値を持つ
+func testCurrentValuesPerKey(t *testing.T, s database.Store) {
+	national := gasPrice("2025-01-06", 175)
+	tokyo := model.NewGasPrice("2025-01-06", "東京都", 178, 189, 161)
+	other := gasPrice("2025-01-06", 176)
+	other.Source = "gogo.gs"
+	for _, p := range []*model.GasPrice{national, tokyo, other} {
+		saveGasPrice(t, s, p)
+	}
+	saveGasPrice(t, s, model.NewGasPrice("2025-01-06", "東京都", 179, 190, 162))
+
+	prices, err := s.GetGasPricesOnLatestDates(1)
+	must(t, err)
+	got := map[string]float64{}
+	for _, p := range prices {
+		got[p.ID] = p.RegularPrice
+	}
+	want := map[string]float64{
+		model.GasPriceID("2025-01-06", "全国平均", "e-nenpi"): 175,
+		model.GasPriceID("2025-01-06", "東京都", "e-nenpi"):  179,
+		model.GasPriceID("2025-01-06", "全国平均", "gogo.gs"): 176,
+	}
+	if len(got) != len(want) {
+		t.Fatalf("現在の値 = %v, want %v", got, want)
+	}
+	for id, regular := range want {
+		if got[id] != regular {
+			t.Errorf("%s = %.1f, want %.1f", id, got[id], regular)
+		}
+	}
+
+	asOf, err := s.GasPricesAsOf(database.RevisionQuery{})
+	must(t, err)
+	for _, p := range asOf {
+		if want[p.ID] != p.RegularPrice {
+			t.Errorf("GasPricesAsOf %s = %.1f, want %.1f", p.ID, p.RegularPrice, want[p.ID])
+		}
+	}
+	if len(asOf) != len(want) {
+		t.Errorf("GasPricesAsOf = %d件, want %d", len(asOf), len(want))
+	}
+
+	regional, _, err := s.ListGasPrices(database.ListQuery{Region: "東京都"})
+	must(t, err)
+	if len(regional) != 1 || regional[0].RegularPrice != 179 {
+		t.Errorf("ListGasPrices(東京都) = %+v", regional)
+	}
+	bySource, _, err := s.ListGasPrices(database.ListQuery{Source: "gogo.gs"})
+	must(t, err)
+	if len(bySource) != 1 || bySource[0].Region != "全国平均" {
+		t.Errorf("ListGasPrices(gogo.gs) = %+v", bySource)
+	}
+
+	first := model.NewExchangeRate("2025-01-06", 157.2, 162.8, 196.1, 21.5)
+	second := model.NewExchangeRate("2025-01-06", 157.4, 162.9, 196.3, 21.6)
+	second.Source = "mock"
+	saveExchangeRate(t, s, first)
+	saveExchangeRate(t, s, second)
+	rates, err := s.GetAllExchangeRates()
+	must(t, err)
+	if len(rates) != 2 {
+		t.Fatalf("為替レート = %d件, want 2（データソースごと）", len(rates))
+	}
+	ratesAsOf, err := s.ExchangeRatesAsOf(database.RevisionQuery{})
+	must(t, err)
+	for _, r := range ratesAsOf {
+		if r.ID != model.ExchangeRateID(r.Date, r.Source) {
+			t.Errorf("ExchangeRatesAsOfのID = %s", r.ID)
+		}
+	}
+	if len(ratesAsOf) != 2 {
+		t.Errorf("ExchangeRatesAsOf = %d件, want 2", len(ratesAsOf))
+	}
+}
+
+func testExchangeRates(t *testing.T, s database.Store) {
+	saveExchangeRate(t, s, model.NewExchangeRate("2025-01-06", 157.2, 162.8, 196.1, 21.5))
+	saveExchangeRate(t, s, model.NewExchangeRate("2025-01-08", 158.0, 163.1, 197.0, 21.6))
+
+	latest, err := s.GetLatestExchangeRate()
+	must(t, err)
+	if latest.Date != "2025-01-08" || latest.USDJPY != 158.0 {
+		t.Errorf("GetLatestExchangeRate = %s %.2f", latest.Date, latest.USDJPY)
+	}
+
+	r, err := s.GetExchangeRateOnOrBefore("2025-01-07")
+	must(t, err)
+	if r.Date != "2025-01-06" {
+		t.Errorf("GetExchangeRateOnOrBefore(2025-01-07) = %s, want 2025-01-06", r.Date)
+	}
+	if r, err := s.GetExchangeRateOnOrBefore("2025-01-01"); r != nil || err != nil {
+		t.Errorf("前の日付がない場合 = %+v, %v, want nil", r, err)
+	}
+
+	if !saveExchangeRate(t, s, model.NewExchangeRate("2025-01-06", 157.5, 162.8, 196.1, 21.5)) {
+		t.Error("USDが変わった保存 = false, want true")
+	}
+	if saveExchangeRate(t, s, model.NewExchangeRate("2025-01-06", 157.5, 162.8, 196.1, 21.5)) {
+		t.Error("同じ値の保存 = true, want false")
+	}
+	revisions, err := s.ExchangeRateRevisions(database.RevisionQuery{Currency: "USD"})
+	must(t, err)
+	if len(revisions) != 3 {
+		t.Errorf("USDの改定履歴 = %d件, want 3", len(revisions))
+	}
+	rates, err := s.ExchangeRatesAsOf(database.RevisionQuery{From: "2025-01-06", To: "2025-01-06"})
+	must(t, err)
+	if len(rates) != 1 || rates[0].USDJPY != 157.5 || rates[0].EURJPY != 162.8 {
+		t.Errorf("ExchangeRatesAsOf = %+v", rates)
+	}
+}
+
+func testNews(t *testing.T, s database.Store) {
+	first := &detect.AnalyzedNews{
+		Title: "原油価格が上昇", Summary: "中東情勢の緊迫化で原油が上昇",
+		Sentiment: detect.SentimentNegative, SentimentScore: -0.6, ImpactLevel: "大", ImpactScore: 0.8,
+		Direction: detect.DirectionUp, Confidence: 0.9,
+		URL: "https://example.com/oil", Date: "2025-01-10T09:00:00Z", Source: "newsapi", Language: "ja",
+	}
+	must(t, s.SaveNews(first))
+	if first.ID == "" || first.CreatedAt == 0 {
+		t.Fatalf("SaveNewsでIDと作成日時が設定されていません: %+v", first)
+	}
+	must(t, s.SaveNews(&detect.AnalyzedNews{
+		Title: "円高が進行", Sentiment: detect.SentimentPositive, Direction: detect.DirectionDown,
+		URL: "https://example.com/yen", Date: "2025-01-12", Source: "rss",
+	}))
+
+	first.SentimentScore = -0.7
+	must(t, s.UpsertNews(first))
+
+	between, err := s.GetNewsBetween("2025-01-10", "2025-01-10")
+	must(t, err)
+	if len(between) != 1 || between[0].ID != first.ID {
+		t.Fatalf("GetNewsBetween = %d件", len(between))
+	}
+	got := between[0]
+	if got.SentimentScore != -0.7 || got.Direction != detect.DirectionUp || got.Source != "newsapi" || got.CreatedAt != first.CreatedAt {
+		t.Errorf("UpsertNews後のニュース = %+v", got)
+	}
+
+	all, err := s.GetAllNews()
+	must(t, err)
+	if len(all) != 2 {
+		t.Errorf("GetAllNews = %d件, want 2", len(all))
+	}
+	latest, err := s.GetLatestNews(1)
+	must(t, err)
+	if len(latest) != 1 {
+		t.Errorf("GetLatestNews(1) = %d件, want 1", len(latest))
+	}
+}
+
+func priceChange(region, dateNew, dateOld string, pct float64, flagged bool) *model.PriceChangeRecord {
+	return &model.PriceChangeRecord{
+		Region: region, DateNew: dateNew, PriceNew: 170 * (1 + pct/100), DateOld: dateOld, PriceOld: 170,
+		PctChange: pct, Flagged: flagged,
+	}
+}
+
+func testPriceChanges(t *testing.T, s database.Store) {
+	flagged := priceChange("全国平均", "2025-01-13", "2025-01-06", 2.5, true)
+	must(t, s.SavePriceChange(flagged))
+	quiet := priceChange("東京都", "2025-01-13", "2025-01-06", 0.4, false)
+	must(t, s.SavePriceChange(quiet))
+	if flagged.ID == 0 || quiet.ID == 0 || flagged.ID == quiet.ID {
+		t.Fatalf("採番されたID = %d, %d", flagged.ID, quiet.ID)
+	}
+	if flagged.CreatedAt == "" {
+		t.Error("作成日時が設定されていません")
+	}
+
+	exists, err := s.PriceChangeExists("全国平均", "2025-01-13", "2025-01-06")
+	must(t, err)
+	if !exists {
+		t.Error("PriceChangeExists = false, want true")
+	}
+	exists, err = s.PriceChangeExists("全国平均", "2025-01-20", "2025-01-13")
+	must(t, err)
+	if exists {
+		t.Error("PriceChangeExists = true, want false")
+	}
+
+	// flaggedは両方のDBで真偽値として読み書きできる
+	latest, err := s.GetLatestFlaggedPriceChange()
+	must(t, err)
+	if latest.ID != flagged.ID || !latest.Flagged || latest.PctChange != 2.5 {
+		t.Errorf("GetLatestFlaggedPriceChange = %+v", latest)
+	}
+	between, err := s.GetFlaggedPriceChangesBetween("2025-01-01", "2025-01-31")
+	must(t, err)
+	if len(between) != 1 || between[0].ID != flagged.ID {
+		t.Errorf("GetFlaggedPriceChangesBetween = %d件, want 1", len(between))
+	}
+
+	all, _, err := s.ListPriceChanges(database.ListQuery{Sort: "pct"})
+	must(t, err)
+	if len(all) != 2 || all[0].Flagged || !all[1].Flagged {
+		t.Errorf("ListPriceChanges(pct)のflagged = %v", flaggedValues(all))
+	}
+	regional, _, err := s.ListPriceChanges(database.ListQuery{Region: "東京都"})
+	must(t, err)
+	if len(regional) != 1 || regional[0].ID != quiet.ID || regional[0].Flagged {
+		t.Errorf("ListPriceChanges(東京都) = %+v", regional)
+	}
+}
+
+// testUpsertPriceChangeByKey 取り込み元のIDは使わず、地域・日付の組み合わせで追加・更新する
+func testUpsertPriceChangeByKey(t *testing.T, s database.Store) {
+	first := priceChange("全国平均", "2025-01-13", "2025-01-06", 1.0, false)
+	must(t, s.SavePriceChange(first))
+
+	// 別のDBのIDが既存の記録のIDと重なっても、その記録を上書きしない
+	imported := priceChange("全国平均", "2025-01-20", "2025-01-13", 3.0, true)
+	imported.ID = first.ID
+	changed, err := s.UpsertPriceChange(imported)
+	must(t, err)
+	if !changed || imported.ID == first.ID {
+		t.Errorf("新しい組み合わせの取り込み = %v, ID %d（既存 %d）", changed, imported.ID, first.ID)
+	}
+
+	// 同じ組み合わせは値が同じなら何もせず、異なれば既存の記録を更新する
+	again := priceChange("全国平均", "2025-01-20", "2025-01-13", 3.0, true)
+	again.ID = 999
+	changed, err = s.UpsertPriceChange(again)
+	must(t, err)
+	if changed || again.ID != imported.ID {
+		t.Errorf("同じ値の取り込み = %v, ID %d, want false, %d", changed, again.ID, imported.ID)
+	}
+	again.PctChange, again.Flagged = 0.5, false
+	changed, err = s.UpsertPriceChange(again)
+	must(t, err)
+	if !changed {
+		t.Error("値が異なる取り込み = false, want true")
+	}
+
+	all, _, err := s.ListPriceChanges(database.ListQuery{Sort: "date"})
+	must(t, err)
+	if len(all) != 2 {
+		t.Fatalf("ListPriceChanges = %d件, want 2", len(all))
+	}
+	if all[0].PctChange != 1.0 || all[1].ID != imported.ID || all[1].PctChange != 0.5 || all[1].Flagged {
+		t.Errorf("変動記録 = %+v, %+v", all[0], all[1])
+	}
+}
+
+func testAttributions(t *testing.T, s database.Store) {
+	change := priceChange("全国平均", "2025-01-13", "2025-01-06", 2.5, true)
+	must(t, s.SavePriceChange(change))
+
+	a := model.NewPriceChangeAttribution(change.ID, "openai", "gpt-4o-mini", "price_change@v1", 3, 155.2, 157.8, "円安と原油高が主因")
+	must(t, s.SavePriceChangeAttribution(a))
+
+	got, err := s.GetAttributionsByPriceChange(change.ID)
+	must(t, err)
+	if len(got) != 1 {
+		t.Fatalf("GetAttributionsByPriceChange = %d件, want 1", len(got))
+	}
+	if got[0].ID != a.ID || got[0].NewsCount != 3 || got[0].USDJPYNew != 157.8 || got[0].PromptVersion != "price_change@v1" ||
+		got[0].Analyzer != "openai" || got[0].Model != "gpt-4o-mini" {
+		t.Errorf("要因分析レポート = %+v", got[0])
+	}
+	if other, err := s.GetAttributionsByPriceChange(change.ID + 1); err != nil || len(other) != 0 {
+		t.Errorf("別の変動記録のレポート = %d件, %v", len(other), err)
+	}
+}
+
+func testFuelTax(t *testing.T, s database.Store) {
+	must(t, s.SaveFuelSubsidy(model.NewFuelSubsidy("2025-01-06", 10)))
+	must(t, s.SaveFuelSubsidy(model.NewFuelSubsidy("2025-01-06", 12.5)))
+	must(t, s.SaveFuelSubsidy(model.NewFuelSubsidy("2025-01-13", 8)))
+
+	sub, err := s.GetFuelSubsidyByWeek("2025-01-06")
+	must(t, err)
+	if sub == nil || sub.Amount != 12.5 {
+		t.Errorf("GetFuelSubsidyByWeek = %+v, want 12.5（上書き後）", sub)
+	}
+	if sub, err := s.GetFuelSubsidyByWeek("2025-01-20"); err != nil || sub != nil {
+		t.Errorf("未登録の週 = %+v, %v, want nil", sub, err)
+	}
+	subsidies, err := s.GetAllFuelSubsidies()
+	must(t, err)
+	if len(subsidies) != 2 || subsidies[0].WeekStart != "2025-01-13" {
+		t.Errorf("GetAllFuelSubsidies = %d件（新しい順）", len(subsidies))
+	}
+
+	must(t, s.SaveCrudePrice(model.NewCrudePrice("2025-01-06", 74.2)))
+	must(t, s.SaveCrudePrice(model.NewCrudePrice("2025-01-10", 76.8)))
+	crude, err := s.GetCrudePriceOnOrBefore("2025-01-09")
+	must(t, err)
+	if crude == nil || crude.Date != "2025-01-06" || crude.USDPerBarrel != 74.2 {
+		t.Errorf("GetCrudePriceOnOrBefore = %+v", crude)
+	}
+	if crude, err := s.GetCrudePriceOnOrBefore("2025-01-01"); err != nil || crude != nil {
+		t.Errorf("登録より前の日付 = %+v, %v, want nil", crude, err)
+	}
+	prices, err := s.GetAllCrudePrices()
+	must(t, err)
+	if len(prices) != 2 || prices[0].Date != "2025-01-10" {
+		t.Errorf("GetAllCrudePrices = %d件（新しい順）", len(prices))
+	}
+}
+
+func testNewsArticles(t *testing.T, s database.Store) {
+	a := fetcher.NewsArticle{
+		Title: "原油価格が上昇", Content: "本文", URL: "https://example.com/oil",
+		Date: "2025-01-10", Source: "rss", Language: "ja",
+	}
+	must(t, s.SaveNewsArticle(a))
+	a.Content = "更新後の本文"
+	must(t, s.SaveNewsArticle(a))
+
+	got, err := s.GetNewsArticle(a.URL)
+	must(t, err)
+	if got == nil || *got != a {
+		t.Errorf("GetNewsArticle = %+v, want %+v", got, a)
+	}
+	if got, err := s.GetNewsArticle("https://example.com/none"); err != nil || got != nil {
+		t.Errorf("未登録のURL = %+v, %v, want nil", got, err)
+	}
+}
+
+// testSearchNews 3文字未満の語と英字の大文字・小文字の違いはどちらの実装でも検索できる
+func testSearchNews(t *testing.T, s database.Store) {
+	for _, n := range []*detect.AnalyzedNews{
+		{Title: "円高が進行", Summary: "円高で輸入価格が下落", Sentiment: detect.SentimentPositive, URL: "https://example.com/1", Date: "2025-01-10"},
+		{Title: "OPEC減産", Summary: "産油国が減産を延長", Sentiment: detect.SentimentNegative, URL: "https://example.com/2", Date: "2025-01-11"},
+		{Title: "株価が上昇", Summary: "円高の影響は限定的", Sentiment: detect.SentimentNeutral, URL: "https://example.com/3", Date: "2025-01-12"},
+	} {
+		must(t, s.SaveNews(n))
+	}
+
+	hits, err := s.SearchNews(database.NewsSearchQuery{Query: "円高"})
+	must(t, err)
+	if len(hits) != 2 || hits[0].Title != "円高が進行" {
+		t.Fatalf("SearchNews(円高) = %d件", len(hits))
+	}
+	if hits[0].TitleHighlight != "<mark>円高</mark>が進行" || hits[0].Score <= hits[1].Score {
+		t.Errorf("強調・関連度 = %q %.1f/%.1f", hits[0].TitleHighlight, hits[0].Score, hits[1].Score)
+	}
+
+	hits, err = s.SearchNews(database.NewsSearchQuery{Query: "opec"})
+	must(t, err)
+	if len(hits) != 1 || hits[0].Title != "OPEC減産" {
+		t.Errorf("SearchNews(opec) = %d件", len(hits))
+	}
+
+	hits, err = s.SearchNews(database.NewsSearchQuery{Query: "円高", Sentiment: "neutral", From: "2025-01-12"})
+	must(t, err)
+	if len(hits) != 1 || hits[0].Title != "株価が上昇" {
+		t.Errorf("絞り込み = %d件", len(hits))
+	}
+
+	if _, err := s.SearchNews(database.NewsSearchQuery{Query: " "}); !errors.Is(err, database.ErrInvalidQuery) {
+		t.Errorf("空の検索語 = %v, want ErrInvalidQuery", err)
+	}
+}
+
+func testAnalysisCache(t *testing.T, s database.Store) {
+	key := detect.AnalysisCacheKey{ContentHash: "abc", Model: "gpt-4o-mini", PromptVersion: "news@v1"}
+	if got, err := s.GetCachedAnalysis(key, 0); err != nil || got != nil {
+		t.Fatalf("空のキャッシュ = %+v, %v", got, err)
+	}
+
+	news := &detect.AnalyzedNews{Title: "原油価格が上昇", Sentiment: detect.SentimentNegative, ImpactScore: 0.8}
+	must(t, s.SaveCachedAnalysis(key, news))
+	for i := 0; i < 2; i++ {
+		got, err := s.GetCachedAnalysis(key, time.Hour)
+		must(t, err)
+		if got == nil || got.Title != news.Title || got.ImpactScore != 0.8 {
+			t.Fatalf("GetCachedAnalysis = %+v", got)
+		}
+	}
+
+	stats, err := s.GetAnalysisCacheStats(time.Hour)
+	must(t, err)
+	if stats.Entries != 1 || stats.Hits != 2 || stats.Expired != 0 || len(stats.ByModel) != 1 || stats.ByModel[0].Model != "gpt-4o-mini" {
+		t.Errorf("GetAnalysisCacheStats = %+v", stats)
+	}
+
+	// 同じキーで保存し直すとヒット数は数え直す
+	must(t, s.SaveCachedAnalysis(key, news))
+	stats, err = s.GetAnalysisCacheStats(0)
+	must(t, err)
+	if stats.Entries != 1 || stats.Hits != 0 {
+		t.Errorf("上書き後の統計 = %+v", stats)
+	}
+
+	if n, err := s.PurgeAnalysisCache(time.Hour); err != nil || n != 0 {
+		t.Errorf("PurgeAnalysisCache(1h) = %d, %v, want 0", n, err)
+	}
+	if n, err := s.PurgeAnalysisCache(0); err != nil || n != 1 {
+		t.Errorf("PurgeAnalysisCache(0) = %d, %v, want 1", n, err)
+	}
+}
+
+// testLLMUsage 日別の集計は日本時間の日付で行う
+func testLLMUsage(t *testing.T, s database.Store) {
+	for _, u := range []*detect.LLMUsage{
+		{Backend: "openai", Model: "gpt-4o-mini", Operation: "news", PromptTokens: 100, ResponseTokens: 20,
+			Latency: 200 * time.Millisecond, CostUSD: 0.01, CreatedAt: time.Date(2025, 1, 10, 10, 0, 0, 0, time.UTC)},
+		{Backend: "openai", Model: "gpt-4o-mini", Operation: "news", PromptTokens: 300, ResponseTokens: 40,
+			Latency: 400 * time.Millisecond, CostUSD: 0.03, Error: "timeout", CreatedAt: time.Date(2025, 1, 10, 12, 0, 0, 0, time.UTC)},
+		{Backend: "openai", Model: "gpt-4o-mini", Operation: "news", PromptTokens: 50, ResponseTokens: 10,
+			Latency: 100 * time.Millisecond, CostUSD: 0.005, CreatedAt: time.Date(2025, 1, 10, 20, 0, 0, 0, time.UTC)},
+	} {
+		must(t, s.SaveLLMUsage(u))
+	}
+
+	cost, err := s.GetLLMCostSince(time.Date(2025, 1, 10, 11, 0, 0, 0, time.UTC))
+	must(t, err)
+	if cost < 0.0349 || cost > 0.0351 {
+		t.Errorf("GetLLMCostSince = %f, want 0.035", cost)
+	}
+
+	summary, err := s.GetLLMUsageSummary("2025-01-10", "2025-01-10")
+	must(t, err)
+	if len(summary) != 1 {
+		t.Fatalf("GetLLMUsageSummary = %d件, want 1（UTC 20時は日本時間の翌日）", len(summary))
+	}
+	got := summary[0]
+	if got.Day != "2025-01-10" || got.Calls != 2 || got.Errors != 1 || got.PromptTokens != 400 ||
+		got.ResponseTokens != 60 || got.AvgLatencyMs != 300 {
+		t.Errorf("日別集計 = %+v", got)
+	}
+
+	summary, err = s.GetLLMUsageSummary("2025-01-11", "2025-01-11")
+	must(t, err)
+	if len(summary) != 1 || summary[0].Calls != 1 {
+		t.Errorf("翌日の集計 = %d件", len(summary))
+	}
+}
+
+func testAggregate(t *testing.T, s database.Store) {
+	for i, date := range []string{"2025-01-06", "2025-01-08", "2025-01-13"} {
+		saveGasPrice(t, s, gasPrice(date, 170+float64(2*i)))
+	}
+	saveExchangeRate(t, s, model.NewExchangeRate("2025-01-06", 157, 162, 196, 21.5))
+	saveExchangeRate(t, s, model.NewExchangeRate("2025-01-07", 158, 163, 197, 21.6))
+
+	gas, err := s.AggregateGasPrices(database.AggregateQuery{Period: timeseries.PeriodWeek, Fuels: []string{"regular"}})
+	must(t, err)
+	if len(gas) != 1 || gas[0].Series != "regular" || gas[0].Region != "全国平均" || len(gas[0].Stats) != 2 {
+		t.Fatalf("AggregateGasPrices = %+v", gas)
+	}
+	if week := gas[0].Stats[0]; week.Count != 2 || week.Avg != 171 || week.First != 170 || week.Last != 172 {
+		t.Errorf("週次集計 = %+v", week)
+	}
+
+	rates, err := s.AggregateExchangeRates(database.AggregateQuery{Period: timeseries.PeriodMonth, Currencies: []string{"USD"}})
+	must(t, err)
+	if len(rates) != 1 || len(rates[0].Stats) != 1 || rates[0].Stats[0].Avg != 157.5 {
+		t.Errorf("AggregateExchangeRates = %+v", rates)
+	}
+
+	if _, err := s.AggregateGasPrices(database.AggregateQuery{Period: "day"}); !errors.Is(err, database.ErrInvalidQuery) {
+		t.Errorf("不正な集計単位 = %v, want ErrInvalidQuery", err)
+	}
+}
+
+// testListCursor 同じ値の行を含む並び順で、カーソルを辿ると全件を重複なく並び順どおりに取得できる
+func testListCursor(t *testing.T, s database.Store) {
+	dates := []string{"2025-01-06", "2025-01-13", "2025-01-20", "2025-01-27", "2025-02-03"}
+	regulars := []float64{175, 176, 175, 177, 176}
+	for i, date := range dates {
+		saveGasPrice(t, s, gasPrice(date, regulars[i]))
+	}
+	for _, sortName := range []string{"-date", "date", "regular", "-regular"} {
+		var got []string
+		pageCount := 0
+		q := database.ListQuery{Sort: sortName, Limit: 2}
+		for {
+			page, next, err := s.ListGasPrices(q)
+			must(t, err)
+			pageCount++
+			for _, p := range page {
+				got = append(got, p.Date)
+			}
+			if next == "" || pageCount > len(dates) {
+				break
+			}
+			q.Cursor = next
+		}
+		all, _, err := s.ListGasPrices(database.ListQuery{Sort: sortName})
+		must(t, err)
+		if want := gasPriceDates(all); !slices.Equal(got, want) {
+			t.Errorf("sort=%s: カーソルで取得 = %v, 一括で取得 = %v", sortName, got, want)
+		}
+	}
+
+	page, _, err := s.ListGasPrices(database.ListQuery{From: "2025-01-13", To: "2025-01-27", Sort: "date", Limit: 2, Offset: 1})
+	must(t, err)
+	if got := gasPriceDates(page); !slices.Equal(got, []string{"2025-01-20", "2025-01-27"}) {
+		t.Errorf("期間とoffsetの指定 = %v", got)
+	}
+	page, _, err = s.ListGasPrices(database.ListQuery{Sort: "date", Offset: 3})
+	must(t, err)
+	if len(page) != 2 {
+		t.Errorf("offsetのみの指定 = %d件, want 2", len(page))
+	}
+
+	// 整数（created_at）と小数（pct_change）の列でもカーソルを辿れる
+	for i, pct := range []float64{2.5, -1.25, 2.5, 0, 3.75} {
+		must(t, s.SavePriceChange(priceChange("全国平均", dates[i], "2024-12-30", pct, pct >= 2)))
+		must(t, s.UpsertNews(&detect.AnalyzedNews{
+			ID: "news-" + dates[i], Title: dates[i], Sentiment: detect.SentimentNeutral,
+			URL: "https://example.com/" + dates[i], Date: dates[i], CreatedAt: int64(1736000000 + i%2),
+		}))
+	}
+	changes := collect(t, func(cursor string) ([]*model.PriceChangeRecord, string, error) {
+		return s.ListPriceChanges(database.ListQuery{Sort: "-pct", Limit: 2, Cursor: cursor})
+	})
+	var pcts []float64
+	for _, c := range changes {
+		pcts = append(pcts, c.PctChange)
+	}
+	if !sort.IsSorted(sort.Reverse(sort.Float64Slice(pcts))) || len(pcts) != 5 {
+		t.Errorf("sort=-pct: カーソルで取得 = %v", pcts)
+	}
+	news := collect(t, func(cursor string) ([]*detect.AnalyzedNews, string, error) {
+		return s.ListNews(database.ListQuery{Sort: "created", Limit: 2, Cursor: cursor})
+	})
+	seen := map[string]bool{}
+	for _, n := range news {
+		seen[n.ID] = true
+	}
+	if len(news) != 5 || len(seen) != 5 {
+		t.Errorf("sort=created: カーソルで取得 = %d件（重複なし%d件）, want 5", len(news), len(seen))
+	}
+}
+
+// collect カーソルを辿って全ページを取得する
+func collect[T any](t *testing.T, page func(cursor string) ([]T, string, error)) []T {
+	t.Helper()
+	var all []T
+	cursor := ""
+	for i := 0; i < 10; i++ {
+		items, next, err := page(cursor)
+		must(t, err)
+		all = append(all, items...)
+		if next == "" {
+			return all
+		}
+		cursor = next
+	}
+	t.Fatal("カーソルが終わりません")
+	return nil
+}
+
+func testListInvalidQuery(t *testing.T, s database.Store) {
+	saveGasPrice(t, s, gasPrice("2025-01-06", 175))
+	_, next, err := s.ListGasPrices(database.ListQuery{Sort: "date", Limit: 1})
+	must(t, err)
+
+	for name, q := range map[string]database.ListQuery{
+		"並び順":         {Sort: "unknown"},
+		"日付":          {From: "2025/01/06"},
+		"負のlimit":     {Limit: -1},
+		"カーソルとoffset": {Sort: "date", Cursor: next, Offset: 1},
+		"並び順の違うカーソル":  {Sort: "-date", Cursor: next},
+		"壊れたカーソル":     {Cursor: "!!"},
+	} {
+		if _, _, err := s.ListGasPrices(q); !errors.Is(err, database.ErrInvalidQuery) {
+			t.Errorf("%s: err = %v, want ErrInvalidQuery", name, err)
+		}
+	}
+	if _, _, err := s.ListExchangeRates(database.ListQuery{Region: "東京都"}); !errors.Is(err, database.ErrInvalidQuery) {
+		t.Errorf("為替レートを地域で絞り込めています: %v", err)
+	}
+}
+
+func testWithTxRollback(t *testing.T, s database.Store) {
+	errAbort := errors.New("abort")
+	err := s.WithTx(context.Background(), func(tx database.Store) error {
+		saveGasPrice(t, tx, gasPrice("2025-01-06", 175))
+		c := priceChange("全国平均", "2025-01-06", "2024-12-30", 2.5, true)
+		must(t, tx.SavePriceChange(c))
+		// トランザクション内では保存した値が見える
+		if _, err := tx.GetGasPriceByDate("2025-01-06"); err != nil {
+			t.Errorf("トランザクション内で保存した値が見えません: %v", err)
+		}
+		// 入れ子のWithTxは同じトランザクションで実行する
+		return tx.WithTx(context.Background(), func(inner database.Store) error {
+			saveExchangeRate(t, inner, model.NewExchangeRate("2025-01-06", 157.2, 162.8, 196.1, 21.5))
+			return errAbort
+		})
+	})
+	if !errors.Is(err, errAbort) {
+		t.Fatalf("WithTx = %v, want %v", err, errAbort)
+	}
+
+	if prices, err := s.GetAllGasPrices(); err != nil || len(prices) != 0 {
+		t.Errorf("ロールバック後のガソリン価格 = %d件, %v", len(prices), err)
+	}
+	if rates, err := s.GetAllExchangeRates(); err != nil || len(rates) != 0 {
+		t.Errorf("ロールバック後の為替レート = %d件, %v", len(rates), err)
+	}
+	if revisions, err := s.GasPriceRevisions(database.RevisionQuery{}); err != nil || len(revisions) != 0 {
+		t.Errorf("ロールバック後の改定履歴 = %d件, %v", len(revisions), err)
+	}
+	if exists, err := s.PriceChangeExists("全国平均", "2025-01-06", "2024-12-30"); err != nil || exists {
+		t.Errorf("ロールバック後に変動記録が残っています: %v", err)
+	}
+
+	must(t, s.WithTx(context.Background(), func(tx database.Store) error {
+		_, err := tx.SaveGasPrice(gasPrice("2025-01-13", 176))
+		return err
+	}))
+	if _, err := s.GetGasPriceByDate("2025-01-13"); err != nil {
+		t.Errorf("コミットした値が見えません: %v", err)
+	}
+}
+
+func gasPriceDates(prices []*model.GasPrice) []string {
+	dates := make([]string, len(prices))
+	for i, p := range prices {
+		dates[i] = p.Date
+	}
+	return dates
+}
+
+func flaggedValues(changes []*model.PriceChangeRecord) []bool {
+	values := make([]bool, len(changes))
+	for i, c := range changes {
+		values[i] = c.Flagged
+	}
+	return values
+}
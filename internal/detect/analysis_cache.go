@@ -0,0 +1,84 @@
+package detect
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	fetcher "gasinsight/internal/fetch"
+	"log"
+	"strings"
+	"time"
+)
+
+// AnalysisCacheKey 分析結果キャッシュのキー
+// 同じ本文を同じモデル・同じプロンプトで分析した結果は再利用できる
+type AnalysisCacheKey struct {
+	ContentHash   string
+	Model         string
+	PromptVersion string
+}
+
+// AnalysisCache 分析結果の永続キャッシュ
+type AnalysisCache interface {
+	// GetCachedAnalysis maxAgeより新しい結果を返す（なければnil。maxAgeが0以下なら期限なし）
+	GetCachedAnalysis(key AnalysisCacheKey, maxAge time.Duration) (*AnalyzedNews, error)
+	SaveCachedAnalysis(key AnalysisCacheKey, news *AnalyzedNews) error
+}
+
+// ContentHash 記事のタイトルと本文からキャッシュ用のハッシュを計算
+// 空白の違いで別の記事とみなさないよう正規化してからハッシュを取る
+func ContentHash(article fetcher.NewsArticle) string {
+	normalize := func(s string) string { return strings.Join(strings.Fields(s), " ") }
+	sum := sha256.Sum256([]byte(normalize(article.Title) + "\n" + normalize(article.Content)))
+	return hex.EncodeToString(sum[:])
+}
+
+// CachedAnalyzer キャッシュを参照してから分析器を呼び出す
+type CachedAnalyzer struct {
+	analyzer NewsAnalyzer
+	cache    AnalysisCache
+	ttl      time.Duration
+}
+
+// NewCachedAnalyzer 分析器をキャッシュで包む（ttlが0以下なら期限なし）
+func NewCachedAnalyzer(analyzer NewsAnalyzer, cache AnalysisCache, ttl time.Duration) *CachedAnalyzer {
+	return &CachedAnalyzer{analyzer: analyzer, cache: cache, ttl: ttl}
+}
+
+func (c *CachedAnalyzer) Name() string          { return c.analyzer.Name() }
+func (c *CachedAnalyzer) Model() string         { return c.analyzer.Model() }
+func (c *CachedAnalyzer) PromptVersion() string { return c.analyzer.PromptVersion() }
+
+// Analyze キャッシュにあればその結果を、なければ分析して保存した結果を返す
+func (c *CachedAnalyzer) Analyze(article fetcher.NewsArticle) (*AnalyzedNews, error) {
+	key := AnalysisCacheKey{
+		ContentHash:   ContentHash(article),
+		Model:         c.analyzer.Name() + ":" + c.analyzer.Model(),
+		PromptVersion: c.analyzer.PromptVersion(),
+	}
+
+	cached, err := c.cache.GetCachedAnalysis(key, c.ttl)
+	if err != nil {
+		log.Printf("⚠️  キャッシュ参照エラー: %v", err)
+	}
+	if cached != nil {
+		log.Printf("💾 キャッシュを使用: %s", article.Title)
+		// 同じ本文でも掲載元・URLは異なる場合があるため記事側の値を使う
+		cached.Title = article.Title
+		cached.URL = article.URL
+		cached.Date = article.Date
+		cached.Source = article.Source
+		cached.Language = article.Language
+		// 正規化前に保存された結果にも対応する
+		cached.Normalize()
+		return cached, nil
+	}
+
+	analyzed, err := c.analyzer.Analyze(article)
+	if err != nil {
+		return nil, err
+	}
+	if err := c.cache.SaveCachedAnalysis(key, analyzed); err != nil {
+		log.Printf("⚠️  キャッシュ保存エラー: %v", err)
+	}
+	return analyzed, nil
+}
@@ -0,0 +1,142 @@
+package detect
+
+import (
+	"context"
+	"fmt"
+	fetcher "gasinsight/internal/fetch"
+	"gasinsight/internal/prompt"
+	"log"
+	"sync"
+	"time"
+)
+
+// NewsAnalyzer ニュース分析のバックエンド
+type NewsAnalyzer interface {
+	Name() string          // バックエンド名（mock, gemini, openai）
+	Model() string         // 使用するモデル
+	PromptVersion() string // プロンプトのバージョン（変更時はキャッシュを無効化するため上げる）
+	Analyze(article fetcher.NewsArticle) (*AnalyzedNews, error)
+}
+
+// NewsPromptName ニュース分析プロンプトのテンプレート名
+const NewsPromptName = "news_analysis"
+
+// PriceChangePromptName 価格変動の要因分析プロンプトのテンプレート名
+const PriceChangePromptName = "price_change"
+
+// PriceChangeAnalyzer 価格変動の要因分析のバックエンド
+type PriceChangeAnalyzer interface {
+	Name() string
+	Model() string
+	PromptVersion() string
+	AnalyzePriceChange(ctx context.Context, in PriceChangeContext) (string, error)
+}
+
+// analyzer ニュース分析と要因分析の両方を行うバックエンド
+type analyzer interface {
+	NewsAnalyzer
+	PriceChangeAnalyzer
+}
+
+// NewNewsAnalyzer バックエンド名から分析器を作成（modelが空ならデフォルトモデル）
+// tmplはニュース分析プロンプト（mockでは使用しないためnil可）
+// meterがnilでなければAPI呼び出しごとに利用量を記録し、1日の上限で停止する
+func NewNewsAnalyzer(backend, model string, tmpl *prompt.Template, meter *UsageMeter) (NewsAnalyzer, error) {
+	return newAnalyzer(backend, model, tmpl, meter)
+}
+
+// NewPriceChangeAnalyzer バックエンド名から要因分析器を作成（引数はNewNewsAnalyzerと同じ）
+// tmplは要因分析プロンプト（mockでは使用しないためnil可）
+func NewPriceChangeAnalyzer(backend, model string, tmpl *prompt.Template, meter *UsageMeter) (PriceChangeAnalyzer, error) {
+	return newAnalyzer(backend, model, tmpl, meter)
+}
+
+func newAnalyzer(backend, model string, tmpl *prompt.Template, meter *UsageMeter) (analyzer, error) {
+	if backend != "mock" && tmpl == nil {
+		return nil, fmt.Errorf("%sバックエンドにはプロンプトテンプレートが必要です", backend)
+	}
+
+	switch backend {
+	case "mock":
+		return &MockAnalyzer{}, nil
+	case "gemini":
+		if model == "" {
+			model = DefaultGeminiModel
+		}
+		// 無料枠のレート制限回避のため、リクエスト間隔を空ける
+		return &GeminiAnalyzer{model: model, tmpl: tmpl, meter: meter, minInterval: 10 * time.Second}, nil
+	case "openai":
+		if model == "" {
+			model = DefaultOpenAIModel
+		}
+		return &OpenAIAnalyzer{model: model, tmpl: tmpl, meter: meter}, nil
+	default:
+		return nil, fmt.Errorf("不明な分析バックエンド: %s（mock/gemini/openai）", backend)
+	}
+}
+
+// MockAnalyzer キーワードによるモック分析（API不要）
+type MockAnalyzer struct{}
+
+func (m *MockAnalyzer) Name() string          { return "mock" }
+func (m *MockAnalyzer) Model() string         { return "mock" }
+func (m *MockAnalyzer) PromptVersion() string { return MockPromptVersion }
+
+func (m *MockAnalyzer) Analyze(article fetcher.NewsArticle) (*AnalyzedNews, error) {
+	return MockAnalyzeNews(article)
+}
+
+func (m *MockAnalyzer) AnalyzePriceChange(ctx context.Context, in PriceChangeContext) (string, error) {
+	return MockAnalyzePriceChange(in)
+}
+
+// GeminiAnalyzer Gemini APIによる分析
+type GeminiAnalyzer struct {
+	model       string
+	tmpl        *prompt.Template
+	meter       *UsageMeter
+	minInterval time.Duration // リクエストの最小間隔
+
+	mu   sync.Mutex
+	last time.Time
+}
+
+func (g *GeminiAnalyzer) Name() string          { return "gemini" }
+func (g *GeminiAnalyzer) Model() string         { return g.model }
+func (g *GeminiAnalyzer) PromptVersion() string { return g.tmpl.ID() }
+
+func (g *GeminiAnalyzer) Analyze(article fetcher.NewsArticle) (*AnalyzedNews, error) {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+
+	if wait := g.minInterval - time.Since(g.last); !g.last.IsZero() && wait > 0 {
+		log.Printf("⏳ APIレート制限回避のため %.0f秒待機中...", wait.Seconds())
+		time.Sleep(wait)
+	}
+	defer func() { g.last = time.Now() }()
+
+	return analyzeNewsWithGemini(article, g.model, g.tmpl, g.meter)
+}
+
+func (g *GeminiAnalyzer) AnalyzePriceChange(ctx context.Context, in PriceChangeContext) (string, error) {
+	return analyzePriceChangeWithGemini(ctx, in, g.model, g.tmpl, g.meter)
+}
+
+// OpenAIAnalyzer OpenAI APIによる分析
+type OpenAIAnalyzer struct {
+	model string
+	tmpl  *prompt.Template
+	meter *UsageMeter
+}
+
+func (o *OpenAIAnalyzer) Name() string          { return "openai" }
+func (o *OpenAIAnalyzer) Model() string         { return o.model }
+func (o *OpenAIAnalyzer) PromptVersion() string { return o.tmpl.ID() }
+
+func (o *OpenAIAnalyzer) Analyze(article fetcher.NewsArticle) (*AnalyzedNews, error) {
+	return analyzeNewsWithOpenAI(article, o.model, o.tmpl, o.meter)
+}
+
+func (o *OpenAIAnalyzer) AnalyzePriceChange(ctx context.Context, in PriceChangeContext) (string, error) {
+	return analyzePriceChangeWithOpenAI(ctx, in, o.model, o.tmpl, o.meter)
+}
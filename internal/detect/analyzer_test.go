@@ -0,0 +1,94 @@
+package detect
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"gasinsight/internal/prompt"
+)
+
+// recordingStore 記録された利用量を保持する
+type recordingStore struct {
+	usages []*LLMUsage
+}
+
+func (s *recordingStore) SaveLLMUsage(u *LLMUsage) error {
+	s.usages = append(s.usages, u)
+	return nil
+}
+
+func (s *recordingStore) GetLLMCostSince(time.Time) (float64, error) { return 0, nil }
+
+var priceChange = PriceChangeContext{
+	Region: "全国平均", DateOld: "2025-01-06", DateNew: "2025-01-13",
+	OldPrice: 175, NewPrice: 180, PctChange: 2.86, USDJPYOld: 155, USDJPYNew: 158,
+}
+
+func TestNewPriceChangeAnalyzer(t *testing.T) {
+	a, err := NewPriceChangeAnalyzer("mock", "", nil, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	report, err := a.AnalyzePriceChange(context.Background(), priceChange)
+	if err != nil || !strings.Contains(report, "円安") {
+		t.Errorf("モック分析 = %q, %v", report, err)
+	}
+	if a.Name() != "mock" || a.PromptVersion() != MockPromptVersion {
+		t.Errorf("モック分析器 = %s / %s", a.Name(), a.PromptVersion())
+	}
+
+	if _, err := NewPriceChangeAnalyzer("gemini", "", nil, nil); err == nil {
+		t.Error("プロンプトなしでエラーになりません")
+	}
+	if _, err := NewPriceChangeAnalyzer("claude", "", nil, nil); err == nil {
+		t.Error("不明なバックエンドでエラーになりません")
+	}
+}
+
+func TestPriceChangeAnalyzerUsesConfiguredModel(t *testing.T) {
+	var requested string
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var req struct {
+			Model string `json:"model"`
+		}
+		json.NewDecoder(r.Body).Decode(&req)
+		requested = req.Model
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(`{"choices":[{"message":{"role":"assistant","content":"円安が主因です"}}],"usage":{"prompt_tokens":120,"completion_tokens":30}}`))
+	}))
+	defer srv.Close()
+	t.Setenv("OPENAI_BASE_URL", srv.URL)
+	t.Setenv("OPENAI_API_KEY", "test")
+
+	tmpl, err := prompt.NewStore("../../prompts").Load(PriceChangePromptName, "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	store := &recordingStore{}
+	a, err := NewPriceChangeAnalyzer("openai", "local-model", tmpl, NewUsageMeter(store, 0))
+	if err != nil {
+		t.Fatal(err)
+	}
+	report, err := a.AnalyzePriceChange(context.Background(), priceChange)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if report != "円安が主因です" || requested != "local-model" {
+		t.Errorf("回答 = %q, 要求したモデル = %q", report, requested)
+	}
+	if a.Name() != "openai" || a.Model() != "local-model" {
+		t.Errorf("分析器 = %s / %s", a.Name(), a.Model())
+	}
+	if len(store.usages) != 1 {
+		t.Fatalf("利用量 = %d件, want 1", len(store.usages))
+	}
+	if u := store.usages[0]; u.Backend != "openai" || u.Model != "local-model" || u.Operation != "price_change" || u.PromptTokens != 120 {
+		t.Errorf("利用量 = %+v", u)
+	}
+}
@@ -0,0 +1,330 @@
+package detect
+
+import (
+	"fmt"
+	"math"
+	"sort"
+	"strings"
+
+	model "gasinsight/internal/model"
+	"gasinsight/internal/timeseries"
+)
+
+// DetectionMethod 異常検知の手法
+type DetectionMethod string
+
+const (
+	MethodZScore DetectionMethod = "zscore" // 移動窓の平均・標準偏差に対するzスコア
+	MethodEWMA   DetectionMethod = "ewma"   // EWMA管理図の管理限界
+	MethodStreak DetectionMethod = "streak" // 連続上昇（下落）
+)
+
+// Baseline 判定に使った基準値
+type Baseline struct {
+	Window int     `json:"window"`          // 基準に使った点数
+	Mean   float64 `json:"mean"`            // 基準平均
+	StdDev float64 `json:"stddev"`          // 基準標準偏差
+	Lower  float64 `json:"lower,omitempty"` // 下側管理限界
+	Upper  float64 `json:"upper,omitempty"` // 上側管理限界
+	From   string  `json:"from"`            // 基準期間の開始日
+	To     string  `json:"to"`              // 基準期間の終了日
+}
+
+// DetectionEvent 検知イベント
+type DetectionEvent struct {
+	Series    string          `json:"series"`    // 系列名（例: gas/regular/全国平均）
+	Date      string          `json:"date"`      // 検知日
+	Method    DetectionMethod `json:"method"`    // 手法
+	Value     float64         `json:"value"`     // 観測値
+	Score     float64         `json:"score"`     // zスコア / EWMA値 / 連続日数
+	Threshold float64         `json:"threshold"` // 判定しきい値
+	Direction string          `json:"direction"` // up / down
+	Baseline  Baseline        `json:"baseline"`  // 判定基準
+}
+
+// String ログ表示用の文字列
+func (e DetectionEvent) String() string {
+	return fmt.Sprintf("[%s] %s %s value=%.2f score=%.2f threshold=%.2f dir=%s baseline(mean=%.2f sd=%.2f n=%d %s〜%s)",
+		e.Method, e.Series, e.Date, e.Value, e.Score, e.Threshold, e.Direction,
+		e.Baseline.Mean, e.Baseline.StdDev, e.Baseline.Window, e.Baseline.From, e.Baseline.To)
+}
+
+// Detector 時系列の異常検知器
+type Detector interface {
+	Method() DetectionMethod
+	Detect(series string, s timeseries.Series) []DetectionEvent
+}
+
+// ZScoreDetector 直近Window点の平均・標準偏差からのzスコアで判定
+type ZScoreDetector struct {
+	Window    int
+	Threshold float64
+}
+
+func (d ZScoreDetector) Method() DetectionMethod { return MethodZScore }
+
+func (d ZScoreDetector) Detect(series string, s timeseries.Series) []DetectionEvent {
+	// 標準偏差には2点以上の窓が必要
+	if d.Window < 2 {
+		return nil
+	}
+
+	var events []DetectionEvent
+	for i := d.Window; i < len(s); i++ {
+		window := s[i-d.Window : i]
+		values := window.Values()
+		mean := timeseries.Mean(values)
+		sd := timeseries.StdDev(values)
+		if sd == 0 {
+			continue
+		}
+
+		z := (s[i].Value - mean) / sd
+		if math.Abs(z) < d.Threshold {
+			continue
+		}
+		events = append(events, DetectionEvent{
+			Series:    series,
+			Date:      timeseries.FormatDate(s[i].Date),
+			Method:    MethodZScore,
+			Value:     s[i].Value,
+			Score:     z,
+			Threshold: d.Threshold,
+			Direction: direction(z),
+			Baseline: Baseline{
+				Window: d.Window,
+				Mean:   mean,
+				StdDev: sd,
+				Lower:  mean - d.Threshold*sd,
+				Upper:  mean + d.Threshold*sd,
+				From:   timeseries.FormatDate(window[0].Date),
+				To:     timeseries.FormatDate(window[len(window)-1].Date),
+			},
+		})
+	}
+	return events
+}
+
+// EWMADetector EWMA管理図。直前Window点の平均・標準偏差を基準とし、
+// 平滑化係数Lambda、管理限界幅L（σ単位）で判定。管理限界を超えた時点で1回だけ通知する
+type EWMADetector struct {
+	Lambda float64
+	L      float64
+	Window int
+}
+
+func (d EWMADetector) Method() DetectionMethod { return MethodEWMA }
+
+func (d EWMADetector) Detect(series string, s timeseries.Series) []DetectionEvent {
+	if len(s) <= d.Window || d.Window < 2 {
+		return nil
+	}
+
+	var events []DetectionEvent
+	ewma := s[0].Value
+	outside := false
+	for i := 1; i < len(s); i++ {
+		ewma = d.Lambda*s[i].Value + (1-d.Lambda)*ewma
+		if i < d.Window {
+			continue
+		}
+
+		window := s[i-d.Window : i]
+		mean := timeseries.Mean(window.Values())
+		sd := timeseries.StdDev(window.Values())
+		if sd == 0 {
+			continue
+		}
+
+		width := d.L * sd * math.Sqrt(d.Lambda/(2-d.Lambda))
+		lower, upper := mean-width, mean+width
+		if ewma >= lower && ewma <= upper {
+			outside = false
+			continue
+		}
+		if outside {
+			continue
+		}
+		outside = true
+
+		events = append(events, DetectionEvent{
+			Series:    series,
+			Date:      timeseries.FormatDate(s[i].Date),
+			Method:    MethodEWMA,
+			Value:     s[i].Value,
+			Score:     ewma,
+			Threshold: d.L,
+			Direction: direction(ewma - mean),
+			Baseline: Baseline{
+				Window: d.Window,
+				Mean:   mean,
+				StdDev: sd,
+				Lower:  lower,
+				Upper:  upper,
+				From:   timeseries.FormatDate(window[0].Date),
+				To:     timeseries.FormatDate(window[len(window)-1].Date),
+			},
+		})
+	}
+	return events
+}
+
+// StreakDetector MinLength回以上の連続上昇（Downがtrueなら連続下落）を検知
+type StreakDetector struct {
+	MinLength int
+	Down      bool
+}
+
+func (d StreakDetector) Method() DetectionMethod { return MethodStreak }
+
+func (d StreakDetector) Detect(series string, s timeseries.Series) []DetectionEvent {
+	var events []DetectionEvent
+	streak := 0
+	for i := 1; i < len(s); i++ {
+		diff := s[i].Value - s[i-1].Value
+		if (!d.Down && diff > 0) || (d.Down && diff < 0) {
+			streak++
+		} else {
+			streak = 0
+		}
+
+		// 連続回数がしきい値に達した時点で1回だけ通知
+		if streak != d.MinLength {
+			continue
+		}
+
+		start := s[i-streak]
+		dir := "up"
+		if d.Down {
+			dir = "down"
+		}
+		events = append(events, DetectionEvent{
+			Series:    series,
+			Date:      timeseries.FormatDate(s[i].Date),
+			Method:    MethodStreak,
+			Value:     s[i].Value,
+			Score:     float64(streak),
+			Threshold: float64(d.MinLength),
+			Direction: dir,
+			Baseline: Baseline{
+				Window: streak,
+				Mean:   start.Value,
+				From:   timeseries.FormatDate(start.Date),
+				To:     timeseries.FormatDate(s[i-1].Date),
+			},
+		})
+	}
+	return events
+}
+
+func direction(v float64) string {
+	if v < 0 {
+		return "down"
+	}
+	return "up"
+}
+
+// AnomalyConfig 系列ごとの検知器設定
+type AnomalyConfig struct {
+	Default   []Detector            // 個別設定のない系列に使う検知器
+	PerSeries map[string][]Detector // 系列名（またはその接頭辞 "gas/" など）ごとの検知器
+}
+
+// DefaultAnomalyConfig デフォルトの検知器設定
+// ガソリン価格は変動が小さいため感度を高め、為替は日々の変動が大きいため窓を長めにとる
+func DefaultAnomalyConfig() AnomalyConfig {
+	return AnomalyConfig{
+		Default: []Detector{
+			ZScoreDetector{Window: 14, Threshold: 3.0},
+		},
+		PerSeries: map[string][]Detector{
+			"gas/": {
+				ZScoreDetector{Window: 8, Threshold: 2.5},
+				EWMADetector{Lambda: 0.2, L: 3.0, Window: 8},
+				StreakDetector{MinLength: 4},
+			},
+			"fx/": {
+				ZScoreDetector{Window: 20, Threshold: 3.0},
+				EWMADetector{Lambda: 0.1, L: 3.0, Window: 20},
+			},
+		},
+	}
+}
+
+// DetectorsFor 系列名に対応する検知器を返す（完全一致 → 最長接頭辞一致 → デフォルト）
+func (c AnomalyConfig) DetectorsFor(series string) []Detector {
+	if d, ok := c.PerSeries[series]; ok {
+		return d
+	}
+	best := ""
+	for prefix := range c.PerSeries {
+		if strings.HasPrefix(series, prefix) && len(prefix) > len(best) {
+			best = prefix
+		}
+	}
+	if best != "" {
+		return c.PerSeries[best]
+	}
+	return c.Default
+}
+
+// NamedSeries 系列名付きの時系列
+type NamedSeries struct {
+	Name   string
+	Series timeseries.Series
+}
+
+// DetectAnomalies 全系列に設定された検知器を適用し、日付順のイベントを返す
+func DetectAnomalies(series []NamedSeries, cfg AnomalyConfig) []DetectionEvent {
+	var events []DetectionEvent
+	for _, ns := range series {
+		for _, d := range cfg.DetectorsFor(ns.Name) {
+			events = append(events, d.Detect(ns.Name, ns.Series)...)
+		}
+	}
+	sort.SliceStable(events, func(i, j int) bool { return events[i].Date < events[j].Date })
+	return events
+}
+
+// AnomalySource 異常検知に使用するデータの取得元
+type AnomalySource interface {
+	GetAllGasPrices() ([]*model.GasPrice, error)
+	GetAllExchangeRates() ([]*model.ExchangeRate, error)
+}
+
+// LoadAnomalySeries 保存済みデータから検知対象の系列を作成
+// 系列名: gas/<燃料種別>/<地域>, fx/<通貨>
+func LoadAnomalySeries(src AnomalySource) ([]NamedSeries, error) {
+	prices, err := src.GetAllGasPrices()
+	if err != nil {
+		return nil, fmt.Errorf("ガソリン価格取得エラー: %w", err)
+	}
+	rates, err := src.GetAllExchangeRates()
+	if err != nil {
+		return nil, fmt.Errorf("為替レート取得エラー: %w", err)
+	}
+
+	regions := map[string]bool{}
+	for _, p := range prices {
+		regions[p.Region] = true
+	}
+
+	var series []NamedSeries
+	for region := range regions {
+		for _, fuel := range []string{"regular", "premium", "diesel"} {
+			series = append(series, NamedSeries{
+				Name:   "gas/" + fuel + "/" + region,
+				Series: timeseries.FromGasPrices(prices, fuel, region),
+			})
+		}
+	}
+	for _, currency := range []string{"USD", "EUR", "GBP", "CNY"} {
+		series = append(series, NamedSeries{
+			Name:   "fx/" + currency,
+			Series: timeseries.FromExchangeRates(rates, currency),
+		})
+	}
+
+	sort.Slice(series, func(i, j int) bool { return series[i].Name < series[j].Name })
+	return series, nil
+}
@@ -0,0 +1,98 @@
+package detect
+
+import (
+	"testing"
+	"time"
+
+	"gasinsight/internal/timeseries"
+)
+
+const (
+	spikeIndex  = 20 // 急騰を埋め込む位置
+	streakStart = 30 // 連続上昇を始める位置
+)
+
+// plantedSeries 小さく上下する系列に1回の急騰を埋め込み、最後を5回の小さな連続上昇にする
+func plantedSeries() timeseries.Series {
+	jitter := []float64{0, 0.4, -0.2, 0.2, -0.1, 0.3, -0.3}
+	start := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+	s := make(timeseries.Series, streakStart+6)
+	for i := range s {
+		v := 170 + jitter[i%len(jitter)]
+		switch {
+		case i == spikeIndex:
+			v = 178
+		case i >= streakStart:
+			v = 170 + 0.05*float64(i-streakStart)
+		}
+		s[i] = timeseries.Point{Date: start.AddDate(0, 0, i), Value: v}
+	}
+	return s
+}
+
+func dateAt(s timeseries.Series, i int) string {
+	return timeseries.FormatDate(s[i].Date)
+}
+
+func TestZScoreDetectorFindsSpike(t *testing.T) {
+	s := plantedSeries()
+	events := ZScoreDetector{Window: 8, Threshold: 2.5}.Detect("gas/regular/全国平均", s)
+	if len(events) != 1 {
+		t.Fatalf("検知 = %d件, want 1: %v", len(events), events)
+	}
+	e := events[0]
+	if e.Date != dateAt(s, spikeIndex) || e.Direction != "up" || e.Score < 2.5 || e.Value != 178 {
+		t.Errorf("検知イベント = %v", e)
+	}
+	if e.Baseline.Window != 8 || e.Baseline.From != dateAt(s, spikeIndex-8) || e.Baseline.To != dateAt(s, spikeIndex-1) {
+		t.Errorf("基準期間 = %+v", e.Baseline)
+	}
+}
+
+func TestEWMADetectorFindsSpike(t *testing.T) {
+	s := plantedSeries()
+	events := EWMADetector{Lambda: 0.2, L: 3.0, Window: 8}.Detect("gas/regular/全国平均", s)
+	if len(events) == 0 {
+		t.Fatal("急騰を検知しません")
+	}
+	// 管理限界を超えた時点で1回だけ通知する
+	e := events[0]
+	if e.Date != dateAt(s, spikeIndex) || e.Direction != "up" || e.Score <= e.Baseline.Upper {
+		t.Errorf("検知イベント = %v", e)
+	}
+	for _, e := range events[1:] {
+		if e.Date == dateAt(s, spikeIndex+1) {
+			t.Errorf("管理限界の外にいる間に再通知しました: %v", e)
+		}
+	}
+}
+
+func TestStreakDetectorFindsStreak(t *testing.T) {
+	s := plantedSeries()
+	events := StreakDetector{MinLength: 4}.Detect("gas/regular/全国平均", s)
+	if len(events) != 1 {
+		t.Fatalf("検知 = %d件, want 1: %v", len(events), events)
+	}
+	// 4回目の上昇の時点で1回だけ通知する（5回目では通知しない）
+	e := events[0]
+	if e.Date != dateAt(s, streakStart+4) || e.Direction != "up" || e.Score != 4 || e.Baseline.From != dateAt(s, streakStart) {
+		t.Errorf("検知イベント = %v", e)
+	}
+
+	if events := (StreakDetector{MinLength: 4, Down: true}).Detect("gas/regular/全国平均", s); len(events) != 0 {
+		t.Errorf("連続下落 = %d件, want 0", len(events))
+	}
+}
+
+// TestDetectorsRejectShortWindow 窓幅が2点未満の設定でもパニックせず、何も検知しない
+func TestDetectorsRejectShortWindow(t *testing.T) {
+	s := plantedSeries()
+	for _, window := range []int{-1, 0, 1} {
+		if events := (ZScoreDetector{Window: window, Threshold: 2.5}).Detect("s", s); len(events) != 0 {
+			t.Errorf("ZScore window=%d: %d件", window, len(events))
+		}
+		if events := (EWMADetector{Lambda: 0.2, L: 3, Window: window}).Detect("s", s); len(events) != 0 {
+			t.Errorf("EWMA window=%d: %d件", window, len(events))
+		}
+	}
+}
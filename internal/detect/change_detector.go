@@ -1,176 +1,109 @@
 package detect
 
 import (
-	"database/sql"
 	"fmt"
 	"log"
 	"math"
-	"time"
 
-	_ "github.com/mattn/go-sqlite3"
+	model "gasinsight/internal/model"
 )
 
-// DetectPriceChanges opens the given sqlite DB file, compares the most recent two dates
-// of gas_price per region, calculates percent change, and logs/inserts flagged changes.
-// dbPath: path to sqlite DB (e.g. "data/gasinsight.db")
-// thresholdPct: absolute percent threshold, e.g. 2.0 for ±2%
-func DetectPriceChanges(dbPath string, thresholdPct float64) error {
-	db, err := sql.Open("sqlite3", dbPath)
-	if err != nil {
-		return fmt.Errorf("open db: %w", err)
-	}
-	defer func() {
-		_ = db.Close()
-	}()
+// DefaultChangeThresholdPct 変動を検知するしきい値（±%）
+const DefaultChangeThresholdPct = 2.0
 
-	// ensure change table exists
-	if err := ensurePriceChangeTable(db); err != nil {
-		return fmt.Errorf("ensure table: %w", err)
-	}
+// PriceChangeStore 変動検知に使う保存先（database.Storeやそのトランザクションが満たす）
+type PriceChangeStore interface {
+	GetGasPricesOnLatestDates(n int) ([]*model.GasPrice, error)
+	PriceChangeExists(region, dateNew, dateOld string) (bool, error)
+	SavePriceChange(c *model.PriceChangeRecord) error
+}
+
+// PriceChangeDetector 直近2日付のレギュラー価格を地域ごとに比較し、変動を記録する
+type PriceChangeDetector struct {
+	store        PriceChangeStore
+	thresholdPct float64
+}
+
+// NewPriceChangeDetector 変動検知を作成（thresholdPctは±%、例: 2.0）
+func NewPriceChangeDetector(store PriceChangeStore, thresholdPct float64) *PriceChangeDetector {
+	return &PriceChangeDetector{store: store, thresholdPct: thresholdPct}
+}
 
-	// get two most recent distinct dates
-	dates, err := getLatestTwoDates(db)
+// Detect 直近2日付を比較して変動を記録し、新たに記録した変動を返す
+// 同じ地域・日付の組み合わせを記録済みの場合は記録しない
+func (d *PriceChangeDetector) Detect() ([]*model.PriceChangeRecord, error) {
+	prices, err := d.store.GetGasPricesOnLatestDates(2)
 	if err != nil {
-		return fmt.Errorf("get dates: %w", err)
+		return nil, fmt.Errorf("価格取得エラー: %w", err)
+	}
+
+	// 日付の降順で、地域ごとに新旧の価格を集める
+	var dates []string
+	byDate := map[string]map[string]float64{}
+	var regions []string
+	seenRegion := map[string]bool{}
+	for _, p := range prices {
+		if byDate[p.Date] == nil {
+			byDate[p.Date] = map[string]float64{}
+			dates = append(dates, p.Date)
+		}
+		byDate[p.Date][p.Region] = p.RegularPrice
+		if !seenRegion[p.Region] {
+			seenRegion[p.Region] = true
+			regions = append(regions, p.Region)
+		}
 	}
 	if len(dates) < 2 {
 		log.Printf("[change_detector] not enough dates for comparison (need 2), found %d", len(dates))
-		return nil
-	}
-	dateNew := dates[0] // newest
-	dateOld := dates[1] // previous
-
-	// iterate regions
-	regions, err := getRegions(db)
-	if err != nil {
-		return fmt.Errorf("get regions: %w", err)
+		return nil, nil
 	}
+	dateNew, dateOld := dates[0], dates[1]
 
+	var changes []*model.PriceChangeRecord
 	for _, region := range regions {
-		priceNew, okNew, err := getPriceForRegionDate(db, region, dateNew)
-		if err != nil {
-			log.Printf("[change_detector] error fetching new price region=%s date=%s: %v", region, dateNew, err)
-			continue
-		}
-		priceOld, okOld, err := getPriceForRegionDate(db, region, dateOld)
-		if err != nil {
-			log.Printf("[change_detector] error fetching old price region=%s date=%s: %v", region, dateOld, err)
-			continue
-		}
+		priceNew, okNew := byDate[dateNew][region]
+		priceOld, okOld := byDate[dateOld][region]
 		if !okNew || !okOld {
-			// missing data for this region/date - skip
 			log.Printf("[change_detector] skipping region=%s because missing price at one of dates (%s/%s)", region, dateNew, dateOld)
 			continue
 		}
-
-		// percent change: ((new - old) / old) * 100
 		if priceOld == 0 {
 			log.Printf("[change_detector] old price zero for region=%s/date=%s, skipping to avoid div0", region, dateOld)
 			continue
 		}
-		pct := ((priceNew - priceOld) / priceOld) * 100
-		flagged := math.Abs(pct) >= thresholdPct
-
-		// log result
-		log.Printf("[change_detector] region=%s date_new=%s price_new=%.2f date_old=%s price_old=%.2f pct=%.3f flagged=%v",
-			region, dateNew, priceNew, dateOld, priceOld, pct, flagged,
-		)
 
-		// insert record of this comparison
-		if err := insertPriceChange(db, region, dateNew, priceNew, dateOld, priceOld, pct, flagged); err != nil {
-			log.Printf("[change_detector] insert error region=%s: %v", region, err)
+		exists, err := d.store.PriceChangeExists(region, dateNew, dateOld)
+		if err != nil {
+			return changes, err
 		}
-
-		// if flagged -> you can call notification here or collect flagged items for later notification
-		if flagged {
-			// keep it simple: just log; integration (Slack/LINE) should read from price_change table or
-			// you can directly notify here by calling a notifier.
-			log.Printf("[change_detector] ALERT: region=%s pct_change=%.3f%% (threshold %.2f%%)", region, pct, thresholdPct)
+		if exists {
+			log.Printf("[change_detector] already recorded region=%s date_new=%s date_old=%s", region, dateNew, dateOld)
+			continue
 		}
-	}
-
-	return nil
-}
-
-func ensurePriceChangeTable(db *sql.DB) error {
-	create := `
-	CREATE TABLE IF NOT EXISTS price_change (
-		id INTEGER PRIMARY KEY AUTOINCREMENT,
-		region TEXT,
-		date_new TEXT,
-		price_new REAL,
-		date_old TEXT,
-		price_old REAL,
-		pct_change REAL,
-		flagged INTEGER,
-		created_at DATETIME DEFAULT (datetime('now'))
-	);
-	`
-	_, err := db.Exec(create)
-	return err
-}
-
-func getLatestTwoDates(db *sql.DB) ([]string, error) {
-	// distinct dates in gas_price table ordered desc
-	rows, err := db.Query(`SELECT DISTINCT date FROM gas_prices ORDER BY date DESC LIMIT 2`)
-	if err != nil {
-		return nil, err
-	}
-	defer rows.Close()
 
-	var dates []string
-	for rows.Next() {
-		var d string
-		if err := rows.Scan(&d); err != nil {
-			return nil, err
+		pct := ((priceNew - priceOld) / priceOld) * 100
+		c := &model.PriceChangeRecord{
+			Region:    region,
+			DateNew:   dateNew,
+			PriceNew:  priceNew,
+			DateOld:   dateOld,
+			PriceOld:  priceOld,
+			PctChange: pct,
+			Flagged:   math.Abs(pct) >= d.thresholdPct,
 		}
-		dates = append(dates, d)
-	}
-	return dates, rows.Err()
-}
-
-func getRegions(db *sql.DB) ([]string, error) {
-	rows, err := db.Query(`SELECT DISTINCT region FROM gas_prices`)
-	if err != nil {
-		return nil, err
-	}
-	defer rows.Close()
+		log.Printf("[change_detector] region=%s date_new=%s price_new=%.2f date_old=%s price_old=%.2f pct=%.3f flagged=%v",
+			region, dateNew, priceNew, dateOld, priceOld, pct, c.Flagged,
+		)
 
-	var regs []string
-	for rows.Next() {
-		var r string
-		if err := rows.Scan(&r); err != nil {
-			return nil, err
+		if err := d.store.SavePriceChange(c); err != nil {
+			return changes, err
 		}
-		regs = append(regs, r)
-	}
-	return regs, rows.Err()
-}
+		changes = append(changes, c)
 
-func getPriceForRegionDate(db *sql.DB, region, date string) (float64, bool, error) {
-	row := db.QueryRow(`SELECT regular_price FROM gas_prices WHERE region = ? AND date = ? LIMIT 1`, region, date)
-	var p sql.NullFloat64
-	if err := row.Scan(&p); err != nil {
-		if err == sql.ErrNoRows {
-			return 0, false, nil
+		if c.Flagged {
+			log.Printf("[change_detector] ALERT: region=%s pct_change=%.3f%% (threshold %.2f%%)", region, pct, d.thresholdPct)
 		}
-		return 0, false, err
-	}
-	if !p.Valid {
-		return 0, false, nil
 	}
-	return p.Float64, true, nil
-}
 
-func insertPriceChange(db *sql.DB, region, dateNew string, priceNew float64, dateOld string, priceOld float64, pct float64, flagged bool) error {
-	stmt := `INSERT INTO price_change (region, date_new, price_new, date_old, price_old, pct_change, flagged, created_at) VALUES (?, ?, ?, ?, ?, ?, ?, ?)`
-	_, err := db.Exec(stmt, region, dateNew, priceNew, dateOld, priceOld, pct, boolToInt(flagged), time.Now().UTC())
-	return err
-}
-
-func boolToInt(b bool) int {
-	if b {
-		return 1
-	}
-	return 0
+	return changes, nil
 }
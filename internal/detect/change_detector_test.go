@@ -0,0 +1,188 @@
+// databaseがdetectをimportするため、外部テストパッケージにする
+package detect_test
+
+import (
+	"context"
+	"errors"
+	"math"
+	"testing"
+
+	"gasinsight/internal/database"
+	"gasinsight/internal/detect"
+	model "gasinsight/internal/model"
+)
+
+func newStore(t *testing.T) *database.SQLiteClient {
+	t.Helper()
+	s, err := database.NewSQLiteClient(":memory:")
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { s.Close() })
+	return s
+}
+
+// savePrices 日付ごとに地域 → レギュラー価格を保存する
+func savePrices(t *testing.T, s database.Store, date string, regulars map[string]float64) {
+	t.Helper()
+	for region, regular := range regulars {
+		if _, err := s.SaveGasPrice(model.NewGasPrice(date, region, regular, regular+11, regular-17)); err != nil {
+			t.Fatal(err)
+		}
+	}
+}
+
+func countChanges(t *testing.T, s database.Store) int {
+	t.Helper()
+	changes, _, err := s.ListPriceChanges(database.ListQuery{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	return len(changes)
+}
+
+func TestDetectNeedsTwoDates(t *testing.T) {
+	s := newStore(t)
+	d := detect.NewPriceChangeDetector(s, detect.DefaultChangeThresholdPct)
+
+	changes, err := d.Detect()
+	if err != nil || len(changes) != 0 {
+		t.Fatalf("データなし: %d件, %v", len(changes), err)
+	}
+
+	savePrices(t, s, "2025-01-06", map[string]float64{"全国平均": 175})
+	changes, err = d.Detect()
+	if err != nil || len(changes) != 0 {
+		t.Fatalf("1日付のみ: %d件, %v", len(changes), err)
+	}
+	if n := countChanges(t, s); n != 0 {
+		t.Errorf("変動記録 = %d件, want 0", n)
+	}
+}
+
+func TestDetectSkipsRegionMissingOnOneDate(t *testing.T) {
+	s := newStore(t)
+	savePrices(t, s, "2025-01-06", map[string]float64{"全国平均": 175, "北海道": 178})
+	savePrices(t, s, "2025-01-13", map[string]float64{"全国平均": 176, "東京都": 180})
+
+	changes, err := detect.NewPriceChangeDetector(s, detect.DefaultChangeThresholdPct).Detect()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(changes) != 1 || changes[0].Region != "全国平均" {
+		t.Fatalf("変動 = %+v, want 全国平均のみ", changes)
+	}
+	if c := changes[0]; c.DateNew != "2025-01-13" || c.DateOld != "2025-01-06" || c.PriceNew != 176 || c.PriceOld != 175 {
+		t.Errorf("変動 = %+v", c)
+	}
+}
+
+func TestDetectSkipsZeroOldPrice(t *testing.T) {
+	s := newStore(t)
+	savePrices(t, s, "2025-01-06", map[string]float64{"全国平均": 0, "東京都": 180})
+	savePrices(t, s, "2025-01-13", map[string]float64{"全国平均": 176, "東京都": 180})
+
+	changes, err := detect.NewPriceChangeDetector(s, detect.DefaultChangeThresholdPct).Detect()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(changes) != 1 || changes[0].Region != "東京都" {
+		t.Fatalf("変動 = %+v, want 東京都のみ", changes)
+	}
+	if math.IsNaN(changes[0].PctChange) || math.IsInf(changes[0].PctChange, 0) {
+		t.Errorf("変動率 = %v", changes[0].PctChange)
+	}
+}
+
+func TestDetectFlagsAtThreshold(t *testing.T) {
+	s := newStore(t)
+	savePrices(t, s, "2025-01-06", map[string]float64{"上昇": 100, "しきい値未満": 100, "下落": 100, "横ばい": 100})
+	savePrices(t, s, "2025-01-13", map[string]float64{"上昇": 102, "しきい値未満": 101.9, "下落": 97.5, "横ばい": 100})
+
+	changes, err := detect.NewPriceChangeDetector(s, 2.0).Detect()
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := map[string]bool{"上昇": true, "しきい値未満": false, "下落": true, "横ばい": false}
+	if len(changes) != len(want) {
+		t.Fatalf("変動 = %d件, want %d", len(changes), len(want))
+	}
+	for _, c := range changes {
+		if c.Flagged != want[c.Region] {
+			t.Errorf("%s: %+.2f%% flagged = %v, want %v", c.Region, c.PctChange, c.Flagged, want[c.Region])
+		}
+	}
+
+	flagged, err := s.GetFlaggedPriceChangesBetween("2025-01-13", "2025-01-13")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(flagged) != 2 {
+		t.Errorf("保存したしきい値超えの変動 = %d件, want 2", len(flagged))
+	}
+}
+
+func TestDetectSkipsRecordedPairs(t *testing.T) {
+	s := newStore(t)
+	savePrices(t, s, "2025-01-06", map[string]float64{"全国平均": 175, "東京都": 178})
+	savePrices(t, s, "2025-01-13", map[string]float64{"全国平均": 176})
+	d := detect.NewPriceChangeDetector(s, detect.DefaultChangeThresholdPct)
+
+	if changes, err := d.Detect(); err != nil || len(changes) != 1 {
+		t.Fatalf("1回目: %d件, %v", len(changes), err)
+	}
+
+	// 記録済みの組み合わせは飛ばし、新たに比較できるようになった地域だけを記録する
+	savePrices(t, s, "2025-01-13", map[string]float64{"東京都": 181})
+	changes, err := d.Detect()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(changes) != 1 || changes[0].Region != "東京都" {
+		t.Fatalf("2回目: %+v, want 東京都のみ", changes)
+	}
+	if changes, err := d.Detect(); err != nil || len(changes) != 0 {
+		t.Fatalf("3回目: %d件, %v", len(changes), err)
+	}
+	if n := countChanges(t, s); n != 2 {
+		t.Errorf("変動記録 = %d件, want 2", n)
+	}
+}
+
+// failingStore 変動記録の保存に失敗する保存先
+type failingStore struct {
+	database.Store
+}
+
+var errSave = errors.New("保存に失敗")
+
+func (failingStore) SavePriceChange(*model.PriceChangeRecord) error { return errSave }
+
+func TestDetectFailureRollsBackSavedPrice(t *testing.T) {
+	s := newStore(t)
+	savePrices(t, s, "2025-01-06", map[string]float64{"全国平均": 175})
+
+	// fetch* モードと同じく、保存と変動検知を1つのトランザクションで行う
+	err := s.WithTx(context.Background(), func(tx database.Store) error {
+		savePrices(t, tx, "2025-01-13", map[string]float64{"全国平均": 180})
+		_, err := detect.NewPriceChangeDetector(failingStore{tx}, detect.DefaultChangeThresholdPct).Detect()
+		return err
+	})
+	if !errors.Is(err, errSave) {
+		t.Fatalf("WithTx = %v, want %v", err, errSave)
+	}
+
+	if p, err := s.GetGasPriceByDate("2025-01-13"); err == nil {
+		t.Errorf("検知に失敗した価格が保存されています: %+v", p)
+	}
+	latest, err := s.GetLatestGasPrice()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if latest.Date != "2025-01-06" {
+		t.Errorf("最新の価格 = %s, want 2025-01-06", latest.Date)
+	}
+	if n := countChanges(t, s); n != 0 {
+		t.Errorf("変動記録 = %d件, want 0", n)
+	}
+}
@@ -4,21 +4,35 @@ import (
 	"context"
 	"fmt"
 	fetcher "gasinsight/internal/fetch"
+	"gasinsight/internal/prompt"
 	"log"
 	"os"
 	"strings"
+	"time"
 
 	"github.com/google/generative-ai-go/genai"
 	"google.golang.org/api/option"
 )
 
-// AnalyzeNewsWithGemini Gemini APIを使ってニュースを分析
-func AnalyzeNewsWithGemini(article fetcher.NewsArticle) (*AnalyzedNews, error) {
+// DefaultGeminiModel デフォルトのGeminiモデル
+const DefaultGeminiModel = "gemini-2.0-flash-lite"
+
+func analyzeNewsWithGemini(article fetcher.NewsArticle, modelName string, tmpl *prompt.Template, meter *UsageMeter) (*AnalyzedNews, error) {
 	apiKey := os.Getenv("GEMINI_API_KEY")
 	if apiKey == "" {
 		return nil, fmt.Errorf("GEMINI_API_KEY環境変数が設定されていません")
 	}
 
+	// プロンプトを構築
+	text, err := tmpl.Render(article)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := meter.Check(); err != nil {
+		return nil, err
+	}
+
 	ctx := context.Background()
 	client, err := genai.NewClient(ctx, option.WithAPIKey(apiKey))
 	if err != nil {
@@ -26,27 +40,12 @@ func AnalyzeNewsWithGemini(article fetcher.NewsArticle) (*AnalyzedNews, error) {
 	}
 	defer client.Close()
 
-	// Gemini 2.0 Flash Lite を使用（高速・軽量）
-	model := client.GenerativeModel("gemini-2.0-flash-lite")
-
-	// プロンプトを構築
-	prompt := fmt.Sprintf(`以下のニュース記事を分析してください。
-
-タイトル: %s
-内容: %s
-
-以下の形式で回答してください：
-【要約】
-（3行以内で要約）
-
-【感情分析】
-（ポジティブ/ニュートラル/ネガティブ のいずれか1つのみ）
-
-【ガソリン価格への影響】
-（大/中/小/なし のいずれか1つ）`, article.Title, article.Content)
+	model := client.GenerativeModel(modelName)
 
 	log.Printf("🤖 Gemini APIでニュース分析中...")
-	resp, err := model.GenerateContent(ctx, genai.Text(prompt))
+	start := time.Now()
+	resp, err := model.GenerateContent(ctx, genai.Text(text))
+	meter.Record(geminiUsage("news", modelName, tmpl.ID(), resp, time.Since(start), err))
 	if err != nil {
 		if strings.Contains(err.Error(), "429") {
 			return nil, fmt.Errorf("Gemini APIレート制限超過 (429): しばらく待ってから再試行してください。詳細: %w", err)
@@ -65,82 +64,95 @@ func AnalyzeNewsWithGemini(article fetcher.NewsArticle) (*AnalyzedNews, error) {
 		return nil, fmt.Errorf("Gemini APIからの応答が予期しない形式です")
 	}
 
-	// 感情分析の抽出（簡易版）
-	sentiment := "ニュートラル"
-	summaryLower := strings.ToLower(summary)
-	if strings.Contains(summaryLower, "ポジティブ") || strings.Contains(summaryLower, "positive") {
-		sentiment = "ポジティブ"
-	} else if strings.Contains(summaryLower, "ネガティブ") || strings.Contains(summaryLower, "negative") {
-		sentiment = "ネガティブ"
+	analyzed := &AnalyzedNews{
+		Title:         article.Title,
+		URL:           article.URL,
+		Date:          article.Date, // PublishedAt -> Date
+		Summary:       summary,
+		Source:        article.Source,
+		Language:      article.Language,
+		Model:         modelName,
+		PromptVersion: tmpl.ID(),
 	}
+	parseAnalysis(summary).apply(analyzed)
 
-	// ガソリン価格への影響を抽出
-	impact := "なし"
-	if strings.Contains(summary, "大") {
-		impact = "大"
-	} else if strings.Contains(summary, "中") {
-		impact = "中"
-	} else if strings.Contains(summary, "小") {
-		impact = "小"
-	}
+	log.Printf("✅ 分析完了: %s (スコア %+.2f, 影響 %.2f, 確信度 %.2f)",
+		analyzed.Sentiment.Label(), analyzed.SentimentScore, analyzed.ImpactScore, analyzed.Confidence)
+	return analyzed, nil
+}
 
-	log.Printf("✅ 分析完了: %s", sentiment)
+// PriceChangeContext 価格変動の要因分析に渡すデータ
+type PriceChangeContext struct {
+	Region    string
+	DateOld   string
+	DateNew   string
+	OldPrice  float64
+	NewPrice  float64
+	PctChange float64
+	USDJPYOld float64 // 変動前のUSD/JPY（不明なら0）
+	USDJPYNew float64 // 変動後のUSD/JPY（不明なら0）
+	News      []*AnalyzedNews
+}
+
+// HasFX 為替データが揃っているか
+func (c PriceChangeContext) HasFX() bool {
+	return c.USDJPYOld > 0 && c.USDJPYNew > 0
+}
 
-	return &AnalyzedNews{
-		Title:       article.Title,
-		URL:         article.URL,
-		Date:        article.Date, // PublishedAt -> Date
-		Summary:     summary,
-		Sentiment:   sentiment,
-		ImpactLevel: impact,
-	}, nil
+// PriceDiff 価格の変動幅（円）
+func (c PriceChangeContext) PriceDiff() float64 {
+	return c.NewPrice - c.OldPrice
 }
 
-// AnalyzePriceChange はガソリン価格の変動とニュース記事を受け取り、変動要因を分析します
-func AnalyzePriceChange(ctx context.Context, priceDiff int, oldPrice, newPrice int, newsList []fetcher.NewsArticle) (string, error) {
+// FXDiff USD/JPYの変動幅（円）
+func (c PriceChangeContext) FXDiff() float64 {
+	return c.USDJPYNew - c.USDJPYOld
+}
+
+// NewsFrom 要因分析で参照するニュースの開始日
+// 変動後の日付のdays日前と変動前の日付のうち早い方（比較期間全体と、その前のdays日間を含める）
+func NewsFrom(dateOld, dateNew string, days int) string {
+	from := dateOld
+	if t, err := time.Parse("2006-01-02", dateNew); err == nil {
+		if d := t.AddDate(0, 0, -days).Format("2006-01-02"); from == "" || d < from {
+			from = d
+		}
+	}
+	if from == "" {
+		return dateNew
+	}
+	return from
+}
+
+// analyzePriceChangeWithGemini ガソリン価格の変動と期間中の分析済みニュース・為替変動から、変動要因を分析する
+// meterがnilでなければ利用量を記録し、1日の上限を超えていれば呼び出さない
+func analyzePriceChangeWithGemini(ctx context.Context, in PriceChangeContext, modelName string, tmpl *prompt.Template, meter *UsageMeter) (string, error) {
 	apiKey := os.Getenv("GEMINI_API_KEY")
 	if apiKey == "" {
 		return "", fmt.Errorf("GEMINI_API_KEY is not set")
 	}
 
-	client, err := genai.NewClient(ctx, option.WithAPIKey(apiKey))
+	// プロンプト作成
+	text, err := tmpl.Render(in)
 	if err != nil {
-		return "", fmt.Errorf("failed to create client: %w", err)
+		return "", err
 	}
-	defer client.Close()
 
-	// Gemini 2.0 Flash Lite を使用
-	model := client.GenerativeModel("gemini-2.0-flash-lite")
-
-	// ニュースリストをテキスト化
-	var newsText string
-	for i, n := range newsList {
-		newsText += fmt.Sprintf("%d. %s\n   (URL: %s)\n", i+1, n.Title, n.URL)
+	if err := meter.Check(); err != nil {
+		return "", err
 	}
 
-	// プロンプト作成
-	prompt := fmt.Sprintf(`
-あなたはエネルギー市場のアナリストです。
-日本のガソリン価格が以下のように変動しました。
-提供されたニュース記事の中から、この価格変動の要因として考えられるものを特定し、その理由を解説してください。
-
-【価格変動データ】
-- 変動前: %d円
-- 変動後: %d円
-- 変動幅: %+d円
-
-【本日のニュース】
-%s
-
-【分析依頼】
-1. この価格変動に最も影響を与えたと思われるニュースを1つ以上挙げてください。
-2. なぜそのニュースが価格に影響したのか、因果関係を論理的に説明してください。
-3. もし関連するニュースがない場合は、「関連するニュースは見当たりませんでした」と回答してください。
+	client, err := genai.NewClient(ctx, option.WithAPIKey(apiKey))
+	if err != nil {
+		return "", fmt.Errorf("failed to create client: %w", err)
+	}
+	defer client.Close()
 
-回答は日本語で、一般のドライバーにも分かりやすく簡潔にお願いします。
-`, oldPrice, newPrice, priceDiff, newsText)
+	model := client.GenerativeModel(modelName)
 
-	resp, err := model.GenerateContent(ctx, genai.Text(prompt))
+	start := time.Now()
+	resp, err := model.GenerateContent(ctx, genai.Text(text))
+	meter.Record(geminiUsage("price_change", modelName, tmpl.ID(), resp, time.Since(start), err))
 	if err != nil {
 		return "", fmt.Errorf("Gemini API error: %w", err)
 	}
@@ -155,3 +167,22 @@ func AnalyzePriceChange(ctx context.Context, priceDiff int, oldPrice, newPrice i
 
 	return "", fmt.Errorf("unexpected response format")
 }
+
+// geminiUsage Geminiのレスポンスから利用量を作成
+func geminiUsage(operation, model, promptVersion string, resp *genai.GenerateContentResponse, latency time.Duration, err error) *LLMUsage {
+	u := &LLMUsage{
+		Backend:       "gemini",
+		Model:         model,
+		PromptVersion: promptVersion,
+		Operation:     operation,
+		Latency:       latency,
+	}
+	if err != nil {
+		u.Error = err.Error()
+	}
+	if resp != nil && resp.UsageMetadata != nil {
+		u.PromptTokens = int(resp.UsageMetadata.PromptTokenCount)
+		u.ResponseTokens = int(resp.UsageMetadata.CandidatesTokenCount)
+	}
+	return u
+}
@@ -0,0 +1,25 @@
+package detect_test
+
+import (
+	"testing"
+
+	"gasinsight/internal/detect"
+)
+
+func TestNewsFrom(t *testing.T) {
+	for _, tt := range []struct {
+		dateOld, dateNew string
+		days             int
+		want             string
+	}{
+		{"2025-01-06", "2025-01-13", 7, "2025-01-06"},
+		{"2025-01-06", "2025-01-13", 3, "2025-01-06"},  // 比較期間が長ければ変動前の日付から
+		{"2025-01-06", "2025-01-13", 14, "2024-12-30"}, // 変動前の日付よりさらにさかのぼる
+		{"", "2025-01-13", 7, "2025-01-06"},
+		{"2025-01-06", "不正な日付", 7, "2025-01-06"},
+	} {
+		if got := detect.NewsFrom(tt.dateOld, tt.dateNew, tt.days); got != tt.want {
+			t.Errorf("NewsFrom(%q, %q, %d) = %s, want %s", tt.dateOld, tt.dateNew, tt.days, got, tt.want)
+		}
+	}
+}
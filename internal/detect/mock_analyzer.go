@@ -1,32 +1,92 @@
 package detect
 
 import (
+	"fmt"
 	fetcher "gasinsight/internal/fetch"
 	"log"
+	"strings"
 )
 
+// MockPromptVersion モック分析のバージョン（プロンプトを使わないため固定値）
+const MockPromptVersion = "mock@v1"
+
 // MockAnalyzeNews モックニュース分析（API不要）
 func MockAnalyzeNews(article fetcher.NewsArticle) (*AnalyzedNews, error) {
 	log.Printf("🧪 モック分析を使用: %s", article.Title)
 
 	// タイトルに基づいて感情を自動判定
-	sentiment := "ニュートラル"
+	sentiment, score := SentimentNeutral, 0.0
 	if containsKeyword(article.Title, []string{"上昇", "増加", "高騰", "緊張", "リスク"}) {
-		sentiment = "ネガティブ"
+		sentiment, score = SentimentNegative, -0.6
 	} else if containsKeyword(article.Title, []string{"補助", "軽減", "普及", "好調"}) {
-		sentiment = "ポジティブ"
+		sentiment, score = SentimentPositive, 0.6
 	}
 
 	// モック要約を生成
-	summary := "【要約】\n" + article.Content + "\n\n【感情分析】\n" + sentiment + "\n\n【ガソリン価格への影響】\n中"
-
-	return &AnalyzedNews{
-		Title:     article.Title,
-		Summary:   summary,
-		Sentiment: sentiment,
-		URL:       article.URL,
-		Date:      article.Date,
-	}, nil
+	summary := "【要約】\n" + article.Content + "\n\n【感情分析】\n" + sentiment.Label() + "\n\n【ガソリン価格への影響】\n中"
+
+	analyzed := &AnalyzedNews{
+		Title:          article.Title,
+		Summary:        summary,
+		Sentiment:      sentiment,
+		SentimentScore: score,
+		ImpactLevel:    "中",
+		Confidence:     0.3, // キーワード判定のため低め
+		URL:            article.URL,
+		Date:           article.Date,
+		Source:         article.Source,
+		Language:       article.Language,
+		Model:          "mock",
+		PromptVersion:  MockPromptVersion,
+	}
+	analyzed.Normalize()
+	return analyzed, nil
+}
+
+// MockAnalyzePriceChange モック価格変動分析（API不要）
+// 為替の動きと、期間中のネガティブ（値上がり要因）/ポジティブ（値下がり要因）ニュースから定型レポートを作成
+func MockAnalyzePriceChange(in PriceChangeContext) (string, error) {
+	log.Printf("🧪 モック価格変動分析を使用: %s %s→%s", in.Region, in.DateOld, in.DateNew)
+
+	var b strings.Builder
+	diff := in.NewPrice - in.OldPrice
+	fmt.Fprintf(&b, "ガソリン価格は%.2f円から%.2f円へ%+.2f円（%+.2f%%）変動しました。\n", in.OldPrice, in.NewPrice, diff, in.PctChange)
+
+	if in.HasFX() {
+		fxDiff := in.USDJPYNew - in.USDJPYOld
+		fmt.Fprintf(&b, "同期間のUSD/JPYは%.2f円→%.2f円（%+.2f円）", in.USDJPYOld, in.USDJPYNew, fxDiff)
+		switch {
+		case fxDiff > 0 && diff > 0:
+			b.WriteString("で円安が進んでおり、輸入コスト増が値上がり要因と考えられます。\n")
+		case fxDiff < 0 && diff < 0:
+			b.WriteString("で円高が進んでおり、輸入コスト減が値下がり要因と考えられます。\n")
+		default:
+			b.WriteString("で、為替は価格変動の主因ではないと考えられます。\n")
+		}
+	}
+
+	// 値上がりならネガティブ、値下がりならポジティブなニュースを関連候補とする
+	want := SentimentNegative
+	if diff < 0 {
+		want = SentimentPositive
+	}
+	var related []*AnalyzedNews
+	for _, n := range in.News {
+		if n.Sentiment == want {
+			related = append(related, n)
+		}
+	}
+
+	if len(related) == 0 {
+		b.WriteString("関連するニュースは見当たりませんでした。")
+		return b.String(), nil
+	}
+
+	b.WriteString("関連する可能性のあるニュース:\n")
+	for i, n := range related {
+		fmt.Fprintf(&b, "%d. %s (%s)\n", i+1, n.Title, n.URL)
+	}
+	return b.String(), nil
 }
 
 // containsKeyword タイトルにキーワードが含まれているか確認
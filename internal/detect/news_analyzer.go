@@ -3,45 +3,118 @@ package detect
 import (
 	"context"
 	fetcher "gasinsight/internal/fetch"
+	"gasinsight/internal/prompt"
 	"os"
+	"strings"
+	"time"
 
 	"github.com/sashabaranov/go-openai"
 )
 
 type AnalyzedNews struct {
-	Title       string
-	Summary     string
-	Sentiment   string
-	ImpactLevel string // ガソリン価格への影響（大・中・小・なし）
-	URL         string
-	Date        string
+	ID             string // DB上のID（保存済みのニュースのみ）
+	Title          string
+	Summary        string
+	Sentiment      Sentiment      // 感情（positive/neutral/negative）
+	SentimentScore float64        // 感情スコア（-1〜1、負ほど値上がり要因）
+	ImpactLevel    string         // ガソリン価格への影響（大・中・小・なし）
+	ImpactScore    float64        // 影響度スコア（0〜1）
+	Direction      PriceDirection // 示唆される価格の方向（up/down/flat）
+	Confidence     float64        // 判定の確信度（0〜1）
+	URL            string
+	Date           string
+	Source         string // 取得元（newsapi, RSSフィード名など）
+	Language       string // 記事の言語（ja/en）
+	Model          string // 分析に使用したモデル
+	PromptVersion  string // 分析に使用したプロンプト（例: news_analysis@v1）
+	CreatedAt      int64  // DBへの保存日時（保存済みのニュースのみ）
 }
 
-func AnalyzeNewsWithOpenAI(article fetcher.NewsArticle) (*AnalyzedNews, error) {
-	prompt := "以下のニュースを要約して、感情をポジティブ・ニュートラル・ネガティブで判定してください：\n\n" + article.Content
+// DefaultOpenAIModel デフォルトのOpenAIモデル
+const DefaultOpenAIModel = "gpt-4"
 
-	client := openai.NewClient(os.Getenv("OPENAI_API_KEY"))
-	resp, err := client.CreateChatCompletion(context.Background(), openai.ChatCompletionRequest{
-		Model: "gpt-4",
+func analyzeNewsWithOpenAI(article fetcher.NewsArticle, model string, tmpl *prompt.Template, meter *UsageMeter) (*AnalyzedNews, error) {
+	text, err := tmpl.Render(article)
+	if err != nil {
+		return nil, err
+	}
+
+	content, err := openAIComplete(context.Background(), "news", model, tmpl.ID(), text, meter)
+	if err != nil {
+		return nil, err
+	}
+
+	analyzed := &AnalyzedNews{
+		Title:         article.Title,
+		Summary:       content,
+		URL:           article.URL,
+		Date:          article.Date,
+		Source:        article.Source,
+		Language:      article.Language,
+		Model:         model,
+		PromptVersion: tmpl.ID(),
+	}
+	parseAnalysis(content).apply(analyzed)
+	return analyzed, nil
+}
+
+// analyzePriceChangeWithOpenAI ガソリン価格の変動要因をOpenAI APIで分析する
+func analyzePriceChangeWithOpenAI(ctx context.Context, in PriceChangeContext, model string, tmpl *prompt.Template, meter *UsageMeter) (string, error) {
+	text, err := tmpl.Render(in)
+	if err != nil {
+		return "", err
+	}
+	return openAIComplete(ctx, "price_change", model, tmpl.ID(), text, meter)
+}
+
+// openAIComplete プロンプトを送信して回答を取得し、利用量を記録
+// meterがnilでなければ1日の上限を超えている場合は呼び出さない
+func openAIComplete(ctx context.Context, operation, model, promptVersion, text string, meter *UsageMeter) (string, error) {
+	if err := meter.Check(); err != nil {
+		return "", err
+	}
+
+	// OPENAI_BASE_URLを指定するとOpenAI互換のローカルサーバー等を使用できる
+	config := openai.DefaultConfig(os.Getenv("OPENAI_API_KEY"))
+	if baseURL := os.Getenv("OPENAI_BASE_URL"); baseURL != "" {
+		config.BaseURL = baseURL
+	}
+	client := openai.NewClientWithConfig(config)
+	start := time.Now()
+	resp, err := client.CreateChatCompletion(ctx, openai.ChatCompletionRequest{
+		Model: model,
 		Messages: []openai.ChatCompletionMessage{
-			{Role: "user", Content: prompt},
+			{Role: "user", Content: text},
 		},
 	})
+	usage := &LLMUsage{
+		Backend:        "openai",
+		Model:          model,
+		PromptVersion:  promptVersion,
+		Operation:      operation,
+		PromptTokens:   resp.Usage.PromptTokens,
+		ResponseTokens: resp.Usage.CompletionTokens,
+		Latency:        time.Since(start),
+	}
 	if err != nil {
-		return nil, err
+		usage.Error = err.Error()
+	}
+	meter.Record(usage)
+	if err != nil {
+		return "", err
 	}
 
 	// レスポンスの最初の選択肢のメッセージを取得
-	content := ""
-	if len(resp.Choices) > 0 {
-		content = resp.Choices[0].Message.Content
-	}
-
-	return &AnalyzedNews{
-		Title:     article.Title,
-		Summary:   content,
-		Sentiment: "Neutral", // 必要に応じて解析して上書き
-		URL:       article.URL,
-		Date:      article.Date,
-	}, nil
+	if len(resp.Choices) == 0 {
+		return "", nil
+	}
+	return resp.Choices[0].Message.Content, nil
+}
+
+// SummaryText 回答のうち【要約】の部分（見出しがなければ全文）
+func (n *AnalyzedNews) SummaryText() string {
+	if s := section(n.Summary, "要約"); s != "" {
+		return s
+	}
+	return strings.TrimSpace(n.Summary)
 }
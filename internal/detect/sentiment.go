@@ -0,0 +1,226 @@
+package detect
+
+import (
+	"math"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// Sentiment ニュースの感情（ドライバー視点。値上がり要因はnegative）
+type Sentiment string
+
+const (
+	SentimentPositive Sentiment = "positive"
+	SentimentNeutral  Sentiment = "neutral"
+	SentimentNegative Sentiment = "negative"
+)
+
+// Label 表示用の日本語ラベル
+func (s Sentiment) Label() string {
+	switch s {
+	case SentimentPositive:
+		return "ポジティブ"
+	case SentimentNegative:
+		return "ネガティブ"
+	}
+	return "ニュートラル"
+}
+
+// NormalizeSentiment バックエンドごとの表記（ポジティブ / Positive など）を正規の値に揃える
+func NormalizeSentiment(s string) Sentiment {
+	v := strings.ToLower(strings.TrimSpace(s))
+	switch {
+	case strings.Contains(v, "ポジティブ") || strings.Contains(v, "positive"):
+		return SentimentPositive
+	case strings.Contains(v, "ネガティブ") || strings.Contains(v, "negative"):
+		return SentimentNegative
+	}
+	return SentimentNeutral
+}
+
+// PriceDirection ニュースが示唆するガソリン価格の方向
+type PriceDirection string
+
+const (
+	DirectionUp   PriceDirection = "up"
+	DirectionDown PriceDirection = "down"
+	DirectionFlat PriceDirection = "flat"
+)
+
+// NormalizeDirection 上昇 / down などの表記を正規の値に揃える（判定できなければ空）
+func NormalizeDirection(s string) PriceDirection {
+	v := strings.ToLower(strings.TrimSpace(s))
+	switch {
+	case strings.Contains(v, "上昇") || strings.Contains(v, "値上がり") || hasWord(v, "up"):
+		return DirectionUp
+	case strings.Contains(v, "下落") || strings.Contains(v, "値下がり") || hasWord(v, "down"):
+		return DirectionDown
+	case strings.Contains(v, "横ばい") || hasWord(v, "flat"):
+		return DirectionFlat
+	}
+	return ""
+}
+
+// hasWord 小文字の英単語wordが単語として含まれるか（supplyのupは含まない）
+func hasWord(v, word string) bool {
+	for _, w := range strings.FieldsFunc(v, func(r rune) bool { return r < 'a' || r > 'z' }) {
+		if w == word {
+			return true
+		}
+	}
+	return false
+}
+
+// ImpactLevels 影響度のラベル（大きい順）
+var ImpactLevels = []string{"大", "中", "小", "なし"}
+
+// impactScores ラベルしか得られない場合の影響度スコア
+var impactScores = map[string]float64{"大": 0.9, "中": 0.6, "小": 0.3, "なし": 0}
+
+// NormalizeImpactLevel high / 中 などの表記を 大・中・小・なし に揃える
+func NormalizeImpactLevel(s string) string {
+	switch strings.ToLower(strings.TrimSpace(s)) {
+	case "大", "high":
+		return "大"
+	case "中", "medium":
+		return "中"
+	case "小", "low":
+		return "小"
+	}
+	return "なし"
+}
+
+// Normalize 感情・影響度を正規の値に揃え、値が0のスコアはラベルから補完する
+func (n *AnalyzedNews) Normalize() {
+	n.normalize(n.SentimentScore != 0, n.ImpactScore != 0, n.Confidence != 0)
+}
+
+// normalize 感情・影響度を正規の値に揃え、得られなかったスコアはラベルから補完する
+// 明示的に0と回答されたスコアはそのまま使う
+func (n *AnalyzedNews) normalize(hasSentimentScore, hasImpactScore, hasConfidence bool) {
+	n.Sentiment = NormalizeSentiment(string(n.Sentiment))
+	n.ImpactLevel = NormalizeImpactLevel(n.ImpactLevel)
+
+	if !hasSentimentScore {
+		switch n.Sentiment {
+		case SentimentPositive:
+			n.SentimentScore = 0.5
+		case SentimentNegative:
+			n.SentimentScore = -0.5
+		}
+	}
+	if !hasImpactScore {
+		n.ImpactScore = impactScores[n.ImpactLevel]
+	}
+	if n.Direction == "" {
+		// ドライバー視点のため、ネガティブ（値上がり要因）は上昇を示唆する
+		switch n.Sentiment {
+		case SentimentNegative:
+			n.Direction = DirectionUp
+		case SentimentPositive:
+			n.Direction = DirectionDown
+		default:
+			n.Direction = DirectionFlat
+		}
+	}
+	if !hasConfidence {
+		n.Confidence = 0.5
+	}
+
+	n.SentimentScore = clamp(n.SentimentScore, -1, 1)
+	n.ImpactScore = clamp(n.ImpactScore, 0, 1)
+	n.Confidence = clamp(n.Confidence, 0, 1)
+}
+
+func clamp(v, lo, hi float64) float64 {
+	if math.IsNaN(v) {
+		return 0
+	}
+	return math.Max(lo, math.Min(hi, v))
+}
+
+// analysisFields LLMの回答から抽出した項目
+type analysisFields struct {
+	Sentiment      Sentiment
+	ImpactLevel    string
+	SentimentScore float64
+	ImpactScore    float64
+	Direction      PriceDirection
+	Confidence     float64
+
+	// スコアの見出しと数値が回答にあったか（0と未回答を区別する）
+	HasSentimentScore bool
+	HasImpactScore    bool
+	HasConfidence     bool
+}
+
+// apply 抽出した項目を設定して正規化
+func (f analysisFields) apply(n *AnalyzedNews) {
+	n.Sentiment = f.Sentiment
+	n.ImpactLevel = f.ImpactLevel
+	n.SentimentScore = f.SentimentScore
+	n.ImpactScore = f.ImpactScore
+	n.Direction = f.Direction
+	n.Confidence = f.Confidence
+	n.normalize(f.HasSentimentScore, f.HasImpactScore, f.HasConfidence)
+}
+
+var numberPattern = regexp.MustCompile(`[-+−]?\d+(?:\.\d+)?`)
+
+// parseAnalysis 【見出し】形式の回答から各項目を抽出
+// 見出しが見つからない場合は回答全体から判定する（旧形式のプロンプト向け）
+func parseAnalysis(text string) analysisFields {
+	var f analysisFields
+
+	sentimentText := section(text, "感情分析")
+	if sentimentText == "" {
+		sentimentText = text
+	}
+	f.Sentiment = NormalizeSentiment(sentimentText)
+
+	impactText := section(text, "ガソリン価格への影響")
+	if impactText == "" {
+		impactText = text
+	}
+	f.ImpactLevel = "なし"
+	first := len(impactText) + 1
+	for _, level := range ImpactLevels {
+		if i := strings.Index(impactText, level); i >= 0 && i < first {
+			f.ImpactLevel, first = level, i
+		}
+	}
+
+	f.SentimentScore, f.HasSentimentScore = parseNumber(section(text, "感情スコア"))
+	f.ImpactScore, f.HasImpactScore = parseNumber(section(text, "影響度スコア"))
+	f.Direction = NormalizeDirection(section(text, "価格の方向"))
+	f.Confidence, f.HasConfidence = parseNumber(section(text, "確信度"))
+	return f
+}
+
+// section 【name】の直後から次の【までのテキスト
+func section(text, name string) string {
+	header := "【" + name + "】"
+	i := strings.Index(text, header)
+	if i < 0 {
+		return ""
+	}
+	rest := text[i+len(header):]
+	if j := strings.Index(rest, "【"); j >= 0 {
+		rest = rest[:j]
+	}
+	return strings.TrimSpace(rest)
+}
+
+// parseNumber テキスト中の最初の数値（見つからなければfalse）
+func parseNumber(s string) (float64, bool) {
+	m := numberPattern.FindString(s)
+	if m == "" {
+		return 0, false
+	}
+	v, err := strconv.ParseFloat(strings.Replace(m, "−", "-", 1), 64)
+	if err != nil {
+		return 0, false
+	}
+	return v, true
+}
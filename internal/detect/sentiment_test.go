@@ -0,0 +1,50 @@
+package detect
+
+import "testing"
+
+func TestNormalizeDirection(t *testing.T) {
+	for in, want := range map[string]PriceDirection{
+		"上昇":              DirectionUp,
+		"Up":              DirectionUp,
+		"prices go up.":   DirectionUp,
+		"値下がり":            DirectionDown,
+		"down":            DirectionDown,
+		"横ばい":             DirectionFlat,
+		"Flat":            DirectionFlat,
+		"supply":          "",
+		"supply shortage": "",
+		"downstream":      "",
+		"":                "",
+	} {
+		if got := NormalizeDirection(in); got != want {
+			t.Errorf("NormalizeDirection(%q) = %q, want %q", in, got, want)
+		}
+	}
+}
+
+func TestParseAnalysisKeepsExplicitZero(t *testing.T) {
+	text := "【感情分析】\nネガティブ\n\n【ガソリン価格への影響】\n中\n\n【感情スコア】\n0\n\n【影響度スコア】\n0.0\n\n【価格の方向】\nup\n\n【確信度】\n0"
+	var n AnalyzedNews
+	parseAnalysis(text).apply(&n)
+
+	if n.SentimentScore != 0 || n.ImpactScore != 0 || n.Confidence != 0 {
+		t.Errorf("明示的な0が補完されています: 感情%.2f 影響度%.2f 確信度%.2f", n.SentimentScore, n.ImpactScore, n.Confidence)
+	}
+	if n.Sentiment != SentimentNegative || n.ImpactLevel != "中" || n.Direction != DirectionUp {
+		t.Errorf("ラベル = %s/%s/%s", n.Sentiment, n.ImpactLevel, n.Direction)
+	}
+}
+
+func TestParseAnalysisFillsMissingScores(t *testing.T) {
+	// スコアの見出しがない旧形式、数値のない見出し
+	for _, text := range []string{
+		"【感情分析】\nネガティブ\n\n【ガソリン価格への影響】\n中",
+		"【感情分析】\nネガティブ\n\n【ガソリン価格への影響】\n中\n\n【感情スコア】\n不明\n\n【確信度】\n-",
+	} {
+		var n AnalyzedNews
+		parseAnalysis(text).apply(&n)
+		if n.SentimentScore != -0.5 || n.ImpactScore != 0.6 || n.Confidence != 0.5 || n.Direction != DirectionUp {
+			t.Errorf("補完 = 感情%.2f 影響度%.2f 確信度%.2f 方向%s", n.SentimentScore, n.ImpactScore, n.Confidence, n.Direction)
+		}
+	}
+}
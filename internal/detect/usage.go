@@ -0,0 +1,115 @@
+package detect
+
+import (
+	"errors"
+	"fmt"
+	"log"
+	"strings"
+	"time"
+
+	"gasinsight/internal/timeseries"
+)
+
+// ErrBudgetExceeded 1日の利用上限（コスト）を超えたため分析を停止した
+var ErrBudgetExceeded = errors.New("LLMの1日の利用上限に達しました")
+
+// LLMUsage 1回のLLM呼び出しの利用量
+type LLMUsage struct {
+	Backend        string        `json:"backend"`
+	Model          string        `json:"model"`
+	PromptVersion  string        `json:"prompt_version"`
+	Operation      string        `json:"operation"` // news / price_change
+	PromptTokens   int           `json:"prompt_tokens"`
+	ResponseTokens int           `json:"response_tokens"`
+	Latency        time.Duration `json:"latency"`
+	CostUSD        float64       `json:"cost_usd"` // 推定コスト（ドル）
+	Error          string        `json:"error,omitempty"`
+	CreatedAt      time.Time     `json:"created_at"`
+}
+
+// ModelPrice 100万トークンあたりの料金（ドル）
+type ModelPrice struct {
+	Input  float64
+	Output float64
+}
+
+// ModelPrices モデルごとの料金（公開価格の目安。ローカルモデル等の未登録モデルは0として扱う）
+var ModelPrices = map[string]ModelPrice{
+	"gemini-2.0-flash-lite": {Input: 0.075, Output: 0.30},
+	"gemini-2.0-flash":      {Input: 0.10, Output: 0.40},
+	"gemini-1.5-flash":      {Input: 0.075, Output: 0.30},
+	"gemini-1.5-pro":        {Input: 1.25, Output: 5.00},
+	"gpt-4":                 {Input: 30.00, Output: 60.00},
+	"gpt-4o":                {Input: 2.50, Output: 10.00},
+	"gpt-4o-mini":           {Input: 0.15, Output: 0.60},
+}
+
+// EstimateCost トークン数から推定コスト（ドル）を計算
+// 日付付きのモデル名（gpt-4o-2024-08-06 など）は最長一致する登録名の料金を使う
+func EstimateCost(model string, promptTokens, responseTokens int) float64 {
+	price, ok := ModelPrices[model]
+	if !ok {
+		best := ""
+		for name := range ModelPrices {
+			if strings.HasPrefix(model, name) && len(name) > len(best) {
+				best = name
+			}
+		}
+		price = ModelPrices[best]
+	}
+	return (float64(promptTokens)*price.Input + float64(responseTokens)*price.Output) / 1e6
+}
+
+// UsageStore 利用量の保存先
+type UsageStore interface {
+	SaveLLMUsage(u *LLMUsage) error
+	GetLLMCostSince(since time.Time) (float64, error)
+}
+
+// UsageMeter LLM呼び出しの利用量を記録し、1日の上限を管理する
+type UsageMeter struct {
+	store       UsageStore
+	dailyBudget float64 // 1日（日本時間）のコスト上限（ドル）。0以下なら無制限
+}
+
+// NewUsageMeter 利用量メーターを作成
+func NewUsageMeter(store UsageStore, dailyBudget float64) *UsageMeter {
+	return &UsageMeter{store: store, dailyBudget: dailyBudget}
+}
+
+// Check 本日の利用コストが上限を超えていればErrBudgetExceededを返す
+func (m *UsageMeter) Check() error {
+	if m == nil || m.dailyBudget <= 0 {
+		return nil
+	}
+
+	// 日次の上限は日本時間で区切る
+	now := time.Now().In(timeseries.JST)
+	midnight := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, timeseries.JST)
+	spent, err := m.store.GetLLMCostSince(midnight)
+	if err != nil {
+		return fmt.Errorf("利用量の取得エラー: %w", err)
+	}
+	if spent >= m.dailyBudget {
+		return fmt.Errorf("%w（本日 $%.4f / 上限 $%.4f）", ErrBudgetExceeded, spent, m.dailyBudget)
+	}
+	return nil
+}
+
+// Record 利用量を保存（コスト未設定ならモデルの料金から推定）
+func (m *UsageMeter) Record(u *LLMUsage) {
+	if m == nil {
+		return
+	}
+	if u.CostUSD == 0 {
+		u.CostUSD = EstimateCost(u.Model, u.PromptTokens, u.ResponseTokens)
+	}
+	if u.CreatedAt.IsZero() {
+		u.CreatedAt = time.Now()
+	}
+	log.Printf("📈 LLM利用量: %s/%s 入力%dトークン 出力%dトークン %dms $%.6f",
+		u.Backend, u.Model, u.PromptTokens, u.ResponseTokens, u.Latency.Milliseconds(), u.CostUSD)
+	if err := m.store.SaveLLMUsage(u); err != nil {
+		log.Printf("⚠️  利用量の保存エラー: %v", err)
+	}
+}
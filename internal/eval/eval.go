@@ -0,0 +1,316 @@
+// Package eval ラベル付きの記事データで分析器の精度を評価する
+package eval
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+	"time"
+
+	"gasinsight/internal/detect"
+	fetcher "gasinsight/internal/fetch"
+)
+
+// Example 正解ラベル付きの記事（JSONLの1行）
+type Example struct {
+	ID        string `json:"id"`
+	Title     string `json:"title"`
+	Content   string `json:"content"`
+	URL       string `json:"url,omitempty"`
+	Language  string `json:"language,omitempty"`
+	Sentiment string `json:"sentiment"` // 正解の感情（ポジティブ/ニュートラル/ネガティブ）
+	Impact    string `json:"impact"`    // 正解の影響度（大/中/小/なし）
+}
+
+// Article 分析器に渡す記事に変換
+func (e Example) Article() fetcher.NewsArticle {
+	return fetcher.NewsArticle{
+		Title:    e.Title,
+		Content:  e.Content,
+		URL:      e.URL,
+		Source:   "eval",
+		Language: e.Language,
+	}
+}
+
+// LoadGoldenSet JSONL形式の評価データを読み込む（空行と#で始まる行は無視）
+func LoadGoldenSet(path string) ([]Example, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, fmt.Errorf("評価データ読み込みエラー: %w", err)
+	}
+	defer f.Close()
+
+	var examples []Example
+	ids := map[string]bool{}
+	sc := bufio.NewScanner(f)
+	sc.Buffer(make([]byte, 1024*1024), 16*1024*1024)
+	for line := 1; sc.Scan(); line++ {
+		text := strings.TrimSpace(sc.Text())
+		if text == "" || strings.HasPrefix(text, "#") {
+			continue
+		}
+		var e Example
+		if err := json.Unmarshal([]byte(text), &e); err != nil {
+			return nil, fmt.Errorf("評価データ解析エラー (%s:%d): %w", path, line, err)
+		}
+		if e.ID == "" {
+			e.ID = fmt.Sprintf("line-%d", line)
+		}
+		if ids[e.ID] {
+			return nil, fmt.Errorf("評価データのIDが重複しています (%s:%d): %s", path, line, e.ID)
+		}
+		ids[e.ID] = true
+		e.Sentiment = NormalizeSentiment(e.Sentiment)
+		e.Impact = NormalizeImpact(e.Impact)
+		examples = append(examples, e)
+	}
+	if err := sc.Err(); err != nil {
+		return nil, fmt.Errorf("評価データ読み込みエラー: %w", err)
+	}
+	if len(examples) == 0 {
+		return nil, fmt.Errorf("評価データが空です: %s", path)
+	}
+	return examples, nil
+}
+
+// SentimentLabels 感情ラベル
+var SentimentLabels = []string{
+	string(detect.SentimentPositive),
+	string(detect.SentimentNeutral),
+	string(detect.SentimentNegative),
+}
+
+// ImpactLabels 影響度ラベル
+var ImpactLabels = detect.ImpactLevels
+
+// NormalizeSentiment 英語・日本語の表記ゆれを感情ラベルに揃える
+func NormalizeSentiment(s string) string {
+	return string(detect.NormalizeSentiment(s))
+}
+
+// NormalizeImpact 英語・日本語の表記ゆれを影響度ラベルに揃える
+func NormalizeImpact(s string) string {
+	return detect.NormalizeImpactLevel(s)
+}
+
+// Prediction 1件の評価結果
+type Prediction struct {
+	ID            string `json:"id"`
+	Title         string `json:"title"`
+	WantSentiment string `json:"want_sentiment"`
+	GotSentiment  string `json:"got_sentiment"`
+	WantImpact    string `json:"want_impact"`
+	GotImpact     string `json:"got_impact"`
+	Error         string `json:"error,omitempty"` // 分析に失敗した場合のエラー
+}
+
+// SentimentCorrect 感情が正解と一致したか
+func (p *Prediction) SentimentCorrect() bool {
+	return p.Error == "" && p.GotSentiment == p.WantSentiment
+}
+
+// ImpactCorrect 影響度が正解と一致したか
+func (p *Prediction) ImpactCorrect() bool {
+	return p.Error == "" && p.GotImpact == p.WantImpact
+}
+
+// Confusion 混同行列（Counts[正解][予測] = 件数）
+type Confusion struct {
+	Labels []string                  `json:"labels"`
+	Counts map[string]map[string]int `json:"counts"`
+}
+
+// Metrics 1つのラベル種別の評価指標
+type Metrics struct {
+	N         int       `json:"n"`        // 分析に成功した件数
+	Correct   int       `json:"correct"`  // 正解数
+	Accuracy  float64   `json:"accuracy"` // 正解率（失敗した件は分母に含めない）
+	Confusion Confusion `json:"confusion"`
+}
+
+// Run 評価の実行結果
+type Run struct {
+	Analyzer      string        `json:"analyzer"`
+	Model         string        `json:"model"`
+	PromptVersion string        `json:"prompt_version"`
+	Dataset       string        `json:"dataset"`
+	StartedAt     string        `json:"started_at"`
+	Duration      string        `json:"duration"`
+	Errors        int           `json:"errors"`
+	Sentiment     Metrics       `json:"sentiment"`
+	Impact        Metrics       `json:"impact"`
+	Predictions   []*Prediction `json:"predictions"`
+}
+
+// Label 実行結果の識別用ラベル
+func (r *Run) Label() string {
+	return fmt.Sprintf("%s/%s/%s (%s)", r.Analyzer, r.Model, r.PromptVersion, r.StartedAt)
+}
+
+// Evaluate 全ての例に分析器を適用して精度を集計
+func Evaluate(analyzer detect.NewsAnalyzer, dataset string, examples []Example) *Run {
+	start := time.Now()
+	run := &Run{
+		Analyzer:      analyzer.Name(),
+		Model:         analyzer.Model(),
+		PromptVersion: analyzer.PromptVersion(),
+		Dataset:       dataset,
+		StartedAt:     start.Format(time.RFC3339),
+	}
+
+	for _, e := range examples {
+		p := &Prediction{ID: e.ID, Title: e.Title, WantSentiment: e.Sentiment, WantImpact: e.Impact}
+		analyzed, err := analyzer.Analyze(e.Article())
+		if err != nil {
+			p.Error = err.Error()
+			run.Errors++
+		} else {
+			p.GotSentiment = NormalizeSentiment(string(analyzed.Sentiment))
+			p.GotImpact = NormalizeImpact(analyzed.ImpactLevel)
+		}
+		run.Predictions = append(run.Predictions, p)
+	}
+
+	run.Duration = time.Since(start).Round(time.Millisecond).String()
+	run.Sentiment = score(run.Predictions, SentimentLabels,
+		func(p *Prediction) (string, string) { return p.WantSentiment, p.GotSentiment })
+	run.Impact = score(run.Predictions, ImpactLabels,
+		func(p *Prediction) (string, string) { return p.WantImpact, p.GotImpact })
+	return run
+}
+
+// score 正解率と混同行列を計算（想定外のラベルは行列の末尾に追加する）
+func score(preds []*Prediction, labels []string, pick func(*Prediction) (want, got string)) Metrics {
+	m := Metrics{Confusion: Confusion{Labels: append([]string(nil), labels...), Counts: map[string]map[string]int{}}}
+	known := map[string]bool{}
+	for _, l := range labels {
+		known[l] = true
+	}
+
+	for _, p := range preds {
+		if p.Error != "" {
+			continue
+		}
+		want, got := pick(p)
+		for _, l := range []string{want, got} {
+			if !known[l] {
+				known[l] = true
+				m.Confusion.Labels = append(m.Confusion.Labels, l)
+			}
+		}
+		if m.Confusion.Counts[want] == nil {
+			m.Confusion.Counts[want] = map[string]int{}
+		}
+		m.Confusion.Counts[want][got]++
+		m.N++
+		if want == got {
+			m.Correct++
+		}
+	}
+	if m.N > 0 {
+		m.Accuracy = float64(m.Correct) / float64(m.N)
+	}
+	return m
+}
+
+// SaveRun 実行結果をJSONファイルに保存
+func SaveRun(path string, run *Run) error {
+	data, err := json.MarshalIndent(run, "", "  ")
+	if err != nil {
+		return fmt.Errorf("評価結果変換エラー: %w", err)
+	}
+	if err := os.WriteFile(path, data, 0644); err != nil {
+		return fmt.Errorf("評価結果保存エラー: %w", err)
+	}
+	return nil
+}
+
+// LoadRun 保存した実行結果を読み込む
+func LoadRun(path string) (*Run, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("評価結果読み込みエラー: %w", err)
+	}
+	var run Run
+	if err := json.Unmarshal(data, &run); err != nil {
+		return nil, fmt.Errorf("評価結果解析エラー (%s): %w", path, err)
+	}
+	// 日本語ラベルで保存された過去の結果とも比較できるよう揃える
+	for _, p := range run.Predictions {
+		p.WantSentiment = NormalizeSentiment(p.WantSentiment)
+		if p.Error == "" {
+			p.GotSentiment = NormalizeSentiment(p.GotSentiment)
+		}
+	}
+	return &run, nil
+}
+
+// Change 前回の実行から予測が変わった例
+type Change struct {
+	ID    string      `json:"id"`
+	Title string      `json:"title"`
+	Prev  *Prediction `json:"prev"`
+	Cur   *Prediction `json:"cur"`
+}
+
+// Status 変化の種類（fixed: 正解になった, broken: 不正解になった, changed: その他）
+func (c *Change) Status() string {
+	prevOK := c.Prev.SentimentCorrect() && c.Prev.ImpactCorrect()
+	curOK := c.Cur.SentimentCorrect() && c.Cur.ImpactCorrect()
+	switch {
+	case !prevOK && curOK:
+		return "fixed"
+	case prevOK && !curOK:
+		return "broken"
+	}
+	return "changed"
+}
+
+// RunDiff 2つの実行結果の比較
+type RunDiff struct {
+	Prev           string    `json:"prev"`
+	Cur            string    `json:"cur"`
+	SentimentDelta float64   `json:"sentiment_accuracy_delta"`
+	ImpactDelta    float64   `json:"impact_accuracy_delta"`
+	Changes        []*Change `json:"changes"`
+	OnlyPrev       []string  `json:"only_prev,omitempty"` // 前回のみに含まれる例
+	OnlyCur        []string  `json:"only_cur,omitempty"`  // 今回のみに含まれる例
+}
+
+// Diff 前回の実行結果と比較し、予測が変わった例を列挙
+func Diff(prev, cur *Run) *RunDiff {
+	d := &RunDiff{
+		Prev:           prev.Label(),
+		Cur:            cur.Label(),
+		SentimentDelta: cur.Sentiment.Accuracy - prev.Sentiment.Accuracy,
+		ImpactDelta:    cur.Impact.Accuracy - prev.Impact.Accuracy,
+	}
+
+	prevByID := map[string]*Prediction{}
+	for _, p := range prev.Predictions {
+		prevByID[p.ID] = p
+	}
+	seen := map[string]bool{}
+	for _, c := range cur.Predictions {
+		seen[c.ID] = true
+		p, ok := prevByID[c.ID]
+		if !ok {
+			d.OnlyCur = append(d.OnlyCur, c.ID)
+			continue
+		}
+		if p.GotSentiment != c.GotSentiment || p.GotImpact != c.GotImpact || (p.Error == "") != (c.Error == "") {
+			d.Changes = append(d.Changes, &Change{ID: c.ID, Title: c.Title, Prev: p, Cur: c})
+		}
+	}
+	for id := range prevByID {
+		if !seen[id] {
+			d.OnlyPrev = append(d.OnlyPrev, id)
+		}
+	}
+	sort.Strings(d.OnlyPrev)
+	return d
+}
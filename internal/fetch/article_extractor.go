@@ -0,0 +1,241 @@
+package fetcher
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"log"
+	"regexp"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"golang.org/x/net/html"
+	"golang.org/x/net/html/charset"
+)
+
+// DefaultMaxArticleRunes 抽出する本文の最大文字数
+const DefaultMaxArticleRunes = 20000
+
+// maxArticleBytes ダウンロードするHTMLの最大サイズ
+const maxArticleBytes = 5 << 20
+
+// ArticleExtractor 記事URLから本文を抽出（Readability風の簡易実装）
+type ArticleExtractor struct {
+	httpClient *HTTPClient
+	maxRunes   int
+}
+
+// NewArticleExtractor 本文抽出器を作成（maxRunesが0以下ならDefaultMaxArticleRunes）
+func NewArticleExtractor(maxRunes int) *ArticleExtractor {
+	if maxRunes <= 0 {
+		maxRunes = DefaultMaxArticleRunes
+	}
+	return &ArticleExtractor{
+		httpClient: NewHTTPClient(15 * time.Second),
+		maxRunes:   maxRunes,
+	}
+}
+
+// EnrichArticles 各記事のURLから本文を取得し、概要より長ければContentを置き換える
+// 取得に失敗した記事は元の内容のまま残す
+func (e *ArticleExtractor) EnrichArticles(ctx context.Context, articles []NewsArticle) []NewsArticle {
+	for i, a := range articles {
+		if a.URL == "" {
+			continue
+		}
+		body, err := e.Extract(ctx, a.URL)
+		if err != nil {
+			log.Printf("⚠️  本文抽出エラー (%s): %v", a.URL, err)
+			continue
+		}
+		if utf8.RuneCountInString(body) > utf8.RuneCountInString(a.Content) {
+			log.Printf("📄 本文を抽出: %s (%d文字)", a.Title, utf8.RuneCountInString(body))
+			articles[i].Content = body
+		}
+	}
+	return articles
+}
+
+// Extract URLのHTMLを取得して本文を抽出
+func (e *ArticleExtractor) Extract(ctx context.Context, url string) (string, error) {
+	htmlContent, err := e.httpClient.GetWithLimit(ctx, url, maxArticleBytes)
+	if err != nil {
+		return "", err
+	}
+
+	// meta charset（Shift_JIS等）をUTF-8に変換
+	if r, err := charset.NewReader(strings.NewReader(htmlContent), ""); err == nil {
+		if b, err := io.ReadAll(r); err == nil {
+			htmlContent = string(b)
+		}
+	}
+
+	body, err := ExtractArticleText(htmlContent)
+	if err != nil {
+		return "", err
+	}
+	return truncateRunes(body, e.maxRunes), nil
+}
+
+// boilerplateTags 本文に含まれない要素
+var boilerplateTags = map[string]bool{
+	"script": true, "style": true, "noscript": true, "iframe": true, "svg": true,
+	"nav": true, "header": true, "footer": true, "aside": true, "form": true,
+	"button": true, "select": true, "template": true,
+}
+
+// boilerplatePattern class/idがこれに一致する要素は除去
+var boilerplatePattern = regexp.MustCompile(`(?i)(comment|share|social|sns|related|recommend|ranking|banner|advert|\bads?\b|promo|sidebar|side-bar|menu|breadcrumb|footer|header|nav|popup|modal|cookie|subscribe|pagination)`)
+
+// positivePattern class/idがこれに一致する要素は本文の可能性が高い
+var positivePattern = regexp.MustCompile(`(?i)(article|content|entry|main|body|post|story|text|honbun)`)
+
+// ExtractArticleText HTMLから本文テキストを抽出
+//
+// 段落（<p>）の文字数を親・祖父要素に加点し、最もスコアの高い要素の段落を本文とする。
+// リンク文字の割合が高い段落はナビゲーションとみなして除外する。
+func ExtractArticleText(htmlContent string) (string, error) {
+	doc, err := html.Parse(strings.NewReader(htmlContent))
+	if err != nil {
+		return "", fmt.Errorf("HTMLパースエラー: %w", err)
+	}
+
+	removeBoilerplate(doc)
+
+	scores := map[*html.Node]float64{}
+	for _, p := range FindNodesByTag(doc, "p") {
+		text := collapseSpace(textContent(p))
+		n := utf8.RuneCountInString(text)
+		if n < 20 || linkDensity(p) > 0.5 {
+			continue
+		}
+		// 句読点の多い段落ほど本文らしい
+		score := 1 + float64(n)/100 + float64(strings.Count(text, "、")+strings.Count(text, "。")+strings.Count(text, ","))
+		if parent := p.Parent; parent != nil {
+			scores[parent] += score
+			if grand := parent.Parent; grand != nil {
+				scores[grand] += score / 2
+			}
+		}
+	}
+
+	var best *html.Node
+	var bestScore float64
+	for node, score := range scores {
+		if node.Type == html.ElementNode && (node.Data == "article" || node.Data == "main") {
+			score *= 1.5
+		}
+		if positivePattern.MatchString(attr(node, "class") + " " + attr(node, "id")) {
+			score *= 1.25
+		}
+		if score > bestScore {
+			best, bestScore = node, score
+		}
+	}
+
+	if best == nil {
+		// 段落がない場合はbody全体のテキストを使う
+		if body := FindNodesByTag(doc, "body"); len(body) > 0 {
+			if text := collapseSpace(textContent(body[0])); text != "" {
+				return text, nil
+			}
+		}
+		return "", fmt.Errorf("本文が見つかりませんでした")
+	}
+
+	var paragraphs []string
+	var walk func(*html.Node)
+	walk = func(n *html.Node) {
+		if n.Type == html.ElementNode {
+			switch n.Data {
+			case "p", "h2", "h3", "h4", "li", "blockquote":
+				text := collapseSpace(textContent(n))
+				if text != "" && linkDensity(n) <= 0.5 {
+					paragraphs = append(paragraphs, text)
+				}
+				return
+			}
+		}
+		for c := n.FirstChild; c != nil; c = c.NextSibling {
+			walk(c)
+		}
+	}
+	walk(best)
+
+	if len(paragraphs) == 0 {
+		return "", fmt.Errorf("本文が見つかりませんでした")
+	}
+	return strings.Join(paragraphs, "\n"), nil
+}
+
+// removeBoilerplate 本文以外の要素をツリーから取り除く
+func removeBoilerplate(n *html.Node) {
+	for c := n.FirstChild; c != nil; {
+		next := c.NextSibling
+		if c.Type == html.CommentNode {
+			n.RemoveChild(c)
+		} else if c.Type == html.ElementNode {
+			ident := attr(c, "class") + " " + attr(c, "id") + " " + attr(c, "role")
+			if boilerplateTags[c.Data] || (c.Data != "body" && c.Data != "article" && c.Data != "main" && boilerplatePattern.MatchString(ident)) {
+				n.RemoveChild(c)
+			} else {
+				removeBoilerplate(c)
+			}
+		}
+		c = next
+	}
+}
+
+// textContent 要素配下の全テキスト（GetNodeTextと異なり空白を保持する）
+func textContent(n *html.Node) string {
+	var b strings.Builder
+	var walk func(*html.Node)
+	walk = func(n *html.Node) {
+		if n.Type == html.TextNode {
+			b.WriteString(n.Data)
+		}
+		if n.Type == html.ElementNode && n.Data == "br" {
+			b.WriteByte(' ')
+		}
+		for c := n.FirstChild; c != nil; c = c.NextSibling {
+			walk(c)
+		}
+	}
+	walk(n)
+	return b.String()
+}
+
+// linkDensity 要素のテキストのうちリンク内テキストの割合
+func linkDensity(n *html.Node) float64 {
+	total := utf8.RuneCountInString(collapseSpace(textContent(n)))
+	if total == 0 {
+		return 0
+	}
+	var linked int
+	for _, a := range FindNodesByTag(n, "a") {
+		linked += utf8.RuneCountInString(collapseSpace(textContent(a)))
+	}
+	return float64(linked) / float64(total)
+}
+
+func attr(n *html.Node, key string) string {
+	for _, a := range n.Attr {
+		if a.Key == key {
+			return a.Val
+		}
+	}
+	return ""
+}
+
+func collapseSpace(s string) string {
+	return strings.Join(strings.Fields(s), " ")
+}
+
+// truncateRunes 文字数の上限で切り詰める
+func truncateRunes(s string, n int) string {
+	if utf8.RuneCountInString(s) <= n {
+		return s
+	}
+	return string([]rune(s)[:n])
+}
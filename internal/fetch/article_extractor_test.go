@@ -0,0 +1,158 @@
+package fetcher
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"unicode/utf8"
+)
+
+func readFixture(t *testing.T, name string) []byte {
+	t.Helper()
+	b, err := os.ReadFile(filepath.Join("testdata", name))
+	if err != nil {
+		t.Fatalf("fixture読み込みエラー: %v", err)
+	}
+	return b
+}
+
+// serveFixture bodyを返すテスト用サーバーを起動し、そのURLを返す
+func serveFixture(t *testing.T, body []byte) string {
+	t.Helper()
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "text/html")
+		w.Write(body)
+	}))
+	t.Cleanup(srv.Close)
+	return srv.URL
+}
+
+func TestExtractArticleText(t *testing.T) {
+	text, err := ExtractArticleText(string(readFixture(t, "article.html")))
+	if err != nil {
+		t.Fatalf("ExtractArticleText: %v", err)
+	}
+
+	for _, want := range []string{
+		"レギュラーは1リットル175円台に",
+		"全国平均小売価格は1リットル175.3円となり",
+		"輸入コストが膨らんだ",
+		"補助がなければ価格は190円台に達していた",
+	} {
+		if !strings.Contains(text, want) {
+			t.Errorf("本文に %q が含まれていません:\n%s", want, text)
+		}
+	}
+	if got := strings.Count(text, "\n") + 1; got != 4 {
+		t.Errorf("段落数 = %d, want 4:\n%s", got, text)
+	}
+}
+
+func TestExtractArticleTextStripsBoilerplate(t *testing.T) {
+	text, err := ExtractArticleText(string(readFixture(t, "article.html")))
+	if err != nil {
+		t.Fatalf("ExtractArticleText: %v", err)
+	}
+
+	for _, unwanted := range []string{
+		"dataLayer", "track(", "font-family", // script/style
+		"会員登録",     // header
+		"政治",       // nav
+		"トップ",      // breadcrumb
+		"この記事をシェア", // share
+		"ランキング",    // aside
+		"おすすめ記事",   // related
+		"無断転載",     // footer
+		"関連記事",     // リンクだけの段落
+	} {
+		if strings.Contains(text, unwanted) {
+			t.Errorf("本文に %q が残っています:\n%s", unwanted, text)
+		}
+	}
+}
+
+func TestExtractArticleTextNoBody(t *testing.T) {
+	text, err := ExtractArticleText(string(readFixture(t, "no_body.html")))
+	if err == nil {
+		t.Fatalf("本文のないページでエラーになりません: %q", text)
+	}
+}
+
+func TestExtractConvertsShiftJIS(t *testing.T) {
+	raw := readFixture(t, "shift_jis.html")
+	if utf8.Valid(raw) {
+		t.Fatal("fixtureがShift_JISではありません")
+	}
+
+	text, err := NewArticleExtractor(0).Extract(context.Background(), serveFixture(t, raw))
+	if err != nil {
+		t.Fatalf("Extract: %v", err)
+	}
+	if !utf8.ValidString(text) {
+		t.Fatalf("UTF-8に変換されていません: %q", text)
+	}
+	for _, want := range []string{"ニューヨーク原油先物相場", "高値圏での推移が続く"} {
+		if !strings.Contains(text, want) {
+			t.Errorf("本文に %q が含まれていません:\n%s", want, text)
+		}
+	}
+}
+
+// longArticle 1段落100文字のn段落からなる記事
+func longArticle(n int) string {
+	paragraph := "<p>" + strings.Repeat("価格は上昇を続け、", 10) + strings.Repeat("。", 10) + "</p>\n"
+	return "<html><body><article>" + strings.Repeat(paragraph, n) + "</article></body></html>"
+}
+
+func TestExtractTruncatesAtDefaultMaxArticleRunes(t *testing.T) {
+	// 300段落×約100文字で DefaultMaxArticleRunes を超える本文
+	text, err := NewArticleExtractor(0).Extract(context.Background(), serveFixture(t, []byte(longArticle(300))))
+	if err != nil {
+		t.Fatalf("Extract: %v", err)
+	}
+	if got := utf8.RuneCountInString(text); got != DefaultMaxArticleRunes {
+		t.Errorf("文字数 = %d, want %d", got, DefaultMaxArticleRunes)
+	}
+
+	text, err = NewArticleExtractor(50).Extract(context.Background(), serveFixture(t, []byte(longArticle(3))))
+	if err != nil {
+		t.Fatalf("Extract: %v", err)
+	}
+	if got := utf8.RuneCountInString(text); got != 50 {
+		t.Errorf("文字数 = %d, want 50", got)
+	}
+}
+
+func TestExtractOversizedInput(t *testing.T) {
+	// maxArticleBytes を超えるHTMLは先頭だけを読み込み、途中で切れても本文を抽出する
+	padding := strings.Repeat("<div>"+strings.Repeat("x", 1000)+"</div>", maxArticleBytes/1000+10)
+	page := strings.Replace(longArticle(3), "</article>",
+		padding+"<p>上限より後ろの段落は、読み込まれないため本文に含まれない。</p></article>", 1)
+	if len(page) <= maxArticleBytes {
+		t.Fatalf("fixtureが小さすぎます: %d bytes", len(page))
+	}
+
+	text, err := NewArticleExtractor(0).Extract(context.Background(), serveFixture(t, []byte(page)))
+	if err != nil {
+		t.Fatalf("Extract: %v", err)
+	}
+	if !strings.Contains(text, "価格は上昇を続け") {
+		t.Errorf("本文が抽出されていません: %.100q", text)
+	}
+	if strings.Contains(text, "上限より後ろ") {
+		t.Error("maxArticleBytesより後ろの内容が読み込まれています")
+	}
+}
+
+func TestExtractHTTPError(t *testing.T) {
+	srv := httptest.NewServer(http.NotFoundHandler())
+	defer srv.Close()
+
+	if _, err := NewArticleExtractor(0).Extract(context.Background(), srv.URL); err == nil {
+		t.Fatal("404でエラーになりません")
+	}
+}
@@ -12,6 +12,16 @@ func NewMockNewsFetcher() *MockNewsFetcher {
 	return &MockNewsFetcher{}
 }
 
+// Name 取得元の名前
+func (m *MockNewsFetcher) Name() string {
+	return "mock"
+}
+
+// Language 記事の言語
+func (m *MockNewsFetcher) Language() string {
+	return "ja"
+}
+
 func (m *MockNewsFetcher) FetchTopNews(query string) ([]NewsArticle, error) {
 	log.Println("🧪 モックニュースを使用")
 
@@ -7,92 +7,284 @@ import (
 	"log"
 	"net/http"
 	"net/url"
+	"os"
+	"strconv"
+	"strings"
 	"time"
 )
 
 type NewsFetcher struct {
-	apiKey string
-	client *http.Client
+	apiKey  string
+	client  *http.Client
+	baseURL string
+	query   NewsQuery
+	quota   NewsAPIQuota
 }
 
 type NewsArticle struct {
-	Title   string
-	Content string
-	URL     string
-	Date    string
+	Title    string
+	Content  string
+	URL      string
+	Date     string
+	Source   string // 取得元（newsapi, RSSフィード名など）
+	Language string // 記事の言語（ja/en）
+}
+
+// DefaultNewsAPIQuery NewsAPIのデフォルト検索クエリ（英語の方が安定）
+const DefaultNewsAPIQuery = "oil OR gasoline OR economy"
+
+// newsAPIMaxPageSize NewsAPIの1ページあたりの最大件数
+const newsAPIMaxPageSize = 100
+
+// NewsQuery NewsAPIの検索条件
+type NewsQuery struct {
+	Query      string   `json:"query" yaml:"query"`                                 // 検索クエリ（空ならDefaultNewsAPIQuery）
+	From       string   `json:"from,omitempty" yaml:"from,omitempty"`               // 開始日時（YYYY-MM-DD または RFC3339）
+	To         string   `json:"to,omitempty" yaml:"to,omitempty"`                   // 終了日時
+	Language   string   `json:"language,omitempty" yaml:"language,omitempty"`       // 記事の言語（en, de など。NewsAPIは日本語非対応）
+	Domains    []string `json:"domains,omitempty" yaml:"domains,omitempty"`         // 対象ドメイン（例: reuters.com）
+	SortBy     string   `json:"sort_by,omitempty" yaml:"sort_by,omitempty"`         // publishedAt / relevancy / popularity
+	MaxResults int      `json:"max_results,omitempty" yaml:"max_results,omitempty"` // 取得件数の上限（ページングの上限）
+}
+
+// DefaultNewsQuery デフォルトの検索条件（無料枠のクォータを考慮して件数は少なめ）
+func DefaultNewsQuery() NewsQuery {
+	return NewsQuery{
+		Query:      DefaultNewsAPIQuery,
+		Language:   "en",
+		SortBy:     "publishedAt",
+		MaxResults: 3,
+	}
+}
+
+// NewsAPIQuota 直近のレスポンスから読み取ったクォータ情報（不明な値は-1）
+type NewsAPIQuota struct {
+	Limit      int
+	Remaining  int
+	RetryAfter time.Duration
 }
 
 func NewNewsFetcher(apiKey string) *NewsFetcher {
 	return &NewsFetcher{
-		apiKey: apiKey,
-		client: &http.Client{Timeout: 15 * time.Second},
+		apiKey:  apiKey,
+		client:  &http.Client{Timeout: 15 * time.Second},
+		baseURL: "https://newsapi.org/v2/everything",
+		query:   DefaultNewsQuery(),
+		quota:   NewsAPIQuota{Limit: -1, Remaining: -1},
 	}
 }
 
+// WithQuery 検索条件を設定（未指定の項目はデフォルト値を使用）
+func (n *NewsFetcher) WithQuery(q NewsQuery) *NewsFetcher {
+	def := DefaultNewsQuery()
+	if q.Query == "" {
+		q.Query = def.Query
+	}
+	if q.SortBy == "" {
+		q.SortBy = def.SortBy
+	}
+	if q.MaxResults <= 0 {
+		q.MaxResults = def.MaxResults
+	}
+	n.query = q
+	return n
+}
+
+// Quota 直近のリクエストで取得したクォータ情報
+func (n *NewsFetcher) Quota() NewsAPIQuota {
+	return n.quota
+}
+
+// Name 取得元の名前
+func (n *NewsFetcher) Name() string {
+	return "newsapi"
+}
+
+// Language 記事の言語
+func (n *NewsFetcher) Language() string {
+	if n.query.Language != "" {
+		return n.query.Language
+	}
+	return "en"
+}
+
+// FetchTopNews 設定された検索条件でMaxResults件までページングして記事を取得
+// query: 空でなければ検索クエリのみ上書き
 func (n *NewsFetcher) FetchTopNews(query string) ([]NewsArticle, error) {
+	q := n.query
+	if query != "" {
+		q.Query = query
+	}
+
+	log.Printf("🌐 NewsAPI リクエスト中...")
+	log.Printf("   クエリ: %s", q.Query)
+
+	pageSize := q.MaxResults
+	if pageSize > newsAPIMaxPageSize {
+		pageSize = newsAPIMaxPageSize
+	}
+
+	articles := []NewsArticle{}
+	seen := map[string]bool{}
+	for page := 1; len(articles) < q.MaxResults; page++ {
+		if n.quota.Remaining == 0 {
+			log.Printf("⚠️  NewsAPIのクォータが残っていないため取得を中断します")
+			break
+		}
+
+		result, err := n.fetchPage(q, page, pageSize)
+		if err != nil {
+			if len(articles) > 0 {
+				// 途中のページで失敗した場合は取得済みの記事を返す
+				log.Printf("⚠️  %dページ目の取得を中断: %v", page, err)
+				break
+			}
+			return nil, err
+		}
+
+		for _, a := range result.Articles {
+			if seen[a.URL] || len(articles) >= q.MaxResults {
+				continue
+			}
+			seen[a.URL] = true
+			articles = append(articles, NewsArticle{
+				Title:    a.Title,
+				Content:  a.Description,
+				URL:      a.URL,
+				Date:     a.PublishedAt,
+				Source:   n.Name(),
+				Language: n.Language(),
+			})
+		}
+
+		if len(result.Articles) < pageSize || page*pageSize >= result.TotalResults {
+			break
+		}
+	}
+
+	log.Printf("✅ %d件のニュースを取得", len(articles))
+	return articles, nil
+}
+
+type newsAPIResponse struct {
+	Status       string `json:"status"`
+	Code         string `json:"code"`
+	Message      string `json:"message"`
+	TotalResults int    `json:"totalResults"`
+	Articles     []struct {
+		Title       string `json:"title"`
+		Description string `json:"description"`
+		URL         string `json:"url"`
+		PublishedAt string `json:"publishedAt"`
+	} `json:"articles"`
+}
+
+// fetchPage 1ページ分を取得
+func (n *NewsFetcher) fetchPage(q NewsQuery, page, pageSize int) (*newsAPIResponse, error) {
 	// URLパラメータを適切にエンコード
-	baseURL := "https://newsapi.org/v2/everything"
 	params := url.Values{}
-	params.Add("q", query)
-	params.Add("sortBy", "publishedAt")
-	params.Add("pageSize", "3")
-	params.Add("apiKey", n.apiKey)
+	params.Add("q", q.Query)
+	params.Add("sortBy", q.SortBy)
+	params.Add("pageSize", strconv.Itoa(pageSize))
+	params.Add("page", strconv.Itoa(page))
+	if q.From != "" {
+		params.Add("from", q.From)
+	}
+	if q.To != "" {
+		params.Add("to", q.To)
+	}
+	if q.Language != "" {
+		params.Add("language", q.Language)
+	}
+	if len(q.Domains) > 0 {
+		params.Add("domains", strings.Join(q.Domains, ","))
+	}
 
-	fullURL := baseURL + "?" + params.Encode()
+	req, err := http.NewRequest("GET", n.baseURL+"?"+params.Encode(), nil)
+	if err != nil {
+		return nil, fmt.Errorf("リクエスト作成エラー: %w", err)
+	}
+	// APIキーはURLに含めずヘッダーで送る
+	req.Header.Set("X-Api-Key", n.apiKey)
 
-	log.Printf("🌐 NewsAPI リクエスト中...")
-	log.Printf("   クエリ: %s", query)
-	resp, err := n.client.Get(fullURL)
+	resp, err := n.client.Do(req)
 	if err != nil {
 		return nil, fmt.Errorf("HTTPリクエストエラー: %w", err)
 	}
 	defer resp.Body.Close()
 
+	n.updateQuota(resp.Header)
+
 	// レスポンスボディを読み取り
 	body, err := io.ReadAll(resp.Body)
 	if err != nil {
 		return nil, fmt.Errorf("レスポンス読み取りエラー: %w", err)
 	}
 
-	// ステータスコードをチェック
-	if resp.StatusCode != http.StatusOK {
-		log.Printf("❌ HTTPエラー詳細: %s", string(body))
-		return nil, fmt.Errorf("HTTPエラー: status=%d, body=%s", resp.StatusCode, string(body))
-	}
-
-	var result struct {
-		Status   string `json:"status"`
-		Code     string `json:"code"`
-		Message  string `json:"message"`
-		Articles []struct {
-			Title       string `json:"title"`
-			Description string `json:"description"`
-			URL         string `json:"url"`
-			PublishedAt string `json:"publishedAt"`
-		} `json:"articles"`
+	if resp.StatusCode == http.StatusTooManyRequests {
+		n.quota.Remaining = 0
+		return nil, fmt.Errorf("NewsAPIのレート制限に達しました（再試行まで: %s）", n.quota.RetryAfter)
 	}
 
+	var result newsAPIResponse
 	if err := json.Unmarshal(body, &result); err != nil {
+		if resp.StatusCode != http.StatusOK {
+			return nil, fmt.Errorf("HTTPエラー: status=%d, body=%s", resp.StatusCode, string(body))
+		}
 		log.Printf("❌ JSON解析エラー: %s", string(body))
 		return nil, fmt.Errorf("JSONデコードエラー: %w", err)
 	}
 
 	// APIエラーチェック
 	if result.Status == "error" {
+		if result.Code == "rateLimited" {
+			n.quota.Remaining = 0
+		}
 		return nil, fmt.Errorf("NewsAPIエラー: [%s] %s", result.Code, result.Message)
 	}
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("HTTPエラー: status=%d", resp.StatusCode)
+	}
 
-	log.Printf("✅ %d件のニュースを取得", len(result.Articles))
+	return &result, nil
+}
 
-	articles := []NewsArticle{}
-	for _, a := range result.Articles {
-		articles = append(articles, NewsArticle{
-			Title:   a.Title,
-			Content: a.Description,
-			URL:     a.URL,
-			Date:    a.PublishedAt,
-		})
+// updateQuota レスポンスヘッダーからクォータ情報を更新
+func (n *NewsFetcher) updateQuota(h http.Header) {
+	if v, err := strconv.Atoi(h.Get("X-RateLimit-Limit")); err == nil {
+		n.quota.Limit = v
 	}
+	if v, err := strconv.Atoi(h.Get("X-RateLimit-Remaining")); err == nil {
+		n.quota.Remaining = v
+		log.Printf("   NewsAPI残りリクエスト数: %d", v)
+	}
+	if v, err := strconv.Atoi(h.Get("Retry-After")); err == nil {
+		n.quota.RetryAfter = time.Duration(v) * time.Second
+	}
+}
 
-	return articles, nil
+// LoadNewsQueries 名前付きの検索条件をJSONファイルから読み込む
+// ファイルが存在しなければ "default" のみを返す
+func LoadNewsQueries(path string) (map[string]NewsQuery, error) {
+	queries := map[string]NewsQuery{"default": DefaultNewsQuery()}
+	if path == "" {
+		return queries, nil
+	}
+
+	data, err := os.ReadFile(path)
+	if os.IsNotExist(err) {
+		return queries, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("検索条件ファイル読み込みエラー: %w", err)
+	}
+
+	var saved map[string]NewsQuery
+	if err := json.Unmarshal(data, &saved); err != nil {
+		return nil, fmt.Errorf("検索条件ファイル解析エラー (%s): %w", path, err)
+	}
+	for name, q := range saved {
+		queries[name] = q
+	}
+	return queries, nil
 }
@@ -0,0 +1,170 @@
+package fetcher
+
+import (
+	"fmt"
+	"log"
+	"strings"
+	"sync"
+	"unicode"
+)
+
+// NewsSource ニュース取得元のインターフェース
+type NewsSource interface {
+	Name() string     // 取得元の名前（記事のSourceの既定値）
+	Language() string // 記事の言語（記事のLanguageの既定値）
+	FetchTopNews(query string) ([]NewsArticle, error)
+}
+
+// NewsAggregator 複数の取得元から並行してニュースを取得し、重複を除いてまとめる
+type NewsAggregator struct {
+	sources    []NewsSource
+	similarity float64 // タイトル類似度がこの値以上なら同一記事とみなす
+}
+
+// NewNewsAggregator ニュースアグリゲーターを作成
+func NewNewsAggregator(sources ...NewsSource) *NewsAggregator {
+	return &NewsAggregator{
+		sources:    sources,
+		similarity: 0.8,
+	}
+}
+
+// Name 取得元の名前
+func (a *NewsAggregator) Name() string {
+	names := make([]string, len(a.sources))
+	for i, s := range a.sources {
+		names[i] = s.Name()
+	}
+	return strings.Join(names, "+")
+}
+
+// Language 複数言語を含むため空
+func (a *NewsAggregator) Language() string {
+	return ""
+}
+
+// FetchTopNews 全取得元に並行して問い合わせ、取得元の順に結合して重複を除去
+// 一部の取得元が失敗しても、1つでも成功すれば結果を返す
+func (a *NewsAggregator) FetchTopNews(query string) ([]NewsArticle, error) {
+	type result struct {
+		articles []NewsArticle
+		err      error
+	}
+	results := make([]result, len(a.sources))
+
+	var wg sync.WaitGroup
+	for i, src := range a.sources {
+		wg.Add(1)
+		go func(i int, src NewsSource) {
+			defer wg.Done()
+			articles, err := src.FetchTopNews(query)
+			for j := range articles {
+				if articles[j].Source == "" {
+					articles[j].Source = src.Name()
+				}
+				if articles[j].Language == "" {
+					articles[j].Language = src.Language()
+				}
+			}
+			results[i] = result{articles: articles, err: err}
+		}(i, src)
+	}
+	wg.Wait()
+
+	var merged []NewsArticle
+	var errs []string
+	for i, r := range results {
+		if r.err != nil {
+			log.Printf("⚠️  %s の取得に失敗: %v", a.sources[i].Name(), r.err)
+			errs = append(errs, fmt.Sprintf("%s: %v", a.sources[i].Name(), r.err))
+			continue
+		}
+		merged = append(merged, r.articles...)
+	}
+
+	if len(errs) == len(a.sources) && len(a.sources) > 0 {
+		return nil, fmt.Errorf("全ての取得元が失敗しました: %s", strings.Join(errs, "; "))
+	}
+
+	deduped := a.dedupe(merged)
+	if removed := len(merged) - len(deduped); removed > 0 {
+		log.Printf("🧹 重複記事を%d件除外しました", removed)
+	}
+	return deduped, nil
+}
+
+// dedupe URLが同じ、またはタイトルが類似する記事をまとめる（本文が長い方の内容を残す）
+func (a *NewsAggregator) dedupe(articles []NewsArticle) []NewsArticle {
+	var out []NewsArticle
+	var titles []map[string]int
+
+	for _, article := range articles {
+		grams := bigrams(normalizeTitle(article.Title))
+		dup := -1
+		for i, kept := range out {
+			if article.URL != "" && article.URL == kept.URL {
+				dup = i
+				break
+			}
+			if dice(grams, titles[i]) >= a.similarity {
+				dup = i
+				break
+			}
+		}
+
+		if dup < 0 {
+			out = append(out, article)
+			titles = append(titles, grams)
+			continue
+		}
+		if len([]rune(article.Content)) > len([]rune(out[dup].Content)) {
+			out[dup].Content = article.Content
+		}
+	}
+	return out
+}
+
+// normalizeTitle 比較用にタイトルを正規化（末尾の " - 媒体名" を除き、記号・空白を除去）
+func normalizeTitle(title string) string {
+	if i := strings.LastIndex(title, " - "); i > 0 {
+		title = title[:i]
+	}
+	var b strings.Builder
+	for _, r := range strings.ToLower(title) {
+		if unicode.IsLetter(r) || unicode.IsNumber(r) {
+			b.WriteRune(r)
+		}
+	}
+	return b.String()
+}
+
+// bigrams 文字バイグラムの出現回数
+func bigrams(s string) map[string]int {
+	r := []rune(s)
+	m := map[string]int{}
+	if len(r) == 1 {
+		m[s]++
+	}
+	for i := 0; i+1 < len(r); i++ {
+		m[string(r[i:i+2])]++
+	}
+	return m
+}
+
+// dice バイグラムのDice係数（0〜1）
+func dice(a, b map[string]int) float64 {
+	var na, nb, common int
+	for g, c := range a {
+		na += c
+		if d, ok := b[g]; ok {
+			common += min(c, d)
+		}
+	}
+	for _, c := range b {
+		nb += c
+	}
+	if na+nb == 0 {
+		return 0
+	}
+	return 2 * float64(common) / float64(na+nb)
+}
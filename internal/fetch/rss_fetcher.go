@@ -0,0 +1,332 @@
+package fetcher
+
+import (
+	"bytes"
+	"context"
+	"encoding/xml"
+	"fmt"
+	"log"
+	"net/url"
+	"sort"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"gasinsight/internal/timeseries"
+
+	"golang.org/x/net/html"
+	"golang.org/x/net/html/charset"
+)
+
+// FeedConfig RSS/Atomフィードの設定
+type FeedConfig struct {
+	Name     string // 表示名
+	URL      string // フィードURL
+	Language string // 記事の言語（ja/en）
+}
+
+// DefaultFeeds デフォルトで購読する日本語のエネルギー・経済ニュースフィード
+var DefaultFeeds = []FeedConfig{
+	{Name: "経済産業省 ニュースリリース", URL: "https://www.meti.go.jp/ml_index_release_atom.xml", Language: "ja"},
+	{Name: "NHK 経済", URL: "https://www.nhk.or.jp/rss/news/cat5.xml", Language: "ja"},
+}
+
+// DefaultNewsKeywords 燃料・原油・為替に関する記事を抽出するキーワード
+var DefaultNewsKeywords = []string{
+	"ガソリン", "原油", "石油", "燃料", "軽油", "灯油", "補助金", "OPEC", "エネルギー",
+	"為替", "円安", "円高", "ドル円",
+	"oil", "gasoline", "crude", "fuel",
+}
+
+// RSSFetcher RSS/Atomフィードからニュースを取得
+type RSSFetcher struct {
+	httpClient *HTTPClient
+	feeds      []FeedConfig
+	keywords   []string
+	maxItems   int
+}
+
+// NewRSSFetcher RSSフェッチャーを作成（feedsが空ならDefaultFeedsを使用）
+func NewRSSFetcher(feeds []FeedConfig) *RSSFetcher {
+	if len(feeds) == 0 {
+		feeds = DefaultFeeds
+	}
+	return &RSSFetcher{
+		httpClient: NewHTTPClient(15 * time.Second),
+		feeds:      feeds,
+		keywords:   DefaultNewsKeywords,
+		maxItems:   20,
+	}
+}
+
+// Name 取得元の名前
+func (r *RSSFetcher) Name() string {
+	return "rss"
+}
+
+// Language 記事の言語
+func (r *RSSFetcher) Language() string {
+	return "ja"
+}
+
+// ParseFeedList カンマ区切りのフィード指定（"URL" または "名前=URL"）を解析
+func ParseFeedList(s string) []FeedConfig {
+	var feeds []FeedConfig
+	for _, entry := range strings.Split(s, ",") {
+		entry = strings.TrimSpace(entry)
+		if entry == "" {
+			continue
+		}
+		feed := FeedConfig{URL: entry, Language: "ja"}
+		if i := strings.Index(entry, "="); i > 0 && !strings.Contains(entry[:i], "://") {
+			feed.Name, feed.URL = entry[:i], entry[i+1:]
+		}
+		if feed.Name == "" {
+			if u, err := url.Parse(feed.URL); err == nil {
+				feed.Name = u.Host
+			}
+		}
+		feeds = append(feeds, feed)
+	}
+	return feeds
+}
+
+// FetchTopNews 全フィードから記事を取得し、キーワードに一致するものを新しい順に返す
+// query: " OR " またはカンマ区切りのキーワード（空ならデフォルトのキーワード）
+func (r *RSSFetcher) FetchTopNews(query string) ([]NewsArticle, error) {
+	keywords := r.keywords
+	if q := parseKeywords(query); len(q) > 0 {
+		keywords = q
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+	defer cancel()
+
+	var articles []NewsArticle
+	failed := 0
+	for _, feed := range r.feeds {
+		log.Printf("📡 フィード取得中: %s", feed.Name)
+		items, err := r.fetchFeed(ctx, feed)
+		if err != nil {
+			log.Printf("⚠️  フィード取得エラー (%s): %v", feed.Name, err)
+			failed++
+			continue
+		}
+
+		matched := 0
+		for _, a := range items {
+			if matchesKeywords(a.Title+" "+a.Content, keywords) {
+				a.Source = feed.Name
+				a.Language = feed.Language
+				articles = append(articles, a)
+				matched++
+			}
+		}
+		log.Printf("✅ %s: %d件中%d件が該当", feed.Name, len(items), matched)
+	}
+
+	if failed == len(r.feeds) {
+		return nil, fmt.Errorf("全てのフィードの取得に失敗しました")
+	}
+
+	sort.SliceStable(articles, func(i, j int) bool { return articles[i].Date > articles[j].Date })
+	if len(articles) > r.maxItems {
+		articles = articles[:r.maxItems]
+	}
+	return articles, nil
+}
+
+// rssDocument RSS 2.0 / RSS 1.0 (RDF) / Atom を共通で受けるための構造
+type rssDocument struct {
+	Channel struct {
+		Items []rssItem `xml:"item"`
+	} `xml:"channel"`
+	Items   []rssItem   `xml:"item"`  // RSS 1.0 (RDF) はitemがルート直下
+	Entries []atomEntry `xml:"entry"` // Atom
+}
+
+type rssItem struct {
+	Title       string `xml:"title"`
+	Link        string `xml:"link"`
+	Description string `xml:"description"`
+	PubDate     string `xml:"pubDate"`
+	DCDate      string `xml:"http://purl.org/dc/elements/1.1/ date"`
+}
+
+type atomEntry struct {
+	Title string `xml:"title"`
+	Links []struct {
+		Href string `xml:"href,attr"`
+		Rel  string `xml:"rel,attr"`
+	} `xml:"link"`
+	Summary   string `xml:"summary"`
+	Content   string `xml:"content"`
+	Published string `xml:"published"`
+	Updated   string `xml:"updated"`
+}
+
+func (r *RSSFetcher) fetchFeed(ctx context.Context, feed FeedConfig) ([]NewsArticle, error) {
+	body, err := r.httpClient.Get(ctx, feed.URL)
+	if err != nil {
+		return nil, err
+	}
+	return ParseFeed([]byte(body))
+}
+
+// ParseFeed RSS/Atomフィードを解析して記事一覧に変換
+func ParseFeed(data []byte) ([]NewsArticle, error) {
+	var doc rssDocument
+	dec := xml.NewDecoder(bytes.NewReader(data))
+	dec.CharsetReader = charset.NewReaderLabel
+	dec.Strict = false
+	if err := dec.Decode(&doc); err != nil {
+		return nil, fmt.Errorf("フィード解析エラー: %w", err)
+	}
+
+	var articles []NewsArticle
+	for _, it := range append(doc.Channel.Items, doc.Items...) {
+		published := it.PubDate
+		if published == "" {
+			published = it.DCDate
+		}
+		articles = append(articles, NewsArticle{
+			Title:   strings.TrimSpace(it.Title),
+			Content: stripHTML(it.Description),
+			URL:     strings.TrimSpace(it.Link),
+			Date:    normalizePublishedAt(published),
+		})
+	}
+
+	for _, e := range doc.Entries {
+		link := ""
+		for _, l := range e.Links {
+			if l.Rel == "" || l.Rel == "alternate" {
+				link = l.Href
+				break
+			}
+		}
+		content := e.Summary
+		if content == "" {
+			content = e.Content
+		}
+		published := e.Published
+		if published == "" {
+			published = e.Updated
+		}
+		articles = append(articles, NewsArticle{
+			Title:   strings.TrimSpace(e.Title),
+			Content: stripHTML(content),
+			URL:     strings.TrimSpace(link),
+			Date:    normalizePublishedAt(published),
+		})
+	}
+
+	return articles, nil
+}
+
+// publishedLayouts フィードで使われる日時形式
+var publishedLayouts = []string{
+	time.RFC1123Z,
+	time.RFC1123,
+	"Mon, 2 Jan 2006 15:04:05 -0700",
+	"Mon, 2 Jan 2006 15:04:05 MST",
+	"2 Jan 2006 15:04:05 -0700",
+	time.RFC822Z,
+	time.RFC822,
+	time.RFC3339,
+	"2006-01-02T15:04:05-0700",
+	"2006-01-02T15:04:05",
+	"2006-01-02 15:04:05",
+	"2006-01-02",
+}
+
+// normalizePublishedAt 公開日時をRFC3339（UTC）に正規化。解析できなければ元の文字列を返す
+func normalizePublishedAt(s string) string {
+	s = strings.TrimSpace(s)
+	for _, layout := range publishedLayouts {
+		// タイムゾーン指定のない形式は日本時間とみなし、略称のJSTは+0900として解釈する
+		// （time.Parseでは未知の略称はUTCと同じ時刻になる）
+		t, err := time.ParseInLocation(layout, s, timeseries.JST)
+		if err != nil {
+			continue
+		}
+		return t.UTC().Format(time.RFC3339)
+	}
+	return s
+}
+
+// stripHTML 説明文に含まれるHTMLタグを除去
+func stripHTML(s string) string {
+	if !strings.Contains(s, "<") {
+		return strings.TrimSpace(html.UnescapeString(s))
+	}
+
+	var b strings.Builder
+	z := html.NewTokenizer(strings.NewReader(s))
+	for {
+		switch z.Next() {
+		case html.ErrorToken:
+			return strings.Join(strings.Fields(b.String()), " ")
+		case html.TextToken:
+			b.Write(z.Text())
+		case html.StartTagToken, html.EndTagToken, html.SelfClosingTagToken:
+			// ブロック要素の区切りのみ空白にする（日本語の文中に空白を入れない）
+			name, _ := z.TagName()
+			switch string(name) {
+			case "p", "br", "div", "li", "tr", "h1", "h2", "h3", "h4":
+				b.WriteByte(' ')
+			}
+		}
+	}
+}
+
+// parseKeywords クエリ文字列をキーワードに分解
+func parseKeywords(query string) []string {
+	query = strings.ReplaceAll(query, " OR ", ",")
+	var keywords []string
+	for _, k := range strings.Split(query, ",") {
+		if k = strings.TrimSpace(k); k != "" {
+			keywords = append(keywords, k)
+		}
+	}
+	return keywords
+}
+
+// matchesKeywords テキストにいずれかのキーワードが含まれるか（大文字小文字を区別しない）
+func matchesKeywords(text string, keywords []string) bool {
+	text = strings.ToLower(text)
+	for _, k := range keywords {
+		if containsKeyword(text, strings.ToLower(k)) {
+			return true
+		}
+	}
+	return false
+}
+
+// containsKeyword 英数字のキーワードは単語単位（"oil"は"soil"に一致しない）、日本語などは部分一致で探す
+func containsKeyword(text, keyword string) bool {
+	if strings.IndexFunc(keyword, func(r rune) bool { return r >= utf8.RuneSelf }) >= 0 {
+		return strings.Contains(text, keyword)
+	}
+	for i := 0; i <= len(text); {
+		j := strings.Index(text[i:], keyword)
+		if j < 0 {
+			return false
+		}
+		start, end := i+j, i+j+len(keyword)
+		if !isWordByte(text, start-1) && !isWordByte(text, end) {
+			return true
+		}
+		i = start + 1
+	}
+	return false
+}
+
+// isWordByte s[i]が英数字か（範囲外はfalse。小文字にしたテキストに使う）
+func isWordByte(s string, i int) bool {
+	if i < 0 || i >= len(s) {
+		return false
+	}
+	c := s[i]
+	return 'a' <= c && c <= 'z' || '0' <= c && c <= '9' || c == '_'
+}
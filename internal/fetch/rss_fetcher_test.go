@@ -0,0 +1,46 @@
+package fetcher
+
+import "testing"
+
+func TestNormalizePublishedAt(t *testing.T) {
+	for in, want := range map[string]string{
+		"Mon, 06 Jan 2025 09:30:00 +0900": "2025-01-06T00:30:00Z",
+		"Mon, 06 Jan 2025 09:30:00 JST":   "2025-01-06T00:30:00Z",
+		"Mon, 6 Jan 2025 09:30:00 JST":    "2025-01-06T00:30:00Z",
+		"Mon, 06 Jan 2025 09:30:00 GMT":   "2025-01-06T09:30:00Z",
+		"06 Jan 25 09:30 JST":             "2025-01-06T00:30:00Z",
+		"2025-01-06T09:30:00+09:00":       "2025-01-06T00:30:00Z",
+		"2025-01-06T09:30:00Z":            "2025-01-06T09:30:00Z",
+		"2025-01-06T09:30:00":             "2025-01-06T00:30:00Z",
+		"2025-01-06 09:30:00":             "2025-01-06T00:30:00Z",
+		"2025-01-06":                      "2025-01-05T15:00:00Z",
+		"  2025-01-06  ":                  "2025-01-05T15:00:00Z",
+		"昨日":                              "昨日",
+	} {
+		if got := normalizePublishedAt(in); got != want {
+			t.Errorf("normalizePublishedAt(%q) = %q, want %q", in, got, want)
+		}
+	}
+}
+
+func TestMatchesKeywords(t *testing.T) {
+	keywords := parseKeywords("oil OR gasoline, crude oil, OPEC, ガソリン, 原油")
+	for text, want := range map[string]bool{
+		"Oil prices rise":                    true,
+		"OPEC+ agrees to cut output":         true,
+		"U.S. gasoline demand":               true,
+		"Brent crude-oil futures":            true,
+		"oil.":                               true,
+		"Soil moisture improves crop yields": false,
+		"Toiletries maker raises prices":     false,
+		"Gasolines":                          false,
+		"レギュラーガソリンの店頭価格":                     true,
+		"原油先物が反発":                            true,
+		"中東情勢でoil価格が上昇":                      true,
+		"為替は円高に振れた":                          false,
+	} {
+		if got := matchesKeywords(text, keywords); got != want {
+			t.Errorf("matchesKeywords(%q) = %v, want %v", text, got, want)
+		}
+	}
+}
@@ -34,6 +34,11 @@ func NewHTTPClient(timeout time.Duration) *HTTPClient {
 
 // Get HTTPリクエストを実行
 func (h *HTTPClient) Get(ctx context.Context, url string) (string, error) {
+	return h.GetWithLimit(ctx, url, 0)
+}
+
+// GetWithLimit HTTPリクエストを実行し、レスポンスをlimitバイトまで読み込む（0以下なら無制限）
+func (h *HTTPClient) GetWithLimit(ctx context.Context, url string, limit int64) (string, error) {
 	req, err := http.NewRequestWithContext(ctx, "GET", url, nil)
 	if err != nil {
 		return "", fmt.Errorf("リクエスト作成エラー: %w", err)
@@ -55,7 +60,11 @@ func (h *HTTPClient) Get(ctx context.Context, url string) (string, error) {
 		return "", fmt.Errorf("HTTPエラー: status=%d", resp.StatusCode)
 	}
 
-	body, err := io.ReadAll(resp.Body)
+	var r io.Reader = resp.Body
+	if limit > 0 {
+		r = io.LimitReader(resp.Body, limit)
+	}
+	body, err := io.ReadAll(r)
 	if err != nil {
 		return "", fmt.Errorf("レスポンス読み込みエラー: %w", err)
 	}
@@ -0,0 +1,114 @@
+package forecast
+
+import (
+	"fmt"
+	"math"
+	"time"
+
+	"gasinsight/internal/timeseries"
+)
+
+// HorizonMetrics 予測期間ごとの誤差指標
+type HorizonMetrics struct {
+	Horizon  int     `json:"horizon"`
+	N        int     `json:"n"`         // 評価件数
+	MAE      float64 `json:"mae"`       // 平均絶対誤差（円）
+	RMSE     float64 `json:"rmse"`      // 二乗平均平方根誤差（円）
+	MAPE     float64 `json:"mape"`      // 平均絶対パーセント誤差（%）
+	NaiveMAE float64 `json:"naive_mae"` // 前週値をそのまま使うナイーブ予測のMAE
+	Coverage float64 `json:"coverage"`  // 実績値が予測区間に入った割合
+}
+
+// BacktestReport バックテスト結果
+type BacktestReport struct {
+	Origins int               `json:"origins"` // 予測を行った起点数
+	Methods map[string]int    `json:"methods"` // 使用された手法と回数
+	Metrics []*HorizonMetrics `json:"metrics"`
+}
+
+// Backtest 起点を1週ずつずらしながら過去データのみで予測し、実績との誤差を集計
+// minTrain: 最初の起点までに必要な週数
+func Backtest(in Inputs, cfg Config, minTrain int) (*BacktestReport, error) {
+	if minTrain < 3 {
+		minTrain = 3
+	}
+	if len(in.Retail) <= minTrain {
+		return nil, fmt.Errorf("バックテストには%d週より多いデータが必要です（現在: %d週）", minTrain, len(in.Retail))
+	}
+
+	actual := in.Retail.Index()
+	report := &BacktestReport{Methods: map[string]int{}}
+
+	type accum struct {
+		n                            int
+		absErr, sqErr, pctErr, naive float64
+		covered                      int
+	}
+	acc := make([]accum, cfg.Horizons)
+
+	for i := minTrain - 1; i < len(in.Retail)-1; i++ {
+		origin := in.Retail[i]
+		f, err := Predict(truncate(in, origin.Date), cfg)
+		if err != nil {
+			continue
+		}
+		report.Origins++
+		report.Methods[f.Method]++
+
+		for _, p := range f.Predictions {
+			v, ok := actual[p.WeekStart]
+			if !ok {
+				continue
+			}
+			a := &acc[p.Horizon-1]
+			e := v - p.Value
+			a.n++
+			a.absErr += math.Abs(e)
+			a.sqErr += e * e
+			if v != 0 {
+				a.pctErr += math.Abs(e / v)
+			}
+			a.naive += math.Abs(v - origin.Value)
+			if v >= p.Lower && v <= p.Upper {
+				a.covered++
+			}
+		}
+	}
+
+	if report.Origins == 0 {
+		return nil, fmt.Errorf("予測可能な起点がありませんでした")
+	}
+
+	for h, a := range acc {
+		m := &HorizonMetrics{Horizon: h + 1, N: a.n}
+		if a.n > 0 {
+			n := float64(a.n)
+			m.MAE = a.absErr / n
+			m.RMSE = math.Sqrt(a.sqErr / n)
+			m.MAPE = a.pctErr / n * 100
+			m.NaiveMAE = a.naive / n
+			m.Coverage = float64(a.covered) / n
+		}
+		report.Metrics = append(report.Metrics, m)
+	}
+
+	return report, nil
+}
+
+// truncate 指定日付以前のデータのみを残す
+func truncate(in Inputs, until time.Time) Inputs {
+	cut := func(s timeseries.Series) timeseries.Series {
+		var out timeseries.Series
+		for _, p := range s {
+			if !p.Date.After(until) {
+				out = append(out, p)
+			}
+		}
+		return out
+	}
+	return Inputs{
+		Retail:   cut(in.Retail),
+		CrudeYen: cut(in.CrudeYen),
+		USDJPY:   cut(in.USDJPY),
+	}
+}
@@ -0,0 +1,263 @@
+package forecast
+
+import (
+	"fmt"
+	"math"
+	"strings"
+	"time"
+
+	"gasinsight/internal/timeseries"
+)
+
+// Config 予測の設定
+type Config struct {
+	Horizons   int     // 予測する週数（1〜4）
+	Lags       int     // 説明変数に使うラグ（週）の数
+	Confidence float64 // 予測区間の信頼水準（例: 0.95）
+}
+
+// DefaultConfig デフォルト設定
+func DefaultConfig() Config {
+	return Config{
+		Horizons:   4,
+		Lags:       2,
+		Confidence: 0.95,
+	}
+}
+
+// Inputs 予測に使う週次系列
+type Inputs struct {
+	Retail   timeseries.Series // 小売価格（円/L）
+	CrudeYen timeseries.Series // 円建て原油価格（円/L）
+	USDJPY   timeseries.Series // USD/JPY
+}
+
+// Prediction 1週分の予測値
+type Prediction struct {
+	Horizon   int     `json:"horizon"`    // 何週先か
+	WeekStart string  `json:"week_start"` // 対象週の月曜日
+	Value     float64 `json:"value"`      // 予測値
+	Lower     float64 `json:"lower"`      // 予測区間の下限
+	Upper     float64 `json:"upper"`      // 予測区間の上限
+}
+
+// Forecast 予測結果
+type Forecast struct {
+	Method      string       `json:"method"`
+	LastWeek    string       `json:"last_week"`
+	LastValue   float64      `json:"last_value"`
+	Confidence  float64      `json:"confidence"`
+	Predictions []Prediction `json:"predictions"`
+}
+
+const week = 7 * 24 * time.Hour
+
+// feature 説明変数の元になる系列
+type feature struct {
+	name   string
+	values map[string]float64
+}
+
+// WeeklyInputs 日次系列を週次に集約して予測用の入力を作成
+func WeeklyInputs(retail, crudeUSD, usdJpy timeseries.Series) Inputs {
+	return Inputs{
+		Retail:   retail.Weekly(),
+		CrudeYen: timeseries.CrudeYen(crudeUSD, usdJpy).Weekly(),
+		USDJPY:   usdJpy.Weekly(),
+	}
+}
+
+// Predict 1〜Horizons週先の小売価格を予測
+//
+// 原油・為替のラグ付き変化量を説明変数とする回帰を予測期間ごとに推定する（直接法）。
+// 観測数が不足する場合は説明変数を減らし、最終的にはHolt法（二重指数平滑）にフォールバックする。
+func Predict(in Inputs, cfg Config) (*Forecast, error) {
+	if cfg.Horizons < 1 || cfg.Horizons > 4 {
+		return nil, fmt.Errorf("予測期間は1〜4週で指定してください: %d", cfg.Horizons)
+	}
+	if cfg.Lags < 1 {
+		cfg.Lags = 1
+	}
+	if cfg.Confidence <= 0 || cfg.Confidence >= 1 {
+		cfg.Confidence = 0.95
+	}
+
+	last, ok := in.Retail.Last()
+	if !ok {
+		return nil, fmt.Errorf("小売価格データがありません")
+	}
+
+	for _, features := range candidateFeatures(in) {
+		if f, err := predictRegression(in.Retail, features, cfg); err == nil {
+			return f, nil
+		}
+	}
+
+	f, err := predictHolt(in.Retail, cfg)
+	if err != nil {
+		return nil, err
+	}
+	f.LastWeek = timeseries.FormatDate(last.Date)
+	f.LastValue = last.Value
+	return f, nil
+}
+
+// candidateFeatures 試行する説明変数の組み合わせ（多い順）
+func candidateFeatures(in Inputs) [][]feature {
+	retail := feature{name: "retail", values: in.Retail.Index()}
+	crude := feature{name: "crude", values: in.CrudeYen.Index()}
+	fx := feature{name: "usdjpy", values: in.USDJPY.Index()}
+
+	var sets [][]feature
+	if len(in.CrudeYen) > 0 && len(in.USDJPY) > 0 {
+		sets = append(sets, []feature{retail, crude, fx})
+	}
+	if len(in.CrudeYen) > 0 {
+		sets = append(sets, []feature{retail, crude})
+	}
+	if len(in.USDJPY) > 0 {
+		sets = append(sets, []feature{retail, fx})
+	}
+	return append(sets, []feature{retail})
+}
+
+// lagDiffs 起点週tにおける各系列のラグ付き変化量 x(t-k) - x(t-k-1) を並べる
+func lagDiffs(features []feature, t time.Time, lags int) ([]float64, bool) {
+	var x []float64
+	for _, f := range features {
+		for k := 0; k < lags; k++ {
+			cur, ok1 := f.values[timeseries.FormatDate(t.Add(-time.Duration(k)*week))]
+			prev, ok2 := f.values[timeseries.FormatDate(t.Add(-time.Duration(k+1)*week))]
+			if !ok1 || !ok2 {
+				return nil, false
+			}
+			x = append(x, cur-prev)
+		}
+	}
+	return x, true
+}
+
+func predictRegression(retail timeseries.Series, features []feature, cfg Config) (*Forecast, error) {
+	last, _ := retail.Last()
+	origin, ok := lagDiffs(features, last.Date, cfg.Lags)
+	if !ok {
+		return nil, fmt.Errorf("予測起点の説明変数が揃っていません")
+	}
+
+	y := features[0].values
+	z := zScore(cfg.Confidence)
+
+	names := make([]string, len(features))
+	for i, f := range features {
+		names[i] = f.name
+	}
+
+	result := &Forecast{
+		Method:     fmt.Sprintf("regression(%s; lags=%d)", strings.Join(names, ","), cfg.Lags),
+		LastWeek:   timeseries.FormatDate(last.Date),
+		LastValue:  last.Value,
+		Confidence: cfg.Confidence,
+	}
+
+	for h := 1; h <= cfg.Horizons; h++ {
+		var X [][]float64
+		var target []float64
+		for _, p := range retail {
+			future, ok := y[timeseries.FormatDate(p.Date.Add(time.Duration(h)*week))]
+			if !ok {
+				continue
+			}
+			x, ok := lagDiffs(features, p.Date, cfg.Lags)
+			if !ok {
+				continue
+			}
+			X = append(X, x)
+			target = append(target, future-p.Value)
+		}
+
+		// 推定するパラメータ数より十分多い観測が必要
+		if len(X) < len(origin)+4 {
+			return nil, fmt.Errorf("観測数が不足しています（%d週先: %d件）", h, len(X))
+		}
+
+		fit, err := fitOLS(X, target)
+		if err != nil {
+			return nil, err
+		}
+
+		value := last.Value + predictOLS(fit.coef, origin)
+		margin := z * fit.sigma
+		result.Predictions = append(result.Predictions, Prediction{
+			Horizon:   h,
+			WeekStart: timeseries.FormatDate(last.Date.Add(time.Duration(h) * week)),
+			Value:     value,
+			Lower:     value - margin,
+			Upper:     value + margin,
+		})
+	}
+
+	return result, nil
+}
+
+// predictHolt Holt法（レベル＋トレンドの二重指数平滑）で予測
+func predictHolt(retail timeseries.Series, cfg Config) (*Forecast, error) {
+	values := retail.Values()
+	if len(values) < 3 {
+		return nil, fmt.Errorf("予測には3週分以上のデータが必要です（現在: %d週）", len(values))
+	}
+
+	// 1期先予測誤差が最小となる平滑化係数をグリッドサーチ
+	bestSSE := math.Inf(1)
+	var bestAlpha, bestBeta float64
+	for alpha := 0.1; alpha < 0.95; alpha += 0.1 {
+		for beta := 0.1; beta < 0.95; beta += 0.1 {
+			_, _, sse := holt(values, alpha, beta)
+			if sse < bestSSE {
+				bestSSE, bestAlpha, bestBeta = sse, alpha, beta
+			}
+		}
+	}
+
+	level, trend, sse := holt(values, bestAlpha, bestBeta)
+	sigma := math.Sqrt(sse / float64(len(values)-2))
+	z := zScore(cfg.Confidence)
+	last, _ := retail.Last()
+
+	result := &Forecast{
+		Method:     fmt.Sprintf("holt(alpha=%.1f, beta=%.1f)", bestAlpha, bestBeta),
+		Confidence: cfg.Confidence,
+	}
+	for h := 1; h <= cfg.Horizons; h++ {
+		value := level + float64(h)*trend
+		margin := z * sigma * math.Sqrt(float64(h))
+		result.Predictions = append(result.Predictions, Prediction{
+			Horizon:   h,
+			WeekStart: timeseries.FormatDate(last.Date.Add(time.Duration(h) * week)),
+			Value:     value,
+			Lower:     value - margin,
+			Upper:     value + margin,
+		})
+	}
+	return result, nil
+}
+
+// holt 平滑化を実行し、最終レベル・トレンドと1期先予測の残差平方和を返す
+func holt(values []float64, alpha, beta float64) (level, trend, sse float64) {
+	level = values[0]
+	trend = values[1] - values[0]
+	for i := 1; i < len(values); i++ {
+		forecast := level + trend
+		err := values[i] - forecast
+		sse += err * err
+
+		prevLevel := level
+		level = alpha*values[i] + (1-alpha)*(level+trend)
+		trend = beta*(level-prevLevel) + (1-beta)*trend
+	}
+	return level, trend, sse
+}
+
+// zScore 両側信頼水準に対応する標準正規分布の分位点
+func zScore(confidence float64) float64 {
+	return math.Sqrt2 * math.Erfinv(confidence)
+}
@@ -0,0 +1,204 @@
+package forecast
+
+import (
+	"math"
+	"strings"
+	"testing"
+	"time"
+
+	"gasinsight/internal/timeseries"
+)
+
+const eps = 1e-6
+
+// weekly 2025-01-06（月曜）から1週ごとにf(i)の値を持つ系列
+func weekly(n int, f func(i int) float64) timeseries.Series {
+	start := time.Date(2025, 1, 6, 0, 0, 0, 0, time.UTC)
+	s := make(timeseries.Series, n)
+	for i := range s {
+		s[i] = timeseries.Point{Date: start.Add(time.Duration(i) * week), Value: f(i)}
+	}
+	return s
+}
+
+func TestFitOLS(t *testing.T) {
+	// 説明変数が互いに比例しないよう、周期の異なる値を使う
+	x1 := func(i int) float64 { return float64(i%5) - 2 }
+	x2 := func(i int) float64 { return float64((i*3)%7) * 0.5 }
+
+	tests := []struct {
+		name string
+		coef []float64 // 切片, 各説明変数の係数
+		x    func(i int) []float64
+	}{
+		{"説明変数1つ", []float64{1.5, 2}, func(i int) []float64 { return []float64{x1(i)} }},
+		{"説明変数2つ", []float64{2, 3, -1.5}, func(i int) []float64 { return []float64{x1(i), x2(i)} }},
+		{"切片が0", []float64{0, -0.8, 0.25}, func(i int) []float64 { return []float64{x1(i), x2(i)} }},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var X [][]float64
+			var y []float64
+			for i := 0; i < 20; i++ {
+				x := tt.x(i)
+				X = append(X, x)
+				y = append(y, predictOLS(tt.coef, x))
+			}
+			fit, err := fitOLS(X, y)
+			if err != nil {
+				t.Fatal(err)
+			}
+			for i, want := range tt.coef {
+				if math.Abs(fit.coef[i]-want) > eps {
+					t.Errorf("係数[%d] = %f, want %f", i, fit.coef[i], want)
+				}
+			}
+			if fit.sigma > eps || fit.nobs != 20 || fit.params != len(tt.coef) {
+				t.Errorf("sigma = %g, nobs = %d, params = %d", fit.sigma, fit.nobs, fit.params)
+			}
+		})
+	}
+}
+
+func TestFitOLSErrors(t *testing.T) {
+	if _, err := fitOLS([][]float64{{1}, {2}}, []float64{1, 2}); err == nil {
+		t.Error("観測数不足でエラーになりません")
+	}
+	if _, err := fitOLS([][]float64{{1}, {2}, {3}}, []float64{1, 2}); err == nil {
+		t.Error("説明変数と目的変数の件数が異なってもエラーになりません")
+	}
+}
+
+// TestPredictExactTrend 一定のトレンドだけの系列は、予測値がトレンドの延長と一致し、区間の幅は0になる
+func TestPredictExactTrend(t *testing.T) {
+	tests := []struct {
+		name   string
+		weeks  int
+		slope  float64
+		method string
+	}{
+		{"回帰", 20, 0.5, "regression"},
+		{"下落トレンド", 20, -1.2, "regression"},
+		{"Holt法にフォールバック", 5, 0.8, "holt"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			retail := weekly(tt.weeks, func(i int) float64 { return 170 + tt.slope*float64(i) })
+			f, err := Predict(Inputs{Retail: retail}, DefaultConfig())
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !strings.HasPrefix(f.Method, tt.method) {
+				t.Errorf("手法 = %s, want %s", f.Method, tt.method)
+			}
+			last := retail[len(retail)-1]
+			if f.LastWeek != timeseries.FormatDate(last.Date) || f.LastValue != last.Value {
+				t.Errorf("起点 = %s %.2f", f.LastWeek, f.LastValue)
+			}
+			if len(f.Predictions) != 4 {
+				t.Fatalf("予測 = %d週, want 4", len(f.Predictions))
+			}
+			for _, p := range f.Predictions {
+				want := last.Value + tt.slope*float64(p.Horizon)
+				if math.Abs(p.Value-want) > eps || math.Abs(p.Upper-p.Lower) > eps {
+					t.Errorf("%d週先 = %.6f [%.6f, %.6f], want %.6f", p.Horizon, p.Value, p.Lower, p.Upper, want)
+				}
+				if wantWeek := timeseries.FormatDate(last.Date.Add(time.Duration(p.Horizon) * week)); p.WeekStart != wantWeek {
+					t.Errorf("%d週先の週 = %s, want %s", p.Horizon, p.WeekStart, wantWeek)
+				}
+			}
+		})
+	}
+}
+
+// TestPredictUsesCrudeLag 小売価格の変化が1週前の原油価格の変化に比例する場合、係数を推定して予測する
+func TestPredictUsesCrudeLag(t *testing.T) {
+	crude := weekly(30, func(i int) float64 { return 60 + float64((i*7)%11) })
+	retail := make(timeseries.Series, len(crude))
+	retail[0] = timeseries.Point{Date: crude[0].Date, Value: 170}
+	for i := 1; i < len(crude); i++ {
+		// 翌週の小売価格 = 今週 + 0.5 × 今週の原油価格の変化
+		change := 0.0
+		if i >= 2 {
+			change = 0.5 * (crude[i-1].Value - crude[i-2].Value)
+		}
+		retail[i] = timeseries.Point{Date: crude[i].Date, Value: retail[i-1].Value + change}
+	}
+
+	f, err := Predict(Inputs{Retail: retail, CrudeYen: crude}, Config{Horizons: 1, Lags: 1, Confidence: 0.95})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.HasPrefix(f.Method, "regression(retail,crude;") {
+		t.Errorf("手法 = %s", f.Method)
+	}
+	n := len(crude)
+	want := retail[n-1].Value + 0.5*(crude[n-1].Value-crude[n-2].Value)
+	if got := f.Predictions[0].Value; math.Abs(got-want) > eps {
+		t.Errorf("1週先 = %.6f, want %.6f", got, want)
+	}
+}
+
+func TestPredictRejectsInvalidInput(t *testing.T) {
+	if _, err := Predict(Inputs{Retail: weekly(10, func(int) float64 { return 170 })}, Config{Horizons: 5}); err == nil {
+		t.Error("予測期間5週でエラーになりません")
+	}
+	if _, err := Predict(Inputs{}, DefaultConfig()); err == nil {
+		t.Error("データなしでエラーになりません")
+	}
+	if _, err := Predict(Inputs{Retail: weekly(2, func(int) float64 { return 170 })}, DefaultConfig()); err == nil {
+		t.Error("2週分のデータでエラーになりません")
+	}
+}
+
+// TestBacktestExactTrend 一定のトレンドの系列では誤差が0で、ナイーブ予測の誤差はトレンド×週数になる
+func TestBacktestExactTrend(t *testing.T) {
+	const weeks, minTrain, slope = 20, 8, 0.5
+	retail := weekly(weeks, func(i int) float64 { return 170 + slope*float64(i) })
+
+	report, err := Backtest(Inputs{Retail: retail}, DefaultConfig(), minTrain)
+	if err != nil {
+		t.Fatal(err)
+	}
+	// 起点は minTrain-1 〜 weeks-2 週目
+	if want := weeks - minTrain; report.Origins != want {
+		t.Errorf("起点数 = %d, want %d", report.Origins, want)
+	}
+	if len(report.Metrics) != 4 {
+		t.Fatalf("指標 = %d件, want 4", len(report.Metrics))
+	}
+	for _, m := range report.Metrics {
+		// h週先の実績がある起点だけを評価する
+		if want := weeks - minTrain - m.Horizon + 1; m.N != want {
+			t.Errorf("%d週先の評価件数 = %d, want %d", m.Horizon, m.N, want)
+		}
+		if m.MAE > eps || m.RMSE > eps || m.MAPE > eps {
+			t.Errorf("%d週先の誤差 = MAE %g, RMSE %g, MAPE %g, want 0", m.Horizon, m.MAE, m.RMSE, m.MAPE)
+		}
+		if want := slope * float64(m.Horizon); math.Abs(m.NaiveMAE-want) > eps {
+			t.Errorf("%d週先のナイーブMAE = %f, want %f", m.Horizon, m.NaiveMAE, want)
+		}
+	}
+}
+
+// TestBacktestErrorMetrics 予測が外れる系列では、MAEとMAPEを実績との差から計算する
+func TestBacktestErrorMetrics(t *testing.T) {
+	// 上昇トレンドが最後の週だけ止まると、直前の起点の1週先予測はトレンド分だけ外れる
+	retail := weekly(6, func(i int) float64 { return 100 + 2*float64(min(i, 4)) })
+
+	report, err := Backtest(Inputs{Retail: retail}, Config{Horizons: 1, Lags: 1, Confidence: 0.95}, 5)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if report.Origins != 1 || len(report.Metrics) != 1 {
+		t.Fatalf("起点数 = %d, 指標 = %d件", report.Origins, len(report.Metrics))
+	}
+	m := report.Metrics[0]
+	// 起点（4週目, 108円）の予測は110円、実績は108円
+	if m.N != 1 || math.Abs(m.MAE-2) > eps || math.Abs(m.RMSE-2) > eps || math.Abs(m.MAPE-2.0/108*100) > eps {
+		t.Errorf("指標 = %+v, want MAE 2, MAPE %.4f", m, 2.0/108*100)
+	}
+	if m.NaiveMAE != 0 {
+		t.Errorf("ナイーブMAE = %f, want 0", m.NaiveMAE)
+	}
+}
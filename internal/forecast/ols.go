@@ -0,0 +1,116 @@
+package forecast
+
+import (
+	"fmt"
+	"math"
+)
+
+// olsFit 最小二乗法の推定結果
+type olsFit struct {
+	coef   []float64
+	sigma  float64 // 残差標準偏差
+	nobs   int
+	params int
+}
+
+// fitOLS 最小二乗法で回帰係数を推定（X は切片列を含まない）
+func fitOLS(X [][]float64, y []float64) (*olsFit, error) {
+	n := len(y)
+	if n == 0 || len(X) != n {
+		return nil, fmt.Errorf("回帰データが不正です")
+	}
+	k := len(X[0]) + 1
+	if n <= k {
+		return nil, fmt.Errorf("観測数が不足しています（%d件、必要: %d件以上）", n, k+1)
+	}
+
+	// 正規方程式 (X'X) β = X'y を構築
+	xtx := make([][]float64, k)
+	for i := range xtx {
+		xtx[i] = make([]float64, k)
+	}
+	xty := make([]float64, k)
+
+	row := make([]float64, k)
+	for i := 0; i < n; i++ {
+		row[0] = 1
+		copy(row[1:], X[i])
+		for a := 0; a < k; a++ {
+			xty[a] += row[a] * y[i]
+			for b := 0; b < k; b++ {
+				xtx[a][b] += row[a] * row[b]
+			}
+		}
+	}
+
+	// 数値安定化のためのごく小さいリッジ項
+	for a := 1; a < k; a++ {
+		xtx[a][a] += 1e-9
+	}
+
+	coef, err := solve(xtx, xty)
+	if err != nil {
+		return nil, err
+	}
+
+	var sse float64
+	for i := 0; i < n; i++ {
+		r := y[i] - predictOLS(coef, X[i])
+		sse += r * r
+	}
+
+	return &olsFit{
+		coef:   coef,
+		sigma:  math.Sqrt(sse / float64(n-k)),
+		nobs:   n,
+		params: k,
+	}, nil
+}
+
+// predictOLS 回帰係数から予測値を計算
+func predictOLS(coef []float64, x []float64) float64 {
+	v := coef[0]
+	for i, xi := range x {
+		v += coef[i+1] * xi
+	}
+	return v
+}
+
+// solve ガウスの消去法（部分ピボット選択）で連立一次方程式を解く
+func solve(a [][]float64, b []float64) ([]float64, error) {
+	n := len(b)
+	m := make([][]float64, n)
+	for i := range a {
+		m[i] = append(append([]float64{}, a[i]...), b[i])
+	}
+
+	for col := 0; col < n; col++ {
+		pivot := col
+		for r := col + 1; r < n; r++ {
+			if math.Abs(m[r][col]) > math.Abs(m[pivot][col]) {
+				pivot = r
+			}
+		}
+		if math.Abs(m[pivot][col]) < 1e-12 {
+			return nil, fmt.Errorf("回帰行列が特異です（説明変数が変化していません）")
+		}
+		m[col], m[pivot] = m[pivot], m[col]
+
+		for r := col + 1; r < n; r++ {
+			f := m[r][col] / m[col][col]
+			for c := col; c <= n; c++ {
+				m[r][c] -= f * m[col][c]
+			}
+		}
+	}
+
+	x := make([]float64, n)
+	for i := n - 1; i >= 0; i-- {
+		sum := m[i][n]
+		for j := i + 1; j < n; j++ {
+			sum -= m[i][j] * x[j]
+		}
+		x[i] = sum / m[i][i]
+	}
+	return x, nil
+}
@@ -0,0 +1,37 @@
+package forecast
+
+import (
+	"fmt"
+
+	model "gasinsight/internal/model"
+	"gasinsight/internal/timeseries"
+)
+
+// Source 予測に使用するデータの取得元
+type Source interface {
+	GetAllGasPrices() ([]*model.GasPrice, error)
+	GetAllExchangeRates() ([]*model.ExchangeRate, error)
+	GetAllCrudePrices() ([]*model.CrudePrice, error)
+}
+
+// LoadInputs 保存済みデータから燃料種別・地域の週次入力を作成
+func LoadInputs(src Source, fuel, region string) (Inputs, error) {
+	prices, err := src.GetAllGasPrices()
+	if err != nil {
+		return Inputs{}, fmt.Errorf("ガソリン価格取得エラー: %w", err)
+	}
+	rates, err := src.GetAllExchangeRates()
+	if err != nil {
+		return Inputs{}, fmt.Errorf("為替レート取得エラー: %w", err)
+	}
+	crude, err := src.GetAllCrudePrices()
+	if err != nil {
+		return Inputs{}, fmt.Errorf("原油価格取得エラー: %w", err)
+	}
+
+	return WeeklyInputs(
+		timeseries.FromGasPrices(prices, fuel, region),
+		timeseries.FromCrudePrices(crude),
+		timeseries.FromExchangeRates(rates, "USD"),
+	), nil
+}
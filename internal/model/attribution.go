@@ -0,0 +1,50 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// PriceChangeRecord 変動検知（price_changeテーブル）の記録
+type PriceChangeRecord struct {
+	ID        int64   `json:"id"`
+	Region    string  `json:"region"`     // 地域
+	DateNew   string  `json:"date_new"`   // 比較後の日付
+	PriceNew  float64 `json:"price_new"`  // 比較後の価格
+	DateOld   string  `json:"date_old"`   // 比較前の日付
+	PriceOld  float64 `json:"price_old"`  // 比較前の価格
+	PctChange float64 `json:"pct_change"` // 変動率(%)
+	Flagged   bool    `json:"flagged"`    // しきい値を超えたか
+	CreatedAt string  `json:"created_at"` // 作成日時
+}
+
+// PriceChangeAttribution 価格変動の要因分析レポート
+type PriceChangeAttribution struct {
+	ID            string  `json:"id"`              // プライマリキー（UUID）
+	PriceChangeID int64   `json:"price_change_id"` // 対象の変動記録
+	Analyzer      string  `json:"analyzer"`        // 分析に使用したバックエンド（mock/gemini/openai）
+	Model         string  `json:"model"`           // 分析に使用したモデル
+	PromptVersion string  `json:"prompt_version"`  // 分析に使用したプロンプト（例: price_change@v1）
+	NewsCount     int     `json:"news_count"`      // 分析に使用したニュース数
+	USDJPYOld     float64 `json:"usd_jpy_old"`     // 変動前のUSD/JPY（不明なら0）
+	USDJPYNew     float64 `json:"usd_jpy_new"`     // 変動後のUSD/JPY（不明なら0）
+	Report        string  `json:"report"`          // 分析レポート本文
+	CreatedAt     int64   `json:"created_at"`      // 作成タイムスタンプ
+}
+
+// NewPriceChangeAttribution 新しいPriceChangeAttributionインスタンスを作成
+func NewPriceChangeAttribution(priceChangeID int64, analyzer, model, promptVersion string, newsCount int, usdJpyOld, usdJpyNew float64, report string) *PriceChangeAttribution {
+	return &PriceChangeAttribution{
+		ID:            uuid.New().String(),
+		PriceChangeID: priceChangeID,
+		Analyzer:      analyzer,
+		Model:         model,
+		PromptVersion: promptVersion,
+		NewsCount:     newsCount,
+		USDJPYOld:     usdJpyOld,
+		USDJPYNew:     usdJpyNew,
+		Report:        report,
+		CreatedAt:     time.Now().Unix(),
+	}
+}
@@ -4,7 +4,7 @@ import "time"
 
 // ExchangeRate 為替レートのモデル
 type ExchangeRate struct {
-	ID        string  `json:"id"`         // プライマリキー: YYYY-MM-DD
+	ID        string  `json:"id"`         // プライマリキー（ExchangeRateIDで日付・データソースから決まる）
 	Date      string  `json:"date"`       // 日付
 	USDJPY    float64 `json:"usd_jpy"`    // 米ドル/円
 	EURJPY    float64 `json:"eur_jpy"`    // ユーロ/円
@@ -15,17 +15,23 @@ type ExchangeRate struct {
 	UpdatedAt int64   `json:"updated_at"` // 更新タイムスタンプ
 }
 
+// ExchangeRateID 日付・データソースから決まるID（為替レートはこの組み合わせごとに1件）
+func ExchangeRateID(date, source string) string {
+	return date + "_" + source
+}
+
 // NewExchangeRate 新しいExchangeRateインスタンスを作成
 func NewExchangeRate(date string, usdJpy, eurJpy, gbpJpy, cnyJpy float64) *ExchangeRate {
 	now := time.Now().Unix()
+	const source = "exchangerate-api.com"
 	return &ExchangeRate{
-		ID:        date,
+		ID:        ExchangeRateID(date, source),
 		Date:      date,
 		USDJPY:    usdJpy,
 		EURJPY:    eurJpy,
 		GBPJPY:    gbpJpy,
 		CNYJPY:    cnyJpy,
-		Source:    "exchangerate-api.com",
+		Source:    source,
 		CreatedAt: now,
 		UpdatedAt: now,
 	}
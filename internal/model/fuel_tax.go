@@ -0,0 +1,112 @@
+package models
+
+import "time"
+
+// LitersPerBarrel 1バレルあたりのリットル数
+const LitersPerBarrel = 158.987
+
+// FuelSubsidy 燃料油価格激変緩和補助金（週次）
+type FuelSubsidy struct {
+	ID        string  `json:"id"`         // プライマリキー: 週の開始日
+	WeekStart string  `json:"week_start"` // 適用週の開始日（月曜日, YYYY-MM-DD）
+	Amount    float64 `json:"amount"`     // 補助額（円/L）
+	Source    string  `json:"source"`     // データソース
+	CreatedAt int64   `json:"created_at"` // 作成タイムスタンプ
+	UpdatedAt int64   `json:"updated_at"` // 更新タイムスタンプ
+}
+
+// NewFuelSubsidy 新しいFuelSubsidyインスタンスを作成
+func NewFuelSubsidy(weekStart string, amount float64) *FuelSubsidy {
+	now := time.Now().Unix()
+	return &FuelSubsidy{
+		ID:        weekStart,
+		WeekStart: weekStart,
+		Amount:    amount,
+		Source:    "資源エネルギー庁",
+		CreatedAt: now,
+		UpdatedAt: now,
+	}
+}
+
+// CrudePrice 原油価格（ドバイ原油スポット）
+type CrudePrice struct {
+	ID           string  `json:"id"`             // プライマリキー: YYYY-MM-DD
+	Date         string  `json:"date"`           // 日付
+	USDPerBarrel float64 `json:"usd_per_barrel"` // ドル/バレル
+	Source       string  `json:"source"`         // データソース
+	CreatedAt    int64   `json:"created_at"`     // 作成タイムスタンプ
+	UpdatedAt    int64   `json:"updated_at"`     // 更新タイムスタンプ
+}
+
+// NewCrudePrice 新しいCrudePriceインスタンスを作成
+func NewCrudePrice(date string, usdPerBarrel float64) *CrudePrice {
+	now := time.Now().Unix()
+	return &CrudePrice{
+		ID:           date,
+		Date:         date,
+		USDPerBarrel: usdPerBarrel,
+		Source:       "manual",
+		CreatedAt:    now,
+		UpdatedAt:    now,
+	}
+}
+
+// YenPerLiter 円建て・リットルあたりの原油価格を計算
+func (c *CrudePrice) YenPerLiter(usdJpy float64) float64 {
+	return c.USDPerBarrel * usdJpy / LitersPerBarrel
+}
+
+// FuelTaxRates 燃料にかかる税率（円/L）
+type FuelTaxRates struct {
+	EffectiveFrom      string  `json:"effective_from"`       // 適用開始日
+	GasolineTax        float64 `json:"gasoline_tax"`         // 揮発油税＋地方揮発油税（本則）
+	GasolineProvision  float64 `json:"gasoline_provision"`   // 揮発油税の暫定税率（上乗せ分）
+	DieselTax          float64 `json:"diesel_tax"`           // 軽油引取税（本則）
+	DieselProvision    float64 `json:"diesel_provision"`     // 軽油引取税の暫定税率（上乗せ分）
+	PetroleumCoalTax   float64 `json:"petroleum_coal_tax"`   // 石油石炭税（地球温暖化対策税を含む）
+	ConsumptionTaxRate float64 `json:"consumption_tax_rate"` // 消費税率
+}
+
+// FuelTaxSchedule 税率の改定履歴（適用開始日の昇順）
+var FuelTaxSchedule = []FuelTaxRates{
+	{
+		EffectiveFrom:      "2019-10-01",
+		GasolineTax:        28.7,
+		GasolineProvision:  25.1,
+		DieselTax:          15.0,
+		DieselProvision:    17.1,
+		PetroleumCoalTax:   2.8,
+		ConsumptionTaxRate: 0.10,
+	},
+	{
+		// ガソリンの暫定税率廃止
+		EffectiveFrom:      "2025-12-31",
+		GasolineTax:        28.7,
+		GasolineProvision:  0,
+		DieselTax:          15.0,
+		DieselProvision:    17.1,
+		PetroleumCoalTax:   2.8,
+		ConsumptionTaxRate: 0.10,
+	},
+	{
+		// 軽油引取税の暫定税率廃止
+		EffectiveFrom:      "2026-04-01",
+		GasolineTax:        28.7,
+		GasolineProvision:  0,
+		DieselTax:          15.0,
+		DieselProvision:    0,
+		PetroleumCoalTax:   2.8,
+		ConsumptionTaxRate: 0.10,
+	},
+}
+
+// TaxRatesForDate 指定日付に適用される税率を取得
+func TaxRatesForDate(date string) FuelTaxRates {
+	rates := FuelTaxSchedule[0]
+	for _, r := range FuelTaxSchedule {
+		if r.EffectiveFrom <= date {
+			rates = r
+		}
+	}
+	return rates
+}
@@ -14,16 +14,22 @@ type GasPrice struct {
 	UpdatedAt    int64   `json:"updated_at"`
 }
 
+// GasPriceID 日付・地域・データソースから決まるID（ガソリン価格はこの組み合わせごとに1件）
+func GasPriceID(date, region, source string) string {
+	return date + "_" + region + "_" + source
+}
+
 func NewGasPrice(date, region string, regular, premium, diesel float64) *GasPrice {
 	now := time.Now().Unix()
+	const source = "e-nenpi"
 	return &GasPrice{
-		ID:           date,
+		ID:           GasPriceID(date, region, source),
 		Date:         date,
 		RegularPrice: regular,
 		PremiumPrice: premium,
 		DieselPrice:  diesel,
 		Region:       region,
-		Source:       "e-nenpi",
+		Source:       source,
 		CreatedAt:    now,
 		UpdatedAt:    now,
 	}
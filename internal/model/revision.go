@@ -0,0 +1,23 @@
+package models
+
+// GasPriceRevision ガソリン価格の改定履歴（日付・地域・データソースごとに、値が変わるたびに追記する）
+type GasPriceRevision struct {
+	ID           int64   `json:"id"`
+	Date         string  `json:"date"`
+	Region       string  `json:"region"`
+	Source       string  `json:"source"`
+	RegularPrice float64 `json:"regular_price"`
+	PremiumPrice float64 `json:"premium_price"`
+	DieselPrice  float64 `json:"diesel_price"`
+	RecordedAt   int64   `json:"recorded_at"` // この値を記録した日時（UNIX時刻）
+}
+
+// ExchangeRateRevision 為替レートの改定履歴（日付・通貨・データソースごとに、値が変わるたびに追記する）
+type ExchangeRateRevision struct {
+	ID         int64   `json:"id"`
+	Date       string  `json:"date"`
+	Currency   string  `json:"currency"` // USD/EUR/GBP/CNY
+	Source     string  `json:"source"`
+	Rate       float64 `json:"rate"` // 円/通貨
+	RecordedAt int64   `json:"recorded_at"`
+}
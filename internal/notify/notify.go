@@ -0,0 +1,94 @@
+// Package notify レポートやアラートを外部サービスへ通知する
+package notify
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"time"
+)
+
+// Message 通知内容（BodyはMarkdown）
+type Message struct {
+	Title string `json:"title"`
+	Body  string `json:"body"`
+}
+
+// Notifier 通知先
+type Notifier interface {
+	Name() string
+	Notify(ctx context.Context, msg Message) error
+}
+
+// New 種類と送信先URLから通知先を作成（urlが空なら環境変数から取得）
+//   - slack:   Slack Incoming Webhook（SLACK_WEBHOOK_URL）
+//   - webhook: 任意のURLへ Message をJSONでPOST（NOTIFY_WEBHOOK_URL）
+func New(kind, url string) (Notifier, error) {
+	switch kind {
+	case "slack":
+		if url == "" {
+			url = os.Getenv("SLACK_WEBHOOK_URL")
+		}
+		if url == "" {
+			return nil, fmt.Errorf("SLACK_WEBHOOK_URLが設定されていません")
+		}
+		return NewSlackNotifier(url), nil
+	case "webhook":
+		if url == "" {
+			url = os.Getenv("NOTIFY_WEBHOOK_URL")
+		}
+		if url == "" {
+			return nil, fmt.Errorf("NOTIFY_WEBHOOK_URLが設定されていません")
+		}
+		return NewWebhookNotifier(url), nil
+	}
+	return nil, fmt.Errorf("不明な通知先: %s（slack/webhook）", kind)
+}
+
+// WebhookNotifier 任意のURLへ Message をJSONでPOSTする
+type WebhookNotifier struct {
+	url    string
+	client *http.Client
+}
+
+// NewWebhookNotifier Webhook通知先を作成
+func NewWebhookNotifier(url string) *WebhookNotifier {
+	return &WebhookNotifier{url: url, client: &http.Client{Timeout: 15 * time.Second}}
+}
+
+func (n *WebhookNotifier) Name() string { return "webhook" }
+
+// Notify メッセージを送信
+func (n *WebhookNotifier) Notify(ctx context.Context, msg Message) error {
+	return postJSON(ctx, n.client, n.url, msg)
+}
+
+// postJSON JSONをPOSTし、2xx以外はエラーにする
+func postJSON(ctx context.Context, client *http.Client, url string, payload interface{}) error {
+	body, err := json.Marshal(payload)
+	if err != nil {
+		return fmt.Errorf("通知内容変換エラー: %w", err)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, url, bytes.NewReader(body))
+	if err != nil {
+		return fmt.Errorf("通知リクエスト作成エラー: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := client.Do(req)
+	if err != nil {
+		return fmt.Errorf("通知送信エラー: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode/100 != 2 {
+		detail, _ := io.ReadAll(io.LimitReader(resp.Body, 512))
+		return fmt.Errorf("通知送信エラー: status=%d %s", resp.StatusCode, bytes.TrimSpace(detail))
+	}
+	return nil
+}
@@ -0,0 +1,67 @@
+package notify
+
+import (
+	"context"
+	"net/http"
+	"regexp"
+	"strings"
+	"time"
+)
+
+// slackMaxText Slackの1メッセージあたりの文字数上限（超える分は切り詰める）
+const slackMaxText = 39000
+
+// SlackNotifier Slack Incoming Webhookへ通知する
+type SlackNotifier struct {
+	webhookURL string
+	client     *http.Client
+}
+
+// NewSlackNotifier Slack通知先を作成
+func NewSlackNotifier(webhookURL string) *SlackNotifier {
+	return &SlackNotifier{webhookURL: webhookURL, client: &http.Client{Timeout: 15 * time.Second}}
+}
+
+func (n *SlackNotifier) Name() string { return "slack" }
+
+// Notify MarkdownをSlackのmrkdwnに変換して送信
+// 本文が件名と同じ見出しで始まる場合は件名を重ねない
+func (n *SlackNotifier) Notify(ctx context.Context, msg Message) error {
+	body := strings.TrimPrefix(msg.Body, "# "+msg.Title+"\n")
+	text := "*" + msg.Title + "*\n" + SlackMarkdown(body)
+	if r := []rune(text); len(r) > slackMaxText {
+		text = string(r[:slackMaxText]) + "\n…（省略）"
+	}
+	return postJSON(ctx, n.client, n.webhookURL, map[string]string{"text": text})
+}
+
+var (
+	mdBold = regexp.MustCompile(`\*\*(.+?)\*\*`)
+	mdLink = regexp.MustCompile(`\[([^\]]+)\]\((https?://[^)\s]+)\)`)
+)
+
+// SlackMarkdown 見出し・太字・リンクをmrkdwnに変換し、表はコードブロックで囲む
+func SlackMarkdown(md string) string {
+	var b strings.Builder
+	inTable := false
+	for _, line := range strings.Split(md, "\n") {
+		isTable := strings.HasPrefix(strings.TrimSpace(line), "|")
+		if isTable != inTable {
+			b.WriteString("```\n")
+			inTable = isTable
+		}
+		if !isTable {
+			if trimmed := strings.TrimLeft(line, "#"); trimmed != line {
+				line = "*" + strings.TrimSpace(trimmed) + "*"
+			}
+			line = mdBold.ReplaceAllString(line, "*$1*")
+			line = mdLink.ReplaceAllString(line, "<$2|$1>")
+		}
+		b.WriteString(line)
+		b.WriteString("\n")
+	}
+	if inTable {
+		b.WriteString("```\n")
+	}
+	return strings.TrimRight(b.String(), "\n")
+}
@@ -0,0 +1,161 @@
+package pricing
+
+import (
+	"fmt"
+	"time"
+
+	model "gasinsight/internal/model"
+)
+
+// 燃料の種類
+const (
+	FuelRegular = "regular"
+	FuelPremium = "premium"
+	FuelDiesel  = "diesel"
+)
+
+// FuelTypes 分解対象の燃料種別
+var FuelTypes = []string{FuelRegular, FuelPremium, FuelDiesel}
+
+// Decomposition 小売価格の内訳（全て円/L）
+type Decomposition struct {
+	Date             string  `json:"date"`
+	Region           string  `json:"region"`
+	FuelType         string  `json:"fuel_type"`
+	RetailPrice      float64 `json:"retail_price"`       // 小売価格（税込）
+	CrudeCost        float64 `json:"crude_cost"`         // 原油コスト
+	Margin           float64 `json:"margin"`             // 精製・流通コスト＋マージン
+	FuelTax          float64 `json:"fuel_tax"`           // 揮発油税 / 軽油引取税（本則）
+	ProvisionalTax   float64 `json:"provisional_tax"`    // 暫定税率分
+	PetroleumCoalTax float64 `json:"petroleum_coal_tax"` // 石油石炭税
+	ConsumptionTax   float64 `json:"consumption_tax"`    // 消費税（Tax on Taxを含む）
+	Subsidy          float64 `json:"subsidy"`            // 補助金による値引き
+	TaxTotal         float64 `json:"tax_total"`          // 税金合計
+	HasCrude         bool    `json:"has_crude"`          // 原油データの有無（falseの場合Marginは原油コスト込み）
+}
+
+// Inputs 価格分解に必要な外部データ
+type Inputs struct {
+	CrudeYenPerLiter float64 // 円建て原油価格（円/L）、不明なら0
+	HasCrude         bool
+	Subsidy          float64 // 補助金（円/L）
+	Rates            model.FuelTaxRates
+}
+
+// Decompose 小売価格を原油コスト・マージン・税金・補助金に分解
+//
+// ガソリン: 小売価格 = (原油 + マージン + 揮発油税 + 暫定税率 + 石油石炭税 - 補助金) × (1 + 消費税率)
+// 軽油:     小売価格 = (原油 + マージン + 石油石炭税 - 補助金) × (1 + 消費税率) + 軽油引取税
+func Decompose(date, region, fuelType string, retail float64, in Inputs) *Decomposition {
+	r := in.Rates
+	d := &Decomposition{
+		Date:             date,
+		Region:           region,
+		FuelType:         fuelType,
+		RetailPrice:      retail,
+		PetroleumCoalTax: r.PetroleumCoalTax,
+		Subsidy:          in.Subsidy,
+		HasCrude:         in.HasCrude,
+	}
+	if in.HasCrude {
+		d.CrudeCost = in.CrudeYenPerLiter
+	}
+
+	var taxableBase float64
+	if fuelType == FuelDiesel {
+		// 軽油引取税は消費税の課税対象外
+		d.FuelTax = r.DieselTax
+		d.ProvisionalTax = r.DieselProvision
+		taxableBase = (retail - d.FuelTax - d.ProvisionalTax) / (1 + r.ConsumptionTaxRate)
+		d.Margin = taxableBase - d.CrudeCost - d.PetroleumCoalTax + d.Subsidy
+	} else {
+		d.FuelTax = r.GasolineTax
+		d.ProvisionalTax = r.GasolineProvision
+		taxableBase = retail / (1 + r.ConsumptionTaxRate)
+		d.Margin = taxableBase - d.CrudeCost - d.FuelTax - d.ProvisionalTax - d.PetroleumCoalTax + d.Subsidy
+	}
+
+	d.ConsumptionTax = taxableBase * r.ConsumptionTaxRate
+	d.TaxTotal = d.FuelTax + d.ProvisionalTax + d.PetroleumCoalTax + d.ConsumptionTax
+	return d
+}
+
+// Source 価格分解に使用するデータの取得元
+type Source interface {
+	GetFuelSubsidyByWeek(weekStart string) (*model.FuelSubsidy, error)
+	GetCrudePriceOnOrBefore(date string) (*model.CrudePrice, error)
+	GetExchangeRateOnOrBefore(date string) (*model.ExchangeRate, error)
+}
+
+// DecomposeGasPrice 保存済みのガソリン価格を燃料種別ごとに分解
+// 小売価格が0以下（未掲載）の燃料種別は分解しない
+func DecomposeGasPrice(src Source, price *model.GasPrice) ([]*Decomposition, error) {
+	retail := map[string]float64{
+		FuelRegular: price.RegularPrice,
+		FuelPremium: price.PremiumPrice,
+		FuelDiesel:  price.DieselPrice,
+	}
+	var fuels []string
+	for _, fuel := range FuelTypes {
+		if retail[fuel] > 0 {
+			fuels = append(fuels, fuel)
+		}
+	}
+	if len(fuels) == 0 {
+		return nil, fmt.Errorf("%s（%s）の小売価格がありません", price.Date, price.Region)
+	}
+
+	in, err := LoadInputs(src, price.Date)
+	if err != nil {
+		return nil, err
+	}
+
+	var result []*Decomposition
+	for _, fuel := range fuels {
+		result = append(result, Decompose(price.Date, price.Region, fuel, retail[fuel], in))
+	}
+	return result, nil
+}
+
+// LoadInputs 指定日付の補助金・原油価格・税率を取得
+func LoadInputs(src Source, date string) (Inputs, error) {
+	in := Inputs{Rates: model.TaxRatesForDate(date)}
+
+	weekStart, err := WeekStart(date)
+	if err != nil {
+		return in, err
+	}
+
+	subsidy, err := src.GetFuelSubsidyByWeek(weekStart)
+	if err != nil {
+		return in, fmt.Errorf("補助金取得エラー: %w", err)
+	}
+	if subsidy != nil {
+		in.Subsidy = subsidy.Amount
+	}
+
+	crude, err := src.GetCrudePriceOnOrBefore(date)
+	if err != nil {
+		return in, fmt.Errorf("原油価格取得エラー: %w", err)
+	}
+	rate, err := src.GetExchangeRateOnOrBefore(date)
+	if err != nil {
+		return in, fmt.Errorf("為替レート取得エラー: %w", err)
+	}
+	if crude != nil && rate != nil && rate.USDJPY > 0 {
+		in.CrudeYenPerLiter = crude.YenPerLiter(rate.USDJPY)
+		in.HasCrude = true
+	}
+
+	return in, nil
+}
+
+// WeekStart 日付が属する週の月曜日を返す（YYYY-MM-DD）
+func WeekStart(date string) (string, error) {
+	t, err := time.Parse("2006-01-02", date)
+	if err != nil {
+		return "", fmt.Errorf("日付形式エラー: %s", date)
+	}
+	offset := (int(t.Weekday()) + 6) % 7
+	return t.AddDate(0, 0, -offset).Format("2006-01-02"), nil
+}
@@ -0,0 +1,55 @@
+package pricing
+
+import (
+	"math"
+	"testing"
+
+	model "gasinsight/internal/model"
+)
+
+// stubSource 補助金・原油価格・為替レートを固定で返す取得元
+type stubSource struct {
+	subsidy *model.FuelSubsidy
+	crude   *model.CrudePrice
+	rate    *model.ExchangeRate
+}
+
+func (s stubSource) GetFuelSubsidyByWeek(string) (*model.FuelSubsidy, error) {
+	return s.subsidy, nil
+}
+func (s stubSource) GetCrudePriceOnOrBefore(string) (*model.CrudePrice, error) {
+	return s.crude, nil
+}
+func (s stubSource) GetExchangeRateOnOrBefore(string) (*model.ExchangeRate, error) {
+	return s.rate, nil
+}
+
+func TestDecomposeGasPriceSkipsMissingFuel(t *testing.T) {
+	src := stubSource{
+		subsidy: model.NewFuelSubsidy("2025-01-06", 10),
+		crude:   model.NewCrudePrice("2025-01-06", 75),
+		rate:    model.NewExchangeRate("2025-01-06", 157.2, 162.8, 196.1, 21.5),
+	}
+	result, err := DecomposeGasPrice(src, model.NewGasPrice("2025-01-08", "全国平均", 175, 186, 0))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(result) != 2 || result[0].FuelType != FuelRegular || result[1].FuelType != FuelPremium {
+		t.Fatalf("分解結果 = %+v, want レギュラーとハイオクのみ", result)
+	}
+	for _, d := range result {
+		share := d.TaxTotal / d.RetailPrice * 100
+		if math.IsNaN(share) || math.IsInf(share, 0) || share <= 0 {
+			t.Errorf("%s: 税金の割合 = %v", d.FuelType, share)
+		}
+		if !d.HasCrude || d.Subsidy != 10 {
+			t.Errorf("%s: 入力が反映されていません: %+v", d.FuelType, d)
+		}
+	}
+}
+
+func TestDecomposeGasPriceWithoutRetailPrice(t *testing.T) {
+	if _, err := DecomposeGasPrice(stubSource{}, model.NewGasPrice("2025-01-08", "全国平均", 0, 0, 0)); err == nil {
+		t.Error("小売価格がなくてもエラーになりません")
+	}
+}
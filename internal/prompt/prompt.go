@@ -0,0 +1,114 @@
+// Package prompt LLMに渡すプロンプトをtext/templateファイルから読み込む
+//
+// テンプレートは <dir>/<名前>/<バージョン>.tmpl に置く（例: prompts/news_analysis/v2.tmpl）。
+// プロンプトを変更するときは既存ファイルを書き換えず新しいバージョンを追加し、
+// 分析結果に記録されたバージョンで出力を比較できるようにする。
+package prompt
+
+import (
+	"bytes"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"text/template"
+)
+
+// DefaultDir プロンプトテンプレートのデフォルトの配置先
+const DefaultDir = "./prompts"
+
+const ext = ".tmpl"
+
+// Template バージョン付きのプロンプトテンプレート
+type Template struct {
+	Name    string
+	Version string
+	tmpl    *template.Template
+}
+
+// ID 分析結果に記録する識別子（例: news_analysis@v1）
+func (t *Template) ID() string {
+	return t.Name + "@" + t.Version
+}
+
+// Render テンプレートにデータを埋め込んでプロンプトを作成
+func (t *Template) Render(data any) (string, error) {
+	var b bytes.Buffer
+	if err := t.tmpl.Execute(&b, data); err != nil {
+		return "", fmt.Errorf("プロンプト作成エラー (%s): %w", t.ID(), err)
+	}
+	return b.String(), nil
+}
+
+// funcs テンプレートで使える関数
+var funcs = template.FuncMap{
+	"inc": func(i int) int { return i + 1 },
+}
+
+// Store プロンプトテンプレートの保存先
+type Store struct {
+	dir string
+}
+
+// NewStore プロンプトの保存先を作成（dirが空ならDefaultDir）
+func NewStore(dir string) *Store {
+	if dir == "" {
+		dir = DefaultDir
+	}
+	return &Store{dir: dir}
+}
+
+// Versions テンプレートのバージョン一覧（古い順）
+func (s *Store) Versions(name string) ([]string, error) {
+	entries, err := os.ReadDir(filepath.Join(s.dir, name))
+	if err != nil {
+		return nil, fmt.Errorf("プロンプト一覧取得エラー (%s): %w", name, err)
+	}
+
+	var versions []string
+	for _, e := range entries {
+		if !e.IsDir() && strings.HasSuffix(e.Name(), ext) {
+			versions = append(versions, strings.TrimSuffix(e.Name(), ext))
+		}
+	}
+	sort.Slice(versions, func(i, j int) bool { return versionLess(versions[i], versions[j]) })
+	return versions, nil
+}
+
+// Load テンプレートを読み込む（versionが空なら最新バージョン）
+func (s *Store) Load(name, version string) (*Template, error) {
+	if version == "" {
+		versions, err := s.Versions(name)
+		if err != nil {
+			return nil, err
+		}
+		if len(versions) == 0 {
+			return nil, fmt.Errorf("プロンプト %s が %s にありません", name, s.dir)
+		}
+		version = versions[len(versions)-1]
+	}
+
+	path := filepath.Join(s.dir, name, version+ext)
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("プロンプト読み込みエラー: %w", err)
+	}
+
+	tmpl, err := template.New(name).Funcs(funcs).Option("missingkey=error").Parse(string(data))
+	if err != nil {
+		return nil, fmt.Errorf("プロンプト解析エラー (%s): %w", path, err)
+	}
+	return &Template{Name: name, Version: version, tmpl: tmpl}, nil
+}
+
+// versionLess "v2" < "v10" となるよう数値部分を比較
+func versionLess(a, b string) bool {
+	na, errA := strconv.Atoi(strings.TrimPrefix(a, "v"))
+	nb, errB := strconv.Atoi(strings.TrimPrefix(b, "v"))
+	if errA == nil && errB == nil {
+		return na < nb
+	}
+	return a < b
+}
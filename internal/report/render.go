@@ -0,0 +1,155 @@
+package report
+
+import (
+	"bytes"
+	"fmt"
+	"html/template"
+	"strings"
+)
+
+// Markdown ダイジェストをMarkdownで出力
+func (d *Digest) Markdown() string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "# %s\n\n", d.Title())
+	fmt.Fprintf(&b, "作成: %s（日本時間）\n\n", d.GeneratedAt)
+
+	b.WriteString("## ガソリン価格\n\n")
+	if len(d.Prices) == 0 {
+		b.WriteString("期間内のデータがありません。\n\n")
+	} else {
+		b.WriteString("| 燃料 | 地域 | 日付 | 価格 | 前週比 | 期間の変化 |\n")
+		b.WriteString("|---|---|---|---:|---:|---:|\n")
+		for _, p := range d.Prices {
+			wow := "-"
+			if p.WoWChange != nil {
+				wow = fmt.Sprintf("%+.2f円（%+.2f%%）", *p.WoWChange, *p.WoWPct)
+			}
+			fmt.Fprintf(&b, "| %s | %s | %s | %.2f円 | %s | %s |\n",
+				p.FuelLabel, p.Region, p.Date, p.Price, wow, signedYen(p.PeriodChange))
+		}
+		b.WriteString("\n")
+	}
+
+	b.WriteString("## 為替\n\n")
+	if len(d.FX) == 0 {
+		b.WriteString("期間内のデータがありません。\n\n")
+	} else {
+		b.WriteString("| 通貨 | 期間開始 | 期間終了 | 変化 | 最安値 | 最高値 |\n")
+		b.WriteString("|---|---:|---:|---:|---:|---:|\n")
+		for _, r := range d.FX {
+			fmt.Fprintf(&b, "| %s/JPY | %.2f（%s） | %.2f（%s） | %+.2f（%+.2f%%） | %.2f | %.2f |\n",
+				r.Currency, r.Start, r.StartDate, r.End, r.EndDate, r.Change, r.ChangePct, r.Min, r.Max)
+		}
+		b.WriteString("\n")
+	}
+
+	fmt.Fprintf(&b, "## 注目ニュース（%d件中 上位%d件）\n\n", d.NewsCount, len(d.News))
+	if len(d.News) == 0 {
+		b.WriteString("期間内のニュースがありません。\n\n")
+	}
+	for i, n := range d.News {
+		title := n.Title
+		if n.URL != "" {
+			title = fmt.Sprintf("[%s](%s)", n.Title, n.URL)
+		}
+		fmt.Fprintf(&b, "%d. **%s**\n", i+1, title)
+		fmt.Fprintf(&b, "   - %s %s | 感情: %s（%+.2f） | 影響度: %.2f | 方向: %s\n",
+			n.Date, n.Source, n.SentimentLabel(), n.SentimentScore, n.ImpactScore, n.Direction)
+		for _, line := range strings.Split(n.Summary, "\n") {
+			if line = strings.TrimSpace(line); line != "" {
+				fmt.Fprintf(&b, "   - %s\n", line)
+			}
+		}
+	}
+	if len(d.News) > 0 {
+		b.WriteString("\n")
+	}
+
+	b.WriteString("## アラート\n\n")
+	if len(d.Alerts) == 0 {
+		b.WriteString("期間内のアラートはありません。\n")
+	}
+	for _, a := range d.Alerts {
+		fmt.Fprintf(&b, "- %s [%s] %s\n", a.Date, a.Kind, a.Message)
+	}
+	return b.String()
+}
+
+// HTML ダイジェストをHTMLで出力
+func (d *Digest) HTML() (string, error) {
+	var buf bytes.Buffer
+	if err := htmlTemplate.Execute(&buf, d); err != nil {
+		return "", fmt.Errorf("HTMLレポート作成エラー: %w", err)
+	}
+	return buf.String(), nil
+}
+
+// signedYen 符号付きの円表示（nilなら-）
+func signedYen(v *float64) string {
+	if v == nil {
+		return "-"
+	}
+	return fmt.Sprintf("%+.2f円", *v)
+}
+
+var htmlTemplate = template.Must(template.New("report").Funcs(template.FuncMap{
+	"signedYen": signedYen,
+	"deref":     func(v *float64) float64 { return *v },
+	"lines": func(s string) []string {
+		var out []string
+		for _, line := range strings.Split(s, "\n") {
+			if line = strings.TrimSpace(line); line != "" {
+				out = append(out, line)
+			}
+		}
+		return out
+	},
+}).Parse(`<!DOCTYPE html>
+<html lang="ja">
+<head>
+<meta charset="utf-8">
+<title>{{.Title}}</title>
+<style>
+body { font-family: sans-serif; max-width: 960px; margin: 2em auto; color: #222; }
+table { border-collapse: collapse; margin-bottom: 1.5em; }
+th, td { border: 1px solid #ccc; padding: 4px 8px; }
+td.num { text-align: right; }
+.meta { color: #666; font-size: 0.9em; }
+</style>
+</head>
+<body>
+<h1>{{.Title}}</h1>
+<p class="meta">作成: {{.GeneratedAt}}（日本時間）</p>
+
+<h2>ガソリン価格</h2>
+{{if .Prices}}<table>
+<tr><th>燃料</th><th>地域</th><th>日付</th><th>価格</th><th>前週比</th><th>期間の変化</th></tr>
+{{range .Prices}}<tr><td>{{.FuelLabel}}</td><td>{{.Region}}</td><td>{{.Date}}</td><td class="num">{{printf "%.2f" .Price}}円</td><td class="num">{{if .WoWChange}}{{printf "%+.2f" (deref .WoWChange)}}円（{{printf "%+.2f" (deref .WoWPct)}}%）{{else}}-{{end}}</td><td class="num">{{signedYen .PeriodChange}}</td></tr>
+{{end}}</table>
+{{else}}<p>期間内のデータがありません。</p>
+{{end}}
+<h2>為替</h2>
+{{if .FX}}<table>
+<tr><th>通貨</th><th>期間開始</th><th>期間終了</th><th>変化</th><th>最安値</th><th>最高値</th></tr>
+{{range .FX}}<tr><td>{{.Currency}}/JPY</td><td class="num">{{printf "%.2f" .Start}}（{{.StartDate}}）</td><td class="num">{{printf "%.2f" .End}}（{{.EndDate}}）</td><td class="num">{{printf "%+.2f" .Change}}（{{printf "%+.2f" .ChangePct}}%）</td><td class="num">{{printf "%.2f" .Min}}</td><td class="num">{{printf "%.2f" .Max}}</td></tr>
+{{end}}</table>
+{{else}}<p>期間内のデータがありません。</p>
+{{end}}
+<h2>注目ニュース（{{.NewsCount}}件中 上位{{len .News}}件）</h2>
+{{if .News}}<ol>
+{{range .News}}<li><strong>{{if .URL}}<a href="{{.URL}}">{{.Title}}</a>{{else}}{{.Title}}{{end}}</strong>
+<div class="meta">{{.Date}} {{.Source}} | 感情: {{.SentimentLabel}}（{{printf "%+.2f" .SentimentScore}}） | 影響度: {{printf "%.2f" .ImpactScore}} | 方向: {{.Direction}}</div>
+{{range lines .Summary}}<p>{{.}}</p>
+{{end}}</li>
+{{end}}</ol>
+{{else}}<p>期間内のニュースがありません。</p>
+{{end}}
+<h2>アラート</h2>
+{{if .Alerts}}<ul>
+{{range .Alerts}}<li>{{.Date}} [{{.Kind}}] {{.Message}}</li>
+{{end}}</ul>
+{{else}}<p>期間内のアラートはありません。</p>
+{{end}}
+</body>
+</html>
+`))
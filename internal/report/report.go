@@ -0,0 +1,358 @@
+// Package report 期間内の価格・為替・ニュース・アラートをまとめたダイジェストを作成する
+package report
+
+import (
+	"fmt"
+	"math"
+	"sort"
+	"time"
+
+	"gasinsight/internal/database"
+	"gasinsight/internal/detect"
+	model "gasinsight/internal/model"
+	"gasinsight/internal/timeseries"
+)
+
+// DefaultTopNews ダイジェストに載せるニュースの件数
+const DefaultTopNews = 5
+
+const (
+	historyDays = 120 // 開始日より前に読み込む日数（異常検知の基準期間・前週比・期間の基準点に使う）
+	weekAgoDays = 7   // 前週比の基準日は最新日のこの日数前以前で直近
+	weekAgoMax  = 14  // 最新日からこの日数より前の点は前週比の基準にしない
+)
+
+// Fuels 燃料種別と表示名
+var Fuels = []struct{ Key, Label string }{
+	{"regular", "レギュラー"},
+	{"premium", "ハイオク"},
+	{"diesel", "軽油"},
+}
+
+// Currencies 為替の対象通貨
+var Currencies = []string{"USD", "EUR", "GBP", "CNY"}
+
+// Source レポートに使用するデータの取得元
+type Source interface {
+	ListGasPrices(q database.ListQuery) ([]*model.GasPrice, string, error)
+	ListExchangeRates(q database.ListQuery) ([]*model.ExchangeRate, string, error)
+	GetNewsBetween(from, to string) ([]*detect.AnalyzedNews, error)
+	GetFlaggedPriceChangesBetween(from, to string) ([]*model.PriceChangeRecord, error)
+}
+
+// Options レポートの設定
+type Options struct {
+	TopNews int // 影響度の高い順に載せるニュースの件数（0ならDefaultTopNews）
+}
+
+// PriceRow 燃料種別・地域ごとの価格
+type PriceRow struct {
+	Fuel         string   `json:"fuel"`
+	FuelLabel    string   `json:"fuel_label"`
+	Region       string   `json:"region"`
+	Date         string   `json:"date"`                    // 期間内の最新日
+	Price        float64  `json:"price"`                   // 最新価格（円/L）
+	WeekAgoDate  string   `json:"week_ago_date,omitempty"` // 前週比の基準日（最新日の7〜14日前で直近。なければ前週比なし）
+	WoWChange    *float64 `json:"wow_change,omitempty"`    // 前週比（円）
+	WoWPct       *float64 `json:"wow_pct,omitempty"`       // 前週比（%）
+	PeriodChange *float64 `json:"period_change,omitempty"` // 期間開始時点からの変化（円）
+}
+
+// FXRow 通貨ごとの為替の動き
+type FXRow struct {
+	Currency  string  `json:"currency"`
+	StartDate string  `json:"start_date"`
+	Start     float64 `json:"start"` // 期間開始時点のレート（開始日より前で直近、なければ期間内の最初）
+	EndDate   string  `json:"end_date"`
+	End       float64 `json:"end"`
+	Change    float64 `json:"change"`
+	ChangePct float64 `json:"change_pct"`
+	Min       float64 `json:"min"` // 期間内の最小値
+	Max       float64 `json:"max"` // 期間内の最大値
+}
+
+// NewsItem 影響度の高いニュース
+type NewsItem struct {
+	Date           string                `json:"date"`
+	Title          string                `json:"title"`
+	Summary        string                `json:"summary"`
+	URL            string                `json:"url"`
+	Source         string                `json:"source"`
+	Sentiment      detect.Sentiment      `json:"sentiment"`
+	SentimentScore float64               `json:"sentiment_score"`
+	ImpactScore    float64               `json:"impact_score"`
+	Direction      detect.PriceDirection `json:"direction"`
+}
+
+// SentimentLabel 感情の表示名
+func (n NewsItem) SentimentLabel() string { return n.Sentiment.Label() }
+
+// Alert 期間内のアラート
+type Alert struct {
+	Date    string `json:"date"`
+	Kind    string `json:"kind"`   // price_change / zscore / ewma / streak
+	Series  string `json:"series"` // 地域 または 系列名（gas/regular/全国平均 など）
+	Message string `json:"message"`
+}
+
+// Digest 期間のダイジェスト
+type Digest struct {
+	From        string     `json:"from"`
+	To          string     `json:"to"`
+	GeneratedAt string     `json:"generated_at"`
+	Prices      []PriceRow `json:"prices"`
+	FX          []FXRow    `json:"fx"`
+	NewsCount   int        `json:"news_count"` // 期間内のニュース件数
+	News        []NewsItem `json:"news"`
+	Alerts      []Alert    `json:"alerts"`
+}
+
+// Title 通知の件名などに使うタイトル
+func (d *Digest) Title() string {
+	if d.From == d.To {
+		return fmt.Sprintf("GasInsight デイリーレポート %s", d.To)
+	}
+	return fmt.Sprintf("GasInsight レポート %s〜%s", d.From, d.To)
+}
+
+// Build 期間（YYYY-MM-DD, 両端含む）のダイジェストを作成
+func Build(src Source, from, to string, opts Options) (*Digest, error) {
+	start, err := timeseries.ParseDate(from)
+	if err != nil {
+		return nil, fmt.Errorf("開始日の形式エラー: %w", err)
+	}
+	end, err := timeseries.ParseDate(to)
+	if err != nil {
+		return nil, fmt.Errorf("終了日の形式エラー: %w", err)
+	}
+	if end.Before(start) {
+		return nil, fmt.Errorf("期間の指定が不正です: %s〜%s", from, to)
+	}
+	if opts.TopNews <= 0 {
+		opts.TopNews = DefaultTopNews
+	}
+
+	q := database.ListQuery{
+		From: timeseries.FormatDate(start.AddDate(0, 0, -historyDays)),
+		To:   timeseries.FormatDate(end),
+		Sort: "date",
+	}
+	prices, _, err := src.ListGasPrices(q)
+	if err != nil {
+		return nil, fmt.Errorf("ガソリン価格取得エラー: %w", err)
+	}
+	rates, _, err := src.ListExchangeRates(q)
+	if err != nil {
+		return nil, fmt.Errorf("為替レート取得エラー: %w", err)
+	}
+	news, err := src.GetNewsBetween(from, to)
+	if err != nil {
+		return nil, fmt.Errorf("ニュース取得エラー: %w", err)
+	}
+	changes, err := src.GetFlaggedPriceChangesBetween(from, to)
+	if err != nil {
+		return nil, err
+	}
+
+	d := &Digest{
+		From:        timeseries.FormatDate(start),
+		To:          timeseries.FormatDate(end),
+		GeneratedAt: time.Now().In(timeseries.JST).Format("2006-01-02 15:04"),
+		Prices:      priceRows(prices, start, end),
+		FX:          fxRows(rates, start, end),
+		NewsCount:   len(news),
+		News:        topNews(news, opts.TopNews),
+		Alerts:      alerts(changes, prices, rates, start, end),
+	}
+	return d, nil
+}
+
+// priceRows 燃料種別・地域ごとの最新価格と前週比
+func priceRows(prices []*model.GasPrice, start, end time.Time) []PriceRow {
+	regions := map[string]bool{}
+	for _, p := range prices {
+		regions[p.Region] = true
+	}
+	names := make([]string, 0, len(regions))
+	for r := range regions {
+		names = append(names, r)
+	}
+	sort.Strings(names)
+
+	var rows []PriceRow
+	for _, region := range names {
+		for _, fuel := range Fuels {
+			s := timeseries.FromGasPrices(prices, fuel.Key, region)
+			latest, ok := lastOnOrBefore(s, end)
+			if !ok || latest.Date.Before(start) {
+				continue
+			}
+			row := PriceRow{
+				Fuel:      fuel.Key,
+				FuelLabel: fuel.Label,
+				Region:    region,
+				Date:      timeseries.FormatDate(latest.Date),
+				Price:     latest.Value,
+			}
+			prev, ok := lastOnOrBefore(s, latest.Date.AddDate(0, 0, -weekAgoDays))
+			if ok && !prev.Date.Before(latest.Date.AddDate(0, 0, -weekAgoMax)) {
+				row.WeekAgoDate = timeseries.FormatDate(prev.Date)
+				row.WoWChange = ptr(latest.Value - prev.Value)
+				row.WoWPct = ptr((latest.Value - prev.Value) / prev.Value * 100)
+			}
+			if base, ok := periodStart(s, start); ok && base.Date.Before(latest.Date) {
+				row.PeriodChange = ptr(latest.Value - base.Value)
+			}
+			rows = append(rows, row)
+		}
+	}
+	return rows
+}
+
+// fxRows 通貨ごとの期間中の動き
+func fxRows(rates []*model.ExchangeRate, start, end time.Time) []FXRow {
+	var rows []FXRow
+	for _, currency := range Currencies {
+		s := timeseries.FromExchangeRates(rates, currency)
+		last, ok := lastOnOrBefore(s, end)
+		if !ok || last.Date.Before(start) {
+			continue
+		}
+		first, _ := periodStart(s, start)
+
+		row := FXRow{
+			Currency:  currency,
+			StartDate: timeseries.FormatDate(first.Date),
+			Start:     first.Value,
+			EndDate:   timeseries.FormatDate(last.Date),
+			End:       last.Value,
+			Change:    last.Value - first.Value,
+			Min:       math.Inf(1),
+			Max:       math.Inf(-1),
+		}
+		if first.Value != 0 {
+			row.ChangePct = row.Change / first.Value * 100
+		}
+		for _, p := range s {
+			if p.Date.Before(start) || p.Date.After(end) {
+				continue
+			}
+			row.Min = math.Min(row.Min, p.Value)
+			row.Max = math.Max(row.Max, p.Value)
+		}
+		rows = append(rows, row)
+	}
+	return rows
+}
+
+// topNews 影響度スコアの高い順（同点なら感情スコアの絶対値が大きい順）に上位n件
+func topNews(news []*detect.AnalyzedNews, n int) []NewsItem {
+	sorted := append([]*detect.AnalyzedNews(nil), news...)
+	sort.SliceStable(sorted, func(i, j int) bool {
+		if sorted[i].ImpactScore != sorted[j].ImpactScore {
+			return sorted[i].ImpactScore > sorted[j].ImpactScore
+		}
+		return math.Abs(sorted[i].SentimentScore) > math.Abs(sorted[j].SentimentScore)
+	})
+	if len(sorted) > n {
+		sorted = sorted[:n]
+	}
+
+	items := make([]NewsItem, 0, len(sorted))
+	for _, a := range sorted {
+		date := a.Date
+		if len(date) > len(timeseries.DateLayout) {
+			date = date[:len(timeseries.DateLayout)]
+		}
+		items = append(items, NewsItem{
+			Date:           date,
+			Title:          a.Title,
+			Summary:        a.SummaryText(),
+			URL:            a.URL,
+			Source:         a.Source,
+			Sentiment:      a.Sentiment,
+			SentimentScore: a.SentimentScore,
+			ImpactScore:    a.ImpactScore,
+			Direction:      a.Direction,
+		})
+	}
+	return items
+}
+
+// alerts しきい値を超えた価格変動と、期間内の統計的異常検知イベント
+func alerts(changes []*model.PriceChangeRecord, prices []*model.GasPrice, rates []*model.ExchangeRate, start, end time.Time) []Alert {
+	var list []Alert
+	for _, c := range changes {
+		list = append(list, Alert{
+			Date:    c.DateNew,
+			Kind:    "price_change",
+			Series:  c.Region,
+			Message: fmt.Sprintf("%s: %.2f円 → %.2f円（%+.2f%%、%s比）", c.Region, c.PriceOld, c.PriceNew, c.PctChange, c.DateOld),
+		})
+	}
+
+	series, _ := detect.LoadAnomalySeries(staticSource{prices, rates})
+	from, to := timeseries.FormatDate(start), timeseries.FormatDate(end)
+	for _, e := range detect.DetectAnomalies(series, detect.DefaultAnomalyConfig()) {
+		if e.Date < from || e.Date > to {
+			continue
+		}
+		list = append(list, Alert{
+			Date:    e.Date,
+			Kind:    string(e.Method),
+			Series:  e.Series,
+			Message: anomalyMessage(e),
+		})
+	}
+
+	sort.SliceStable(list, func(i, j int) bool { return list[i].Date < list[j].Date })
+	return list
+}
+
+// anomalyMessage 検知イベントの説明文
+func anomalyMessage(e detect.DetectionEvent) string {
+	switch e.Method {
+	case detect.MethodZScore:
+		return fmt.Sprintf("%s: %.2f（z=%+.2f、基準平均 %.2f）", e.Series, e.Value, e.Score, e.Baseline.Mean)
+	case detect.MethodEWMA:
+		return fmt.Sprintf("%s: %.2f（EWMA %.2f が管理限界 %.2f〜%.2f を逸脱）", e.Series, e.Value, e.Score, e.Baseline.Lower, e.Baseline.Upper)
+	case detect.MethodStreak:
+		dir := map[string]string{"up": "上昇", "down": "下落"}[e.Direction]
+		return fmt.Sprintf("%s: %.0f回連続%s（%.2f → %.2f）", e.Series, e.Score, dir, e.Baseline.Mean, e.Value)
+	}
+	return e.String()
+}
+
+// staticSource 取得済みのデータを異常検知に渡す
+type staticSource struct {
+	prices []*model.GasPrice
+	rates  []*model.ExchangeRate
+}
+
+func (s staticSource) GetAllGasPrices() ([]*model.GasPrice, error)         { return s.prices, nil }
+func (s staticSource) GetAllExchangeRates() ([]*model.ExchangeRate, error) { return s.rates, nil }
+
+// lastOnOrBefore 指定日以前で最後の点
+func lastOnOrBefore(s timeseries.Series, t time.Time) (timeseries.Point, bool) {
+	for i := len(s) - 1; i >= 0; i-- {
+		if !s[i].Date.After(t) {
+			return s[i], true
+		}
+	}
+	return timeseries.Point{}, false
+}
+
+// periodStart 期間の基準点（開始日より前で直近、なければ期間内の最初の点）
+func periodStart(s timeseries.Series, start time.Time) (timeseries.Point, bool) {
+	if p, ok := lastOnOrBefore(s, start.AddDate(0, 0, -1)); ok {
+		return p, true
+	}
+	for _, p := range s {
+		if !p.Date.Before(start) {
+			return p, true
+		}
+	}
+	return timeseries.Point{}, false
+}
+
+func ptr(v float64) *float64 { return &v }
@@ -0,0 +1,51 @@
+package report
+
+import (
+	"testing"
+
+	"gasinsight/internal/database"
+	model "gasinsight/internal/model"
+)
+
+func newStore(t *testing.T) *database.SQLiteClient {
+	t.Helper()
+	s, err := database.NewSQLiteClient(":memory:")
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { s.Close() })
+	return s
+}
+
+// TestBuildWeekAgoReference 前週比は最新日の7〜14日前の点だけを基準にする
+func TestBuildWeekAgoReference(t *testing.T) {
+	s := newStore(t)
+	for _, p := range []*model.GasPrice{
+		// 全国平均は3週間あいている
+		model.NewGasPrice("2025-01-06", "全国平均", 170, 181, 153),
+		model.NewGasPrice("2025-01-27", "全国平均", 175, 186, 158),
+		model.NewGasPrice("2025-01-20", "東京都", 176, 187, 159),
+		model.NewGasPrice("2025-01-27", "東京都", 178, 189, 161),
+	} {
+		if _, err := s.SaveGasPrice(p); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	d, err := Build(s, "2025-01-27", "2025-01-27", Options{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	rows := map[string]PriceRow{}
+	for _, r := range d.Prices {
+		if r.Fuel == "regular" {
+			rows[r.Region] = r
+		}
+	}
+	if r := rows["全国平均"]; r.WoWChange != nil || r.WeekAgoDate != "" {
+		t.Errorf("全国平均の前週比 = %s %v, want なし", r.WeekAgoDate, r.WoWChange)
+	}
+	if r := rows["東京都"]; r.WoWChange == nil || *r.WoWChange != 2 || r.WeekAgoDate != "2025-01-20" {
+		t.Errorf("東京都の前週比 = %s %v, want 2025-01-20 +2", r.WeekAgoDate, r.WoWChange)
+	}
+}
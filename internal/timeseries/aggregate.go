@@ -0,0 +1,109 @@
+package timeseries
+
+import (
+	"fmt"
+	"math"
+	"time"
+)
+
+// Period 集計の単位
+type Period string
+
+const (
+	PeriodWeek  Period = "week"  // ISO週（月曜始まり）
+	PeriodMonth Period = "month" // 暦月
+)
+
+// ParsePeriod 集計単位の文字列（week/month）を解釈
+func ParsePeriod(s string) (Period, error) {
+	switch Period(s) {
+	case PeriodWeek, PeriodMonth:
+		return Period(s), nil
+	}
+	return "", fmt.Errorf("不明な集計単位: %s（week/month）", s)
+}
+
+// Start tを含む期間の開始日（週は月曜日、月は1日。JST）
+func (p Period) Start(t time.Time) time.Time {
+	if p == PeriodMonth {
+		t = t.In(JST)
+		return time.Date(t.Year(), t.Month(), 1, 0, 0, 0, 0, JST)
+	}
+	return WeekStart(t)
+}
+
+// End 開始日startの期間の最終日
+func (p Period) End(start time.Time) time.Time {
+	if p == PeriodMonth {
+		return start.AddDate(0, 1, -1)
+	}
+	return start.AddDate(0, 0, 6)
+}
+
+// Label 期間の表示（週は2025-W45のようなISO週番号、月は2025-11）
+func (p Period) Label(start time.Time) string {
+	if p == PeriodMonth {
+		return start.In(JST).Format("2006-01")
+	}
+	year, week := start.In(JST).ISOWeek()
+	return fmt.Sprintf("%d-W%02d", year, week)
+}
+
+// Stats 期間ごとの集計値
+type Stats struct {
+	Period     string  `json:"period"` // 2025-W45 / 2025-11
+	Start      string  `json:"start"`  // 期間の開始日
+	End        string  `json:"end"`    // 期間の最終日
+	Count      int     `json:"count"`  // データ点数
+	First      float64 `json:"first"`  // 期間内の最初の値
+	Last       float64 `json:"last"`   // 期間内の最後の値
+	Avg        float64 `json:"avg"`
+	Min        float64 `json:"min"`
+	Max        float64 `json:"max"`
+	StdDev     float64 `json:"stddev"`     // 値の標本標準偏差
+	Volatility float64 `json:"volatility"` // 前の点からの変化率（%）の標本標準偏差
+}
+
+// Aggregate 期間ごとに平均・最小・最大・ばらつきを集計（期間の昇順）
+// 変化率は系列全体で前の点と比較し、後の点が属する期間に含める
+func (s Series) Aggregate(p Period) []Stats {
+	var stats []Stats
+	var values, changes []float64
+	var start time.Time
+
+	flush := func() {
+		if len(values) == 0 {
+			return
+		}
+		st := Stats{
+			Period:     p.Label(start),
+			Start:      FormatDate(start),
+			End:        FormatDate(p.End(start)),
+			Count:      len(values),
+			First:      values[0],
+			Last:       values[len(values)-1],
+			Avg:        Mean(values),
+			Min:        math.Inf(1),
+			Max:        math.Inf(-1),
+			StdDev:     StdDev(values),
+			Volatility: StdDev(changes),
+		}
+		for _, v := range values {
+			st.Min, st.Max = math.Min(st.Min, v), math.Max(st.Max, v)
+		}
+		stats = append(stats, st)
+	}
+
+	for i, pt := range s {
+		if ps := p.Start(pt.Date); i == 0 || !ps.Equal(start) {
+			flush()
+			start, values, changes = ps, nil, nil
+		}
+		values = append(values, pt.Value)
+		if i > 0 && s[i-1].Value != 0 {
+			changes = append(changes, (pt.Value-s[i-1].Value)/s[i-1].Value*100)
+		}
+	}
+	flush()
+	return stats
+}
@@ -0,0 +1,220 @@
+package timeseries
+
+import (
+	"math"
+	"sort"
+	"time"
+
+	model "gasinsight/internal/model"
+)
+
+// JST 日本標準時（日付文字列はJSTとして解釈する）
+var JST = time.FixedZone("JST", 9*60*60)
+
+// DateLayout 日付文字列の形式
+const DateLayout = "2006-01-02"
+
+// Point 時系列の1点
+type Point struct {
+	Date  time.Time
+	Value float64
+}
+
+// Series 日付昇順の時系列
+type Series []Point
+
+// ParseDate YYYY-MM-DD形式の日付をJSTとして解釈（RFC3339形式も受け付ける）
+func ParseDate(s string) (time.Time, error) {
+	if len(s) > len(DateLayout) {
+		t, err := time.Parse(time.RFC3339, s)
+		if err == nil {
+			t = t.In(JST)
+			return time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, JST), nil
+		}
+		s = s[:len(DateLayout)]
+	}
+	return time.ParseInLocation(DateLayout, s, JST)
+}
+
+// FormatDate 日付をYYYY-MM-DD形式に変換
+func FormatDate(t time.Time) string {
+	return t.In(JST).Format(DateLayout)
+}
+
+// WeekStart ISO週の開始日（月曜日）を返す
+func WeekStart(t time.Time) time.Time {
+	t = t.In(JST)
+	offset := (int(t.Weekday()) + 6) % 7
+	return time.Date(t.Year(), t.Month(), t.Day()-offset, 0, 0, 0, 0, JST)
+}
+
+// Values 値のみを取り出す
+func (s Series) Values() []float64 {
+	values := make([]float64, len(s))
+	for i, p := range s {
+		values[i] = p.Value
+	}
+	return values
+}
+
+// Last 最後の点を返す
+func (s Series) Last() (Point, bool) {
+	if len(s) == 0 {
+		return Point{}, false
+	}
+	return s[len(s)-1], true
+}
+
+// Index 日付（YYYY-MM-DD）から値を引けるマップを返す
+func (s Series) Index() map[string]float64 {
+	m := make(map[string]float64, len(s))
+	for _, p := range s {
+		m[FormatDate(p.Date)] = p.Value
+	}
+	return m
+}
+
+// Weekly ISO週ごとの平均値に集約（日付は週の月曜日）
+func (s Series) Weekly() Series {
+	sums := map[time.Time]float64{}
+	counts := map[time.Time]int{}
+	for _, p := range s {
+		w := WeekStart(p.Date)
+		sums[w] += p.Value
+		counts[w]++
+	}
+
+	weekly := make(Series, 0, len(sums))
+	for w, sum := range sums {
+		weekly = append(weekly, Point{Date: w, Value: sum / float64(counts[w])})
+	}
+	weekly.sort()
+	return weekly
+}
+
+// Diff 前の点からの差分系列
+func (s Series) Diff() Series {
+	if len(s) < 2 {
+		return nil
+	}
+	diff := make(Series, 0, len(s)-1)
+	for i := 1; i < len(s); i++ {
+		diff = append(diff, Point{Date: s[i].Date, Value: s[i].Value - s[i-1].Value})
+	}
+	return diff
+}
+
+func (s Series) sort() {
+	sort.Slice(s, func(i, j int) bool { return s[i].Date.Before(s[j].Date) })
+}
+
+// fromMap 日付→値のマップから系列を作成
+func fromMap(m map[string]float64) Series {
+	s := make(Series, 0, len(m))
+	for date, v := range m {
+		t, err := ParseDate(date)
+		if err != nil {
+			continue
+		}
+		s = append(s, Point{Date: t, Value: v})
+	}
+	s.sort()
+	return s
+}
+
+// FromGasPrices ガソリン価格から燃料種別・地域の系列を作成（regionが空なら全地域）
+func FromGasPrices(prices []*model.GasPrice, fuel, region string) Series {
+	m := map[string]float64{}
+	for _, p := range prices {
+		if region != "" && p.Region != region {
+			continue
+		}
+		var v float64
+		switch fuel {
+		case "premium":
+			v = p.PremiumPrice
+		case "diesel":
+			v = p.DieselPrice
+		default:
+			v = p.RegularPrice
+		}
+		if v > 0 {
+			m[p.Date] = v
+		}
+	}
+	return fromMap(m)
+}
+
+// FromExchangeRates 為替レートから通貨ごとの系列を作成（USD/EUR/GBP/CNY）
+func FromExchangeRates(rates []*model.ExchangeRate, currency string) Series {
+	m := map[string]float64{}
+	for _, r := range rates {
+		var v float64
+		switch currency {
+		case "EUR":
+			v = r.EURJPY
+		case "GBP":
+			v = r.GBPJPY
+		case "CNY":
+			v = r.CNYJPY
+		default:
+			v = r.USDJPY
+		}
+		if v > 0 {
+			m[r.Date] = v
+		}
+	}
+	return fromMap(m)
+}
+
+// FromCrudePrices 原油価格（ドル/バレル）の系列を作成
+func FromCrudePrices(prices []*model.CrudePrice) Series {
+	m := map[string]float64{}
+	for _, p := range prices {
+		if p.USDPerBarrel > 0 {
+			m[p.Date] = p.USDPerBarrel
+		}
+	}
+	return fromMap(m)
+}
+
+// CrudeYen 原油価格を円建て（円/L）に換算。各日付で直近のUSD/JPYを使用
+func CrudeYen(crudeUSD, usdJpy Series) Series {
+	var out Series
+	j := -1
+	for _, p := range crudeUSD {
+		for j+1 < len(usdJpy) && !usdJpy[j+1].Date.After(p.Date) {
+			j++
+		}
+		if j < 0 {
+			continue
+		}
+		out = append(out, Point{Date: p.Date, Value: p.Value * usdJpy[j].Value / model.LitersPerBarrel})
+	}
+	return out
+}
+
+// Mean 平均値
+func Mean(values []float64) float64 {
+	if len(values) == 0 {
+		return 0
+	}
+	var sum float64
+	for _, v := range values {
+		sum += v
+	}
+	return sum / float64(len(values))
+}
+
+// StdDev 標本標準偏差
+func StdDev(values []float64) float64 {
+	if len(values) < 2 {
+		return 0
+	}
+	mean := Mean(values)
+	var ss float64
+	for _, v := range values {
+		ss += (v - mean) * (v - mean)
+	}
+	return math.Sqrt(ss / float64(len(values)-1))
+}
@@ -0,0 +1,130 @@
+package transfer
+
+import (
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"io"
+	"reflect"
+	"strconv"
+	"strings"
+)
+
+// columns 行の型の列名（jsonタグ）とフィールド番号
+func columns(t reflect.Type) ([]string, map[string]int) {
+	names := make([]string, t.NumField())
+	index := make(map[string]int, t.NumField())
+	for i := 0; i < t.NumField(); i++ {
+		name, _, _ := strings.Cut(t.Field(i).Tag.Get("json"), ",")
+		names[i] = name
+		index[name] = i
+	}
+	return names, index
+}
+
+// writeCSV ヘッダー行（列名）付きのCSVを書き出す
+func writeCSV[R any](w io.Writer, rows []R) error {
+	names, _ := columns(reflect.TypeOf((*R)(nil)).Elem())
+	cw := csv.NewWriter(w)
+	if err := cw.Write(names); err != nil {
+		return err
+	}
+	record := make([]string, len(names))
+	for _, r := range rows {
+		v := reflect.ValueOf(r)
+		for i := range record {
+			record[i] = formatField(v.Field(i))
+		}
+		if err := cw.Write(record); err != nil {
+			return err
+		}
+	}
+	cw.Flush()
+	return cw.Error()
+}
+
+// readCSV ヘッダー行の列名で対応付けて読み込む（ない列はゼロ値、知らない列はエラー）
+func readCSV[R any](r io.Reader) ([]R, error) {
+	cr := csv.NewReader(r)
+	header, err := cr.Read()
+	if errors.Is(err, io.EOF) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	_, index := columns(reflect.TypeOf((*R)(nil)).Elem())
+	fields := make([]int, len(header))
+	for i, name := range header {
+		name = strings.TrimPrefix(strings.TrimSpace(name), "\ufeff")
+		f, ok := index[name]
+		if !ok {
+			return nil, fmt.Errorf("不明な列: %s", name)
+		}
+		fields[i] = f
+	}
+
+	var rows []R
+	for line := 2; ; line++ {
+		record, err := cr.Read()
+		if errors.Is(err, io.EOF) {
+			return rows, nil
+		}
+		if err != nil {
+			return nil, err
+		}
+		var row R
+		v := reflect.ValueOf(&row).Elem()
+		for i, s := range record {
+			if err := parseField(v.Field(fields[i]), s); err != nil {
+				return nil, fmt.Errorf("%d行目 %s: %w", line, header[i], err)
+			}
+		}
+		rows = append(rows, row)
+	}
+}
+
+func formatField(v reflect.Value) string {
+	switch v.Kind() {
+	case reflect.Float64:
+		return strconv.FormatFloat(v.Float(), 'f', -1, 64)
+	case reflect.Int, reflect.Int64:
+		return strconv.FormatInt(v.Int(), 10)
+	case reflect.Bool:
+		return strconv.FormatBool(v.Bool())
+	default:
+		return v.String()
+	}
+}
+
+func parseField(v reflect.Value, s string) error {
+	if v.Kind() != reflect.String {
+		s = strings.TrimSpace(s)
+		if s == "" {
+			return nil
+		}
+	}
+	switch v.Kind() {
+	case reflect.Float64:
+		f, err := strconv.ParseFloat(s, 64)
+		if err != nil {
+			return err
+		}
+		v.SetFloat(f)
+	case reflect.Int, reflect.Int64:
+		n, err := strconv.ParseInt(s, 10, 64)
+		if err != nil {
+			return err
+		}
+		v.SetInt(n)
+	case reflect.Bool:
+		b, err := strconv.ParseBool(s)
+		if err != nil {
+			return err
+		}
+		v.SetBool(b)
+	default:
+		v.SetString(s)
+	}
+	return nil
+}
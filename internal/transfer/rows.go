@@ -0,0 +1,169 @@
+package transfer
+
+import (
+	"time"
+
+	"gasinsight/internal/database"
+	"gasinsight/internal/detect"
+	model "gasinsight/internal/model"
+
+	"github.com/google/uuid"
+)
+
+// ファイル上の1行（列名はCSV/JSON Lines/Parquetで共通）
+
+type gasPriceRow struct {
+	ID           string  `json:"id" parquet:"id"`
+	Date         string  `json:"date" parquet:"date"`
+	RegularPrice float64 `json:"regular_price" parquet:"regular_price"`
+	PremiumPrice float64 `json:"premium_price" parquet:"premium_price"`
+	DieselPrice  float64 `json:"diesel_price" parquet:"diesel_price"`
+	Region       string  `json:"region" parquet:"region"`
+	Source       string  `json:"source" parquet:"source"`
+	CreatedAt    int64   `json:"created_at" parquet:"created_at"`
+	UpdatedAt    int64   `json:"updated_at" parquet:"updated_at"`
+}
+
+type exchangeRateRow struct {
+	ID        string  `json:"id" parquet:"id"`
+	Date      string  `json:"date" parquet:"date"`
+	USDJPY    float64 `json:"usd_jpy" parquet:"usd_jpy"`
+	EURJPY    float64 `json:"eur_jpy" parquet:"eur_jpy"`
+	GBPJPY    float64 `json:"gbp_jpy" parquet:"gbp_jpy"`
+	CNYJPY    float64 `json:"cny_jpy" parquet:"cny_jpy"`
+	Source    string  `json:"source" parquet:"source"`
+	CreatedAt int64   `json:"created_at" parquet:"created_at"`
+	UpdatedAt int64   `json:"updated_at" parquet:"updated_at"`
+}
+
+type newsRow struct {
+	ID             string  `json:"id" parquet:"id"`
+	Date           string  `json:"date" parquet:"date"`
+	Title          string  `json:"title" parquet:"title"`
+	Summary        string  `json:"summary" parquet:"summary"`
+	Sentiment      string  `json:"sentiment" parquet:"sentiment"`
+	SentimentScore float64 `json:"sentiment_score" parquet:"sentiment_score"`
+	ImpactScore    float64 `json:"impact_score" parquet:"impact_score"`
+	Direction      string  `json:"direction" parquet:"direction"`
+	Confidence     float64 `json:"confidence" parquet:"confidence"`
+	URL            string  `json:"url" parquet:"url"`
+	Source         string  `json:"source" parquet:"source"`
+	Language       string  `json:"language" parquet:"language"`
+	Model          string  `json:"model" parquet:"model"`
+	PromptVersion  string  `json:"prompt_version" parquet:"prompt_version"`
+	CreatedAt      int64   `json:"created_at" parquet:"created_at"`
+}
+
+type priceChangeRow struct {
+	ID        int64   `json:"id" parquet:"id"`
+	Region    string  `json:"region" parquet:"region"`
+	DateNew   string  `json:"date_new" parquet:"date_new"`
+	PriceNew  float64 `json:"price_new" parquet:"price_new"`
+	DateOld   string  `json:"date_old" parquet:"date_old"`
+	PriceOld  float64 `json:"price_old" parquet:"price_old"`
+	PctChange float64 `json:"pct_change" parquet:"pct_change"`
+	Flagged   bool    `json:"flagged" parquet:"flagged"`
+	CreatedAt string  `json:"created_at" parquet:"created_at"`
+}
+
+var gasPrices = dataset[gasPriceRow]{
+	load: func(s database.Store, q database.ListQuery) ([]gasPriceRow, error) {
+		prices, _, err := s.ListGasPrices(q)
+		rows := make([]gasPriceRow, len(prices))
+		for i, p := range prices {
+			rows[i] = gasPriceRow(*p)
+		}
+		return rows, err
+	},
+	save: func(s database.Store, r gasPriceRow) (bool, error) {
+		if r.Date == "" {
+			return false, errMissing("date")
+		}
+		// IDは保存時に日付・地域・データソースから決まる
+		r.CreatedAt, r.UpdatedAt = timestamps(r.CreatedAt, r.UpdatedAt)
+		p := model.GasPrice(r)
+		return s.SaveGasPrice(&p)
+	},
+}
+
+var exchangeRates = dataset[exchangeRateRow]{
+	load: func(s database.Store, q database.ListQuery) ([]exchangeRateRow, error) {
+		rates, _, err := s.ListExchangeRates(q)
+		rows := make([]exchangeRateRow, len(rates))
+		for i, r := range rates {
+			rows[i] = exchangeRateRow(*r)
+		}
+		return rows, err
+	},
+	save: func(s database.Store, r exchangeRateRow) (bool, error) {
+		if r.Date == "" {
+			return false, errMissing("date")
+		}
+		r.CreatedAt, r.UpdatedAt = timestamps(r.CreatedAt, r.UpdatedAt)
+		rate := model.ExchangeRate(r)
+		return s.SaveExchangeRate(&rate)
+	},
+}
+
+var news = dataset[newsRow]{
+	load: func(s database.Store, q database.ListQuery) ([]newsRow, error) {
+		list, _, err := s.ListNews(q)
+		rows := make([]newsRow, len(list))
+		for i, n := range list {
+			rows[i] = newsRow{
+				ID: n.ID, Date: n.Date, Title: n.Title, Summary: n.Summary,
+				Sentiment: string(n.Sentiment), SentimentScore: n.SentimentScore, ImpactScore: n.ImpactScore,
+				Direction: string(n.Direction), Confidence: n.Confidence, URL: n.URL, Source: n.Source,
+				Language: n.Language, Model: n.Model, PromptVersion: n.PromptVersion, CreatedAt: n.CreatedAt,
+			}
+		}
+		return rows, err
+	},
+	save: func(s database.Store, r newsRow) (bool, error) {
+		if r.URL == "" && r.ID == "" {
+			return false, errMissing("id/url")
+		}
+		// IDがなければURLから決まったIDを作り、同じ記事を何度取り込んでも1件にする
+		if r.ID == "" {
+			r.ID = uuid.NewSHA1(uuid.NameSpaceURL, []byte(r.URL)).String()
+		}
+		n := &detect.AnalyzedNews{
+			ID: r.ID, Date: r.Date, Title: r.Title, Summary: r.Summary,
+			Sentiment: detect.NormalizeSentiment(r.Sentiment), SentimentScore: r.SentimentScore, ImpactScore: r.ImpactScore,
+			Direction: detect.NormalizeDirection(r.Direction), Confidence: r.Confidence, URL: r.URL, Source: r.Source,
+			Language: r.Language, Model: r.Model, PromptVersion: r.PromptVersion, CreatedAt: r.CreatedAt,
+		}
+		return true, s.UpsertNews(n)
+	},
+}
+
+var priceChanges = dataset[priceChangeRow]{
+	load: func(s database.Store, q database.ListQuery) ([]priceChangeRow, error) {
+		changes, _, err := s.ListPriceChanges(q)
+		rows := make([]priceChangeRow, len(changes))
+		for i, c := range changes {
+			rows[i] = priceChangeRow(*c)
+		}
+		return rows, err
+	},
+	save: func(s database.Store, r priceChangeRow) (bool, error) {
+		if r.Region == "" || r.DateNew == "" || r.DateOld == "" {
+			return false, errMissing("region/date_new/date_old")
+		}
+		// IDは取り込み元のDBの採番なので使わず、地域・日付の組み合わせで追加・更新する
+		c := model.PriceChangeRecord(r)
+		return s.UpsertPriceChange(&c)
+	},
+}
+
+// timestamps 作成・更新日時が空なら現在時刻にする
+func timestamps(createdAt, updatedAt int64) (int64, int64) {
+	now := time.Now().Unix()
+	if createdAt == 0 {
+		createdAt = now
+	}
+	if updatedAt == 0 {
+		updatedAt = createdAt
+	}
+	return createdAt, updatedAt
+}
@@ -0,0 +1,206 @@
+// Package transfer ガソリン価格・為替・ニュース・価格変動をCSV / JSON Lines / Parquetで書き出し・取り込みする
+package transfer
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"path/filepath"
+	"strings"
+
+	"gasinsight/internal/database"
+
+	"github.com/parquet-go/parquet-go"
+)
+
+// 対応するファイル形式
+const (
+	FormatCSV     = "csv"
+	FormatJSONL   = "jsonl"
+	FormatParquet = "parquet"
+)
+
+// Datasets 書き出し・取り込みできるデータ
+var Datasets = []string{"gas-prices", "exchange-rates", "news", "price-changes"}
+
+// table データごとの書き出し・取り込み
+type table interface {
+	export(s database.Store, q database.ListQuery, format string, w io.Writer) (int, error)
+	importRows(s database.Store, format string, data []byte) (Result, error)
+}
+
+// dataset 行の型Rでのデータの読み書き
+type dataset[R any] struct {
+	load func(s database.Store, q database.ListQuery) ([]R, error)
+	save func(s database.Store, r R) (bool, error) // 追加・更新したらtrue（記録済みの内容と同じ場合はfalse）
+}
+
+func lookup(name string) (table, error) {
+	switch name {
+	case "gas-prices":
+		return gasPrices, nil
+	case "exchange-rates":
+		return exchangeRates, nil
+	case "news":
+		return news, nil
+	case "price-changes":
+		return priceChanges, nil
+	}
+	return nil, fmt.Errorf("不明なデータ: %s（%s）", name, strings.Join(Datasets, "/"))
+}
+
+// FormatFromPath 拡張子からファイル形式を判定（不明なら空）
+func FormatFromPath(path string) string {
+	switch strings.ToLower(filepath.Ext(path)) {
+	case ".csv":
+		return FormatCSV
+	case ".jsonl", ".ndjson":
+		return FormatJSONL
+	case ".parquet":
+		return FormatParquet
+	}
+	return ""
+}
+
+func checkFormat(format string) error {
+	switch format {
+	case FormatCSV, FormatJSONL, FormatParquet:
+		return nil
+	}
+	return fmt.Errorf("不明なファイル形式: %s（csv/jsonl/parquet）", format)
+}
+
+// Export データを期間（from〜to、YYYY-MM-DD、空なら制限なし）で絞り込み、日付の昇順で書き出す。書き出した件数を返す
+func Export(s database.Store, name, format, from, to string, w io.Writer) (int, error) {
+	t, err := lookup(name)
+	if err != nil {
+		return 0, err
+	}
+	if err := checkFormat(format); err != nil {
+		return 0, err
+	}
+	return t.export(s, database.ListQuery{From: from, To: to, Sort: "date"}, format, w)
+}
+
+// Result 取り込み結果
+type Result struct {
+	Rows     int // ファイルの行数
+	Imported int // 追加・更新した行数
+	Skipped  int // 記録済みの内容と同じため変更しなかった行数
+}
+
+// Import ファイルの行を1つのトランザクションで取り込む
+// 記録済みの行（ガソリン価格・為替・ニュースは同じID、価格変動は同じ地域・日付）は上書きするため、
+// 同じファイルを何度取り込んでも結果は変わらない
+func Import(ctx context.Context, s database.Store, name, format string, r io.Reader) (Result, error) {
+	t, err := lookup(name)
+	if err != nil {
+		return Result{}, err
+	}
+	if err := checkFormat(format); err != nil {
+		return Result{}, err
+	}
+	data, err := io.ReadAll(r)
+	if err != nil {
+		return Result{}, fmt.Errorf("読み込みエラー: %w", err)
+	}
+
+	var res Result
+	err = s.WithTx(ctx, func(tx database.Store) error {
+		res, err = t.importRows(tx, format, data)
+		return err
+	})
+	return res, err
+}
+
+func (d dataset[R]) export(s database.Store, q database.ListQuery, format string, w io.Writer) (int, error) {
+	rows, err := d.load(s, q)
+	if err != nil {
+		return 0, err
+	}
+	if err := encode(w, format, rows); err != nil {
+		return 0, fmt.Errorf("書き出しエラー: %w", err)
+	}
+	return len(rows), nil
+}
+
+func (d dataset[R]) importRows(s database.Store, format string, data []byte) (Result, error) {
+	rows, err := decode[R](format, data)
+	if err != nil {
+		return Result{}, fmt.Errorf("読み込みエラー: %w", err)
+	}
+	res := Result{Rows: len(rows)}
+	for i, r := range rows {
+		ok, err := d.save(s, r)
+		if err != nil {
+			return res, fmt.Errorf("%d行目の取り込みエラー: %w", i+1, err)
+		}
+		if ok {
+			res.Imported++
+		} else {
+			res.Skipped++
+		}
+	}
+	return res, nil
+}
+
+func encode[R any](w io.Writer, format string, rows []R) error {
+	switch format {
+	case FormatCSV:
+		return writeCSV(w, rows)
+	case FormatJSONL:
+		bw := bufio.NewWriter(w)
+		enc := json.NewEncoder(bw)
+		enc.SetEscapeHTML(false)
+		for _, r := range rows {
+			if err := enc.Encode(r); err != nil {
+				return err
+			}
+		}
+		return bw.Flush()
+	default:
+		pw := parquet.NewGenericWriter[R](w)
+		if _, err := pw.Write(rows); err != nil {
+			return err
+		}
+		return pw.Close()
+	}
+}
+
+func decode[R any](format string, data []byte) ([]R, error) {
+	switch format {
+	case FormatCSV:
+		return readCSV[R](bytes.NewReader(data))
+	case FormatJSONL:
+		var rows []R
+		dec := json.NewDecoder(bytes.NewReader(data))
+		// 別のデータのファイルを取り込まないよう、知らない列はエラーにする
+		dec.DisallowUnknownFields()
+		for {
+			var r R
+			if err := dec.Decode(&r); errors.Is(err, io.EOF) {
+				return rows, nil
+			} else if err != nil {
+				return nil, fmt.Errorf("%d行目: %w", len(rows)+1, err)
+			}
+			rows = append(rows, r)
+		}
+	default:
+		pr := parquet.NewGenericReader[R](bytes.NewReader(data))
+		defer pr.Close()
+		rows := make([]R, pr.NumRows())
+		n, err := pr.Read(rows)
+		if err != nil && !errors.Is(err, io.EOF) {
+			return nil, err
+		}
+		return rows[:n], nil
+	}
+}
+
+func errMissing(column string) error {
+	return fmt.Errorf("必須の列がありません: %s", column)
+}
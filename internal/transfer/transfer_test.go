@@ -0,0 +1,147 @@
+package transfer
+
+import (
+	"bytes"
+	"context"
+	"testing"
+
+	"gasinsight/internal/database"
+	"gasinsight/internal/detect"
+	model "gasinsight/internal/model"
+)
+
+func newStore(t *testing.T) *database.SQLiteClient {
+	t.Helper()
+	s, err := database.NewSQLiteClient(":memory:")
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { s.Close() })
+	return s
+}
+
+func must(t *testing.T, err error) {
+	t.Helper()
+	if err != nil {
+		t.Fatal(err)
+	}
+}
+
+// seed 全てのデータを2件ずつ保存する
+func seed(t *testing.T, s database.Store) {
+	t.Helper()
+	for _, p := range []*model.GasPrice{
+		model.NewGasPrice("2025-01-06", "全国平均", 175.2, 186.3, 158.1),
+		model.NewGasPrice("2025-01-13", "東京都", 176.8, 187.9, 159.4),
+	} {
+		_, err := s.SaveGasPrice(p)
+		must(t, err)
+	}
+	for _, r := range []*model.ExchangeRate{
+		model.NewExchangeRate("2025-01-06", 157.2, 162.8, 196.1, 21.5),
+		model.NewExchangeRate("2025-01-07", 158.0, 163.1, 197.0, 21.6),
+	} {
+		_, err := s.SaveExchangeRate(r)
+		must(t, err)
+	}
+	for _, n := range []*detect.AnalyzedNews{
+		{Title: "原油価格が上昇, 中東情勢", Summary: "改行を含む\n要約", Sentiment: detect.SentimentNegative,
+			SentimentScore: -0.6, ImpactScore: 0.8, Direction: detect.DirectionUp, Confidence: 0.9,
+			URL: "https://example.com/oil", Date: "2025-01-10", Source: "rss", Language: "ja"},
+		{Title: "円高が進行", Sentiment: detect.SentimentPositive, Direction: detect.DirectionDown,
+			URL: "https://example.com/yen", Date: "2025-01-12", Source: "newsapi", Language: "ja"},
+	} {
+		must(t, s.SaveNews(n))
+	}
+	for _, c := range []*model.PriceChangeRecord{
+		{Region: "全国平均", DateNew: "2025-01-13", PriceNew: 176.8, DateOld: "2025-01-06", PriceOld: 175.2, PctChange: 0.91},
+		{Region: "東京都", DateNew: "2025-01-20", PriceNew: 181.0, DateOld: "2025-01-13", PriceOld: 176.8, PctChange: 2.38, Flagged: true},
+	} {
+		must(t, s.SavePriceChange(c))
+	}
+}
+
+// snapshot データを書き出した内容（IDを含む）。同じ内容を持つDBは同じ結果になる
+func snapshot(t *testing.T, s database.Store, name string) string {
+	t.Helper()
+	var buf bytes.Buffer
+	_, err := Export(s, name, FormatJSONL, "", "", &buf)
+	must(t, err)
+	return buf.String()
+}
+
+// TestRoundTrip 書き出したファイルを空のDBに取り込むと同じ内容になり、もう一度取り込んでも変更しない
+func TestRoundTrip(t *testing.T) {
+	src := newStore(t)
+	seed(t, src)
+
+	for _, format := range []string{FormatCSV, FormatJSONL, FormatParquet} {
+		t.Run(format, func(t *testing.T) {
+			dst := newStore(t)
+			for _, name := range Datasets {
+				var buf bytes.Buffer
+				n, err := Export(src, name, format, "", "", &buf)
+				must(t, err)
+				if n != 2 {
+					t.Fatalf("%sの書き出し = %d件, want 2", name, n)
+				}
+				file := buf.Bytes()
+
+				res, err := Import(context.Background(), dst, name, format, bytes.NewReader(file))
+				must(t, err)
+				if res != (Result{Rows: 2, Imported: 2}) {
+					t.Errorf("%sの取り込み = %+v", name, res)
+				}
+				if got, want := snapshot(t, dst, name), snapshot(t, src, name); got != want {
+					t.Errorf("%sの取り込み後の内容\n got: %s\nwant: %s", name, got, want)
+				}
+
+				res, err = Import(context.Background(), dst, name, format, bytes.NewReader(file))
+				must(t, err)
+				// ニュースは内容を比べずに上書きするため、追加・更新として数える
+				want := Result{Rows: 2, Skipped: 2}
+				if name == "news" {
+					want = Result{Rows: 2, Imported: 2}
+				}
+				if res != want {
+					t.Errorf("%sの再取り込み = %+v, want %+v", name, res, want)
+				}
+			}
+		})
+	}
+}
+
+// TestImportPriceChangesIgnoresForeignIDs 取り込み元のIDが既存の記録と重なっても上書きしない
+func TestImportPriceChangesIgnoresForeignIDs(t *testing.T) {
+	src := newStore(t)
+	seed(t, src)
+	var buf bytes.Buffer
+	_, err := Export(src, "price-changes", FormatJSONL, "", "", &buf)
+	must(t, err)
+
+	dst := newStore(t)
+	local := &model.PriceChangeRecord{Region: "大阪府", DateNew: "2025-01-13", PriceNew: 174, DateOld: "2025-01-06", PriceOld: 173, PctChange: 0.58}
+	must(t, dst.SavePriceChange(local))
+
+	res, err := Import(context.Background(), dst, "price-changes", FormatJSONL, &buf)
+	must(t, err)
+	if res.Imported != 2 {
+		t.Errorf("取り込み = %+v, want 2件追加", res)
+	}
+
+	changes, _, err := dst.ListPriceChanges(database.ListQuery{Sort: "date"})
+	must(t, err)
+	if len(changes) != 3 {
+		t.Fatalf("変動記録 = %d件, want 3", len(changes))
+	}
+	ids := map[int64]bool{}
+	for _, c := range changes {
+		ids[c.ID] = true
+		if c.ID == local.ID && c.Region != "大阪府" {
+			t.Errorf("既存の記録が上書きされました: %+v", c)
+		}
+	}
+	if len(ids) != 3 {
+		t.Errorf("IDが重複しています: %v", ids)
+	}
+}